@@ -0,0 +1,37 @@
+package broker
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	KindMemory    = "memory"
+	KindNATS      = "nats"
+	KindKafkaREST = "kafka-rest"
+)
+
+// memoryCapacity is how many messages the memory broker keeps when it is
+// used outside of tests.
+const memoryCapacity = 1000
+
+// Broker publishes a message to topic. Key groups the messages of one
+// aggregate, brokers without keys ignore it.
+type Broker interface {
+	Publish(topic string, key string, body []byte) error
+}
+
+// New returns the broker of kind, url is the NATS server or the Kafka REST
+// proxy.
+func New(kind string, url string, timeout time.Duration) (Broker, error) {
+	switch kind {
+	case KindMemory, "":
+		return NewMemory(memoryCapacity), nil
+	case KindNATS:
+		return NewNATS(url, timeout), nil
+	case KindKafkaREST:
+		return &KafkaREST{URL: url, Client: &http.Client{Timeout: timeout}}, nil
+	}
+	return nil, fmt.Errorf("unknown broker: %s", kind)
+}
@@ -0,0 +1,72 @@
+package broker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// KafkaREST produces to Kafka through a Confluent compatible REST proxy.
+type KafkaREST struct {
+	URL    string
+	Client *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaResponse struct {
+	Offsets []struct {
+		Partition int    `json:"partition"`
+		Offset    int64  `json:"offset"`
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Publish implements Broker, body must be JSON.
+func (k *KafkaREST) Publish(topic string, key string, body []byte) error {
+	payload, err := json.Marshal(map[string][]kafkaRecord{
+		"records": {{Key: key, Value: body}},
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimSuffix(k.URL, "/") + "/topics/" + url.PathEscape(topic)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := k.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("kafka rest proxy returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	// Proxy menjawab 200 walaupun record gagal ditulis, errornya ada per offset
+	var result kafkaResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return err
+	}
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka rest proxy error %d: %s", *offset.ErrorCode, offset.Error)
+		}
+	}
+	return nil
+}
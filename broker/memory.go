@@ -0,0 +1,52 @@
+package broker
+
+import "sync"
+
+type Message struct {
+	Topic string
+	Key   string
+	Body  []byte
+}
+
+// Memory keeps the published messages in memory, it is meant for tests and
+// for running without a broker.
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+	capacity int
+	err      error
+}
+
+func NewMemory(capacity int) *Memory {
+	return &Memory{capacity: capacity}
+}
+
+// Publish implements Broker
+func (m *Memory) Publish(topic string, key string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.messages = append(m.messages, Message{Topic: topic, Key: key, Body: append([]byte(nil), body...)})
+	if m.capacity > 0 && len(m.messages) > m.capacity {
+		m.messages = m.messages[len(m.messages)-m.capacity:]
+	}
+	return nil
+}
+
+// Messages returns the kept messages, oldest first.
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// Fail makes every publish return err until it is called with nil.
+func (m *Memory) Fail(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
@@ -0,0 +1,109 @@
+package broker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATS publishes to a NATS server over its text protocol. Every publish is
+// followed by a PING, the PONG confirms the server has processed it.
+type NATS struct {
+	Address string
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATS accepts the address as host:port or nats://host:port.
+func NewNATS(url string, timeout time.Duration) *NATS {
+	return &NATS{
+		Address: strings.TrimPrefix(url, "nats://"),
+		Timeout: timeout,
+	}
+}
+
+// Publish implements Broker, the key is not used since NATS has no keys.
+func (n *NATS) Publish(topic string, key string, body []byte) error {
+	if topic == "" || strings.ContainsAny(topic, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject: %q", topic)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.conn == nil {
+		if err := n.connect(); err != nil {
+			return err
+		}
+	}
+
+	// Koneksi yang gagal dibuang, publish berikutnya membuka koneksi baru
+	if err := n.publish(topic, body); err != nil {
+		n.conn.Close()
+		n.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (n *NATS) connect() error {
+	conn, err := net.DialTimeout("tcp", n.Address, n.Timeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(n.Timeout))
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected NATS greeting: %s", strings.TrimSpace(line))
+	}
+
+	if _, err := conn.Write([]byte("CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"outbox-relay\"}\r\n")); err != nil {
+		conn.Close()
+		return err
+	}
+
+	n.conn = conn
+	n.reader = reader
+	return nil
+}
+
+func (n *NATS) publish(subject string, body []byte) error {
+	n.conn.SetDeadline(time.Now().Add(n.Timeout))
+
+	message := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(body), body)
+	if _, err := n.conn.Write([]byte(message)); err != nil {
+		return err
+	}
+
+	for {
+		line, err := n.reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := n.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New("nats: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package broker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/broker"
+)
+
+func TestMemoryKeepsLastMessages(t *testing.T) {
+	memory := broker.NewMemory(2)
+	for i := 1; i <= 3; i++ {
+		if err := memory.Publish("bank.transfer", fmt.Sprint(i), []byte(fmt.Sprint(i))); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+	}
+
+	messages := memory.Messages()
+	if len(messages) != 2 || messages[0].Key != "2" || messages[1].Key != "3" {
+		t.Errorf("expected the last 2 messages, got %+v", messages)
+	}
+
+	memory.Fail(errors.New("broker down"))
+	if err := memory.Publish("bank.transfer", "4", []byte("4")); err == nil {
+		t.Error("expected publish to fail")
+	}
+	if len(memory.Messages()) != 2 {
+		t.Error("expected a failed publish not to be kept")
+	}
+}
+
+// fakeNATS accepts one connection and answers like a NATS server, published
+// messages are sent to the returned channel as "subject payload".
+func fakeNATS(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	published := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) == 3 && fields[0] == "PUB":
+				var size int
+				fmt.Sscan(fields[2], &size)
+				payload := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, payload); err != nil {
+					return
+				}
+				if fields[1] == "forbidden" {
+					conn.Write([]byte("-ERR 'Permissions Violation for Publish to forbidden'\r\n"))
+					continue
+				}
+				published <- fields[1] + " " + string(payload[:size])
+			case len(fields) == 1 && fields[0] == "PING":
+				conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	return "nats://" + listener.Addr().String(), published
+}
+
+func TestNATSPublish(t *testing.T) {
+	url, published := fakeNATS(t)
+	nats := broker.NewNATS(url, time.Second)
+
+	if err := nats.Publish("bank.transfer", "1", []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if got := <-published; got != `bank.transfer {"id":1}` {
+		t.Errorf("unexpected message: %s", got)
+	}
+
+	if err := nats.Publish("forbidden", "1", []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Errorf("expected the server error, got %v", err)
+	}
+	if err := nats.Publish("bank transfer", "1", []byte(`{}`)); err == nil {
+		t.Error("expected a subject with a space to be rejected")
+	}
+}
+
+func TestKafkaRESTPublish(t *testing.T) {
+	var got map[string][]map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/topics/bank.user" {
+			w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"leader not available"}]}`))
+			return
+		}
+		if r.URL.Path != "/topics/bank.transfer" || r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":7,"error_code":null,"error":null}]}`))
+	}))
+	defer server.Close()
+
+	kafka := &broker.KafkaREST{URL: server.URL, Client: server.Client()}
+	if err := kafka.Publish("bank.transfer", "42", []byte(`{"id":42}`)); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if len(got["records"]) != 1 || string(got["records"][0]["key"]) != `"42"` || string(got["records"][0]["value"]) != `{"id":42}` {
+		t.Errorf("unexpected records: %+v", got)
+	}
+
+	if err := kafka.Publish("bank.user", "1", []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "50003") {
+		t.Errorf("expected the record error, got %v", err)
+	}
+	if err := kafka.Publish("bank.unknown", "1", []byte(`{}`)); err == nil {
+		t.Error("expected a 404 to fail")
+	}
+}
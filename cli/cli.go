@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/report"
+	"github.com/sferawann/test_mnc/usecase"
+	"github.com/sferawann/test_mnc/utils"
+)
+
+// App runs the back office commands of the service from the command line,
+// using the same usecases as the HTTP API.
+type App struct {
+	UserUsecase           usecase.UserUsecase
+	StatementUsecase      usecase.StatementUsecase
+	ReconciliationUsecase usecase.ReconciliationUsecase
+	InterestUsecase       usecase.InterestUsecase
+	Stdout                io.Writer
+}
+
+func (a *App) Run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: test_mnc <command> [flags], commands: promote, statement, reconcile, interest")
+	}
+
+	switch args[0] {
+	case "promote":
+		return a.promote(args[1:])
+	case "statement":
+		return a.statement(args[1:])
+	case "reconcile":
+		return a.reconcile(args[1:])
+	case "interest":
+		return a.interest(args[1:])
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// promote changes the role of a user, it is how the first admin is made, e.g.
+//
+//	test_mnc promote -username alice -role admin
+func (a *App) promote(args []string) error {
+	flags := flag.NewFlagSet("promote", flag.ContinueOnError)
+	username := flags.String("username", "", "username of the user")
+	role := flags.String("role", model.RoleAdmin, "one of user, approver, admin")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+
+	user, err := a.UserUsecase.Promote(*username, *role)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Stdout, "user %d (%s) is now %s\n", user.ID, user.Username, user.Role)
+	return nil
+}
+
+// statement exports the statement of one account, e.g.
+//
+//	test_mnc statement -account 1 -from 2023-06-01 -to 2023-06-30 -format mt940 -out june.sta
+func (a *App) statement(args []string) error {
+	flags := flag.NewFlagSet("statement", flag.ContinueOnError)
+	accountID := flags.Int64("account", 0, "account id")
+	fromParam := flags.String("from", "", "first day of the period, YYYY-MM-DD")
+	toParam := flags.String("to", "", "last day of the period, YYYY-MM-DD")
+	format := flags.String("format", report.FormatMT940, "one of "+strings.Join(report.Formats(), ", "))
+	out := flags.String("out", "", "output file, defaults to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *accountID == 0 {
+		return errors.New("-account is required")
+	}
+	if !report.Supported(*format) {
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	from, to, err := utils.ParsePeriod(*fromParam, *toParam)
+	if err != nil {
+		return err
+	}
+
+	statement, err := a.StatementUsecase.Generate(*accountID, from, to)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return report.WriteStatement(a.Stdout, statement, *format)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteStatement(f, statement, *format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// reconcile compares every account balance with its history and prints the
+// discrepancies, -adjust also writes correcting history rows.
+func (a *App) reconcile(args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	adjust := flags.Bool("adjust", false, "write adjustment history rows for every discrepancy")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := a.ReconciliationUsecase.Run(*adjust, 0)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.Stdout, "checked %d accounts, %d discrepancies\n", report.AccountsChecked, len(report.Discrepancies))
+	for _, item := range report.Discrepancies {
+		fmt.Fprintf(a.Stdout, "account %d: balance %.2f, history %.2f (%d rows), difference %.2f",
+			item.AccountID, item.RecordedBalance, item.ComputedBalance, item.HistoryCount, item.Difference)
+		if item.AdjustmentID != 0 {
+			fmt.Fprintf(a.Stdout, ", adjusted by history %d", item.AdjustmentID)
+		}
+		fmt.Fprintln(a.Stdout)
+	}
+	return nil
+}
+
+// interest replays the accrual of a period or posts the interest of a month,
+// e.g.
+//
+//	test_mnc interest -accrue 2023-06-01 -to 2023-06-30
+//	test_mnc interest -post 2023-06
+func (a *App) interest(args []string) error {
+	flags := flag.NewFlagSet("interest", flag.ContinueOnError)
+	accrue := flags.String("accrue", "", "first day to accrue, YYYY-MM-DD")
+	toParam := flags.String("to", "", "last day to accrue, defaults to -accrue")
+	post := flags.String("post", "", "month to post, YYYY-MM")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *accrue == "" && *post == "" {
+		return errors.New("-accrue or -post is required")
+	}
+
+	if *accrue != "" {
+		if *toParam == "" {
+			*toParam = *accrue
+		}
+		from, to, err := utils.ParsePeriod(*accrue, *toParam)
+		if err != nil {
+			return err
+		}
+
+		accruals, err := a.InterestUsecase.Accrue(from, to)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.Stdout, "accrued %d account days\n", len(accruals))
+	}
+
+	if *post != "" {
+		month, err := time.ParseInLocation("2006-01", *post, time.Local)
+		if err != nil {
+			return errors.New("-post must be formatted as YYYY-MM")
+		}
+
+		historys, err := a.InterestUsecase.Post(month, 0)
+		if err != nil {
+			return err
+		}
+		for _, history := range historys {
+			fmt.Fprintf(a.Stdout, "account %d: interest %.2f, history %d\n", history.AccountID, history.Amount, history.ID)
+		}
+		fmt.Fprintf(a.Stdout, "posted %s to %d accounts\n", month.Format("2006-01"), len(historys))
+	}
+	return nil
+}
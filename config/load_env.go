@@ -12,6 +12,71 @@ type Config struct {
 	TokenSecret    string        `mapstructure:"TOKEN_SECRET"`
 	TokenExpiresIn time.Duration `mapstructure:"TOKEN_EXPIRED_IN"`
 	TokenMaxAge    int           `mapstructure:"TOKEN_MAXAGE"`
+
+	SchedulerInterval   time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	SchedulerMaxRetries int           `mapstructure:"SCHEDULER_MAX_RETRIES"`
+	SchedulerRetryDelay time.Duration `mapstructure:"SCHEDULER_RETRY_DELAY"`
+
+	LimitMaxPerTransaction float64 `mapstructure:"LIMIT_MAX_PER_TRANSACTION"`
+	LimitDailyAmount       float64 `mapstructure:"LIMIT_DAILY_AMOUNT"`
+	LimitMonthlyAmount     float64 `mapstructure:"LIMIT_MONTHLY_AMOUNT"`
+	LimitDailyCount        int     `mapstructure:"LIMIT_DAILY_COUNT"`
+
+	FeeRevenueAccountID int64 `mapstructure:"FEE_REVENUE_ACCOUNT_ID"`
+
+	HoldDefaultTTL time.Duration `mapstructure:"HOLD_DEFAULT_TTL"`
+
+	BatchMaxItems int `mapstructure:"BATCH_MAX_ITEMS"`
+
+	ApprovalThreshold float64       `mapstructure:"APPROVAL_THRESHOLD"`
+	ApprovalTTL       time.Duration `mapstructure:"APPROVAL_TTL"`
+
+	BootstrapAdminUsername string `mapstructure:"BOOTSTRAP_ADMIN_USERNAME"`
+
+	StatementDir string `mapstructure:"STATEMENT_DIR"`
+	BankCode     string `mapstructure:"BANK_CODE"`
+
+	AccountBranchCode string `mapstructure:"ACCOUNT_BRANCH_CODE"`
+
+	ReconciliationInterval time.Duration `mapstructure:"RECONCILIATION_INTERVAL"`
+
+	AccountFrozenBlocksCredits bool          `mapstructure:"ACCOUNT_FROZEN_BLOCKS_CREDITS"`
+	AccountDormantAfter        time.Duration `mapstructure:"ACCOUNT_DORMANT_AFTER"`
+
+	InterestExpenseAccountID int64   `mapstructure:"INTEREST_EXPENSE_ACCOUNT_ID"`
+	InterestTaxRate          float64 `mapstructure:"INTEREST_TAX_RATE"`
+
+	FxRateFile string        `mapstructure:"FX_RATE_FILE"`
+	FxSpread   float64       `mapstructure:"FX_SPREAD"`
+	FxQuoteTTL time.Duration `mapstructure:"FX_QUOTE_TTL"`
+
+	BeneficiaryCoolingOff       time.Duration `mapstructure:"BENEFICIARY_COOLING_OFF"`
+	BeneficiaryCoolingOffAmount float64       `mapstructure:"BENEFICIARY_COOLING_OFF_AMOUNT"`
+
+	PaymentRequestTTL time.Duration `mapstructure:"PAYMENT_REQUEST_TTL"`
+
+	VirtualAccountTTL                 time.Duration `mapstructure:"VIRTUAL_ACCOUNT_TTL"`
+	VirtualAccountCallbackMaxAttempts int           `mapstructure:"VIRTUAL_ACCOUNT_CALLBACK_MAX_ATTEMPTS"`
+	VirtualAccountCallbackTimeout     time.Duration `mapstructure:"VIRTUAL_ACCOUNT_CALLBACK_TIMEOUT"`
+
+	WebhookMaxAttempts int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookRetryBase   time.Duration `mapstructure:"WEBHOOK_RETRY_BASE"`
+	WebhookRetryMax    time.Duration `mapstructure:"WEBHOOK_RETRY_MAX"`
+	WebhookTimeout     time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+
+	StreamReplaySize int           `mapstructure:"STREAM_REPLAY_SIZE"`
+	StreamHeartbeat  time.Duration `mapstructure:"STREAM_HEARTBEAT"`
+
+	OutboxBroker        string        `mapstructure:"OUTBOX_BROKER"`
+	OutboxBrokerURL     string        `mapstructure:"OUTBOX_BROKER_URL"`
+	OutboxBrokerTimeout time.Duration `mapstructure:"OUTBOX_BROKER_TIMEOUT"`
+	OutboxTopicPrefix   string        `mapstructure:"OUTBOX_TOPIC_PREFIX"`
+	OutboxBatchSize     int           `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxRetryBase     time.Duration `mapstructure:"OUTBOX_RETRY_BASE"`
+	OutboxRetryMax      time.Duration `mapstructure:"OUTBOX_RETRY_MAX"`
+
+	AccountEventSourcing bool  `mapstructure:"ACCOUNT_EVENT_SOURCING"`
+	AccountSnapshotEvery int64 `mapstructure:"ACCOUNT_SNAPSHOT_EVERY"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
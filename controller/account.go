@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -45,6 +46,11 @@ func (c *AccountCon) Create(ctx *gin.Context) {
 	}
 
 	newAccount, err := c.AccountUsecase.Save(insertAccount)
+	var ruleErr *usecase.ProductRuleError
+	if errors.As(err, &ruleErr) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": ruleErr.Code, "error": ruleErr.Message})
+		return
+	}
 	if err != nil {
 		ctx.JSON(500, gin.H{"error": err.Error()})
 		return
@@ -92,6 +98,10 @@ func (c *AccountCon) Update(ctx *gin.Context) {
 	}
 
 	updatedAccount, err := c.AccountUsecase.Update(updateID)
+	if errors.Is(err, usecase.ErrDirectBalanceEdit) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -108,10 +118,10 @@ func (c *AccountCon) Delete(ctx *gin.Context) {
 
 	_, err = c.AccountUsecase.Delete(id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully deleted Account!"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully closed Account!"})
 }
 
 func (c *AccountCon) GetByUserID(ctx *gin.Context) {
@@ -129,3 +139,189 @@ func (c *AccountCon) GetByUserID(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"Account": AccountByUserID})
 }
+
+type cashRequest struct {
+	Amount    float64 `json:"amount"`
+	Channel   string  `json:"channel"`
+	Reference string  `json:"reference"`
+}
+
+func (c *AccountCon) Deposit(ctx *gin.Context) {
+	c.postCash(ctx, c.AccountUsecase.Deposit)
+}
+
+func (c *AccountCon) Withdraw(ctx *gin.Context) {
+	c.postCash(ctx, c.AccountUsecase.Withdraw)
+}
+
+func (c *AccountCon) postCash(ctx *gin.Context, post func(accountID int64, amount float64, channel string, reference string, actorID int64) (model.History, error)) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := c.AccountUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if account.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account does not belong to the current user"})
+		return
+	}
+
+	req := cashRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := post(account.ID, req.Amount, req.Channel, req.Reference, userID)
+	if err != nil {
+		var ruleErr *usecase.ProductRuleError
+		if errors.As(err, &ruleErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": ruleErr.Code, "error": ruleErr.Message})
+			return
+		}
+		var statusErr *usecase.AccountStatusError
+		if errors.Is(err, usecase.ErrInsufficientBalance) || errors.As(err, &statusErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"History": history})
+}
+
+type statusRequest struct {
+	Reason          string `json:"reason"`
+	PayoutAccountID int64  `json:"payout_account_id"`
+}
+
+// ownedAccount loads the account in the :id param and checks that it belongs
+// to the current user.
+func (c *AccountCon) ownedAccount(ctx *gin.Context) (model.Account, int64, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return model.Account{}, 0, false
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.Account{}, 0, false
+	}
+
+	account, err := c.AccountUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.Account{}, 0, false
+	}
+	if account.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account does not belong to the current user"})
+		return model.Account{}, 0, false
+	}
+
+	return account, userID, true
+}
+
+func (c *AccountCon) Freeze(ctx *gin.Context) {
+	account, userID, ok := c.ownedAccount(ctx)
+	if !ok {
+		return
+	}
+
+	req := statusRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	frozenAccount, err := c.AccountUsecase.Freeze(account.ID, req.Reason, userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Account": frozenAccount})
+}
+
+func (c *AccountCon) Close(ctx *gin.Context) {
+	account, userID, ok := c.ownedAccount(ctx)
+	if !ok {
+		return
+	}
+
+	req := statusRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	closedAccount, err := c.AccountUsecase.Close(account.ID, req.PayoutAccountID, req.Reason, userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Account": closedAccount})
+}
+
+func (c *AccountCon) AdminFreeze(ctx *gin.Context) {
+	c.adminSetStatus(ctx, c.AccountUsecase.Freeze)
+}
+
+func (c *AccountCon) AdminUnfreeze(ctx *gin.Context) {
+	c.adminSetStatus(ctx, c.AccountUsecase.Unfreeze)
+}
+
+// adminSetStatus changes the status of any account, for back office use.
+func (c *AccountCon) adminSetStatus(ctx *gin.Context, set func(id int64, reason string, actorID int64) (model.Account, error)) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req := statusRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedAccount, err := set(id, req.Reason, userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Account": updatedAccount})
+}
+
+// FindByNumber looks up the holder of an account number, so a sender can
+// check the destination before transferring.
+func (c *AccountCon) FindByNumber(ctx *gin.Context) {
+	account, err := c.AccountUsecase.FindByNumber(ctx.Param("number"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_number": account.Number,
+		"id":             account.ID,
+		"holder":         account.User.Username,
+		"status":         account.CurrentStatus(),
+	})
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/eventsource"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type AccountEventCon struct {
+	AccountEventUsecase usecase.AccountEventUsecase
+}
+
+func NewAccountEventController(AccountEventUsecase usecase.AccountEventUsecase) *AccountEventCon {
+	return &AccountEventCon{
+		AccountEventUsecase: AccountEventUsecase,
+	}
+}
+
+// FindEvents lists the stream of an account, after the after_version query
+// parameter when it is set.
+func (c *AccountEventCon) FindEvents(ctx *gin.Context) {
+	id, ok := accountIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var afterVersion int64
+	if ctx.Query("after_version") != "" {
+		var err error
+		afterVersion, err = strconv.ParseInt(ctx.Query("after_version"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	events, err := c.AccountEventUsecase.FindEvents(id, afterVersion)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Events": events})
+}
+
+// State rebuilds an account from its stream, as of the RFC 3339 as_of query
+// parameter when it is set.
+func (c *AccountEventCon) State(ctx *gin.Context) {
+	id, ok := accountIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var account eventsource.Account
+	var err error
+	if ctx.Query("as_of") != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, ctx.Query("as_of"))
+		if parseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "as_of must be an RFC 3339 time"})
+			return
+		}
+		account, err = c.AccountEventUsecase.LoadAsOf(id, asOf)
+	} else {
+		account, err = c.AccountEventUsecase.Load(id)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Account": account.State, "Version": account.Version, "AsOf": account.AsOf})
+}
+
+func (c *AccountEventCon) Snapshot(ctx *gin.Context) {
+	id, ok := accountIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	snapshot, err := c.AccountEventUsecase.Snapshot(id)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Snapshot": snapshot})
+}
+
+// Project overwrites the stored balance and status of an account with the
+// ones rebuilt from its stream.
+func (c *AccountEventCon) Project(ctx *gin.Context) {
+	id, ok := accountIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	account, err := c.AccountEventUsecase.Project(id)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Account": account})
+}
+
+func (c *AccountEventCon) Import(ctx *gin.Context) {
+	imported, err := c.AccountEventUsecase.Import()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Imported": imported})
+}
+
+func accountIDParam(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type AccountProductCon struct {
+	ProductUsecase usecase.AccountProductUsecase
+}
+
+func NewAccountProductController(ProductUsecase usecase.AccountProductUsecase) *AccountProductCon {
+	return &AccountProductCon{
+		ProductUsecase: ProductUsecase,
+	}
+}
+
+func (c *AccountProductCon) FindAll(ctx *gin.Context) {
+	products, err := c.ProductUsecase.FindAll()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Products": products})
+}
+
+func (c *AccountProductCon) FindByCode(ctx *gin.Context) {
+	product, err := c.ProductUsecase.FindByCode(ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Product": product})
+}
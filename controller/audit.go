@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type AuditCon struct {
+	AuditUsecase usecase.AuditUsecase
+}
+
+func NewAuditController(AuditUsecase usecase.AuditUsecase) *AuditCon {
+	return &AuditCon{
+		AuditUsecase: AuditUsecase,
+	}
+}
+
+func (c *AuditCon) FindAll(ctx *gin.Context) {
+	entity := ctx.Query("entity")
+	if entity == "" {
+		logs, err := c.AuditUsecase.FindAll()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"AuditLogs": logs})
+		return
+	}
+
+	entityID, err := strconv.ParseInt(ctx.Query("entity_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "entity_id is required"})
+		return
+	}
+
+	logs, err := c.AuditUsecase.FindByEntity(entity, entityID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"AuditLogs": logs})
+}
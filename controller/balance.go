@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+	"github.com/sferawann/test_mnc/utils"
+)
+
+type BalanceCon struct {
+	BalanceUsecase usecase.BalanceUsecase
+	AccountUsecase usecase.AccountUsecase
+}
+
+func NewBalanceController(BalanceUsecase usecase.BalanceUsecase, AccountUsecase usecase.AccountUsecase) *BalanceCon {
+	return &BalanceCon{
+		BalanceUsecase: BalanceUsecase,
+		AccountUsecase: AccountUsecase,
+	}
+}
+
+// BalanceAt returns the balance of one of the current user's accounts at
+// the at query parameter, an RFC 3339 time or a date for its end of day.
+func (c *BalanceCon) BalanceAt(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+	c.balanceAt(ctx, userID)
+}
+
+// AdminBalanceAt is BalanceAt for any account.
+func (c *BalanceCon) AdminBalanceAt(ctx *gin.Context) {
+	c.balanceAt(ctx, 0)
+}
+
+// balanceAt checks that the account belongs to userID unless it is 0.
+func (c *BalanceCon) balanceAt(ctx *gin.Context, userID int64) {
+	id, ok := accountIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	at, err := utils.ParseInstant(ctx.Query("at"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := c.AccountUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if userID != 0 && account.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account does not belong to the current user"})
+		return
+	}
+
+	balance, err := c.BalanceUsecase.BalanceAt(account.ID, at)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Balance": balance})
+}
+
+// EndOfDay returns the closing balances of every account for the date query
+// parameter (YYYY-MM-DD).
+func (c *BalanceCon) EndOfDay(ctx *gin.Context) {
+	day, err := time.ParseInLocation("2006-01-02", ctx.Query("date"), time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "date must be formatted as YYYY-MM-DD"})
+		return
+	}
+
+	balances, err := c.BalanceUsecase.EndOfDay(day)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Date": day.Format("2006-01-02"), "Balances": balances})
+}
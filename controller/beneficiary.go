@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type BeneficiaryCon struct {
+	BeneficiaryUsecase usecase.BeneficiaryUsecase
+}
+
+func NewBeneficiaryController(BeneficiaryUsecase usecase.BeneficiaryUsecase) *BeneficiaryCon {
+	return &BeneficiaryCon{
+		BeneficiaryUsecase: BeneficiaryUsecase,
+	}
+}
+
+func (c *BeneficiaryCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	insertBeneficiary := model.Beneficiary{}
+	if err := ctx.ShouldBindJSON(&insertBeneficiary); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insertBeneficiary.UserID = userID
+
+	newBeneficiary, err := c.BeneficiaryUsecase.Save(insertBeneficiary)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Beneficiary": newBeneficiary})
+}
+
+func (c *BeneficiaryCon) FindAll(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	beneficiaries, err := c.BeneficiaryUsecase.FindByUserId(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Beneficiaries": beneficiaries})
+}
+
+func (c *BeneficiaryCon) FindByID(ctx *gin.Context) {
+	beneficiary, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Beneficiary": beneficiary})
+}
+
+func (c *BeneficiaryCon) Update(ctx *gin.Context) {
+	beneficiary, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	updateBeneficiary := model.Beneficiary{}
+	if err := ctx.ShouldBindJSON(&updateBeneficiary); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateBeneficiary.ID = beneficiary.ID
+
+	updatedBeneficiary, err := c.BeneficiaryUsecase.Update(updateBeneficiary)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Beneficiary": updatedBeneficiary})
+}
+
+func (c *BeneficiaryCon) Delete(ctx *gin.Context) {
+	beneficiary, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := c.BeneficiaryUsecase.Delete(beneficiary.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully deleted Beneficiary!"})
+}
+
+// findOwned loads the beneficiary from the :id param and checks that it
+// belongs to the current user.
+func (c *BeneficiaryCon) findOwned(ctx *gin.Context) (model.Beneficiary, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return model.Beneficiary{}, false
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.Beneficiary{}, false
+	}
+
+	beneficiary, err := c.BeneficiaryUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.Beneficiary{}, false
+	}
+	if beneficiary.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "beneficiary does not belong to the current user"})
+		return model.Beneficiary{}, false
+	}
+
+	return beneficiary, true
+}
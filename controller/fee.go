@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type FeeCon struct {
+	FeeUsecase     usecase.FeeUsecase
+	AccountUsecase usecase.AccountUsecase
+}
+
+func NewFeeController(FeeUsecase usecase.FeeUsecase, AccountUsecase usecase.AccountUsecase) *FeeCon {
+	return &FeeCon{
+		FeeUsecase:     FeeUsecase,
+		AccountUsecase: AccountUsecase,
+	}
+}
+
+func (c *FeeCon) Quote(ctx *gin.Context) {
+	fromID, err := strconv.ParseInt(ctx.Query("from_account_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from_account_id is required"})
+		return
+	}
+	toID, err := strconv.ParseInt(ctx.Query("to_account_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "to_account_id is required"})
+		return
+	}
+	amount, err := strconv.ParseFloat(ctx.Query("amount"), 64)
+	if err != nil || amount <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Amount must be greater than 0"})
+		return
+	}
+
+	fromacc, err := c.AccountUsecase.FindById(fromID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "from_account_id not found"})
+		return
+	}
+	toacc, err := c.AccountUsecase.FindById(toID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "to_account_id not found"})
+		return
+	}
+
+	quote, err := c.FeeUsecase.Quote(fromacc, toacc, amount)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"FeeQuote": quote})
+}
+
+func (c *FeeCon) Schedule(ctx *gin.Context) {
+	rules, err := c.FeeUsecase.FindAll()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"FeeRules": rules})
+}
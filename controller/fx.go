@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/fx"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type FxCon struct {
+	FxQuoteUsecase usecase.FxQuoteUsecase
+}
+
+func NewFxController(FxQuoteUsecase usecase.FxQuoteUsecase) *FxCon {
+	return &FxCon{
+		FxQuoteUsecase: FxQuoteUsecase,
+	}
+}
+
+// Rate returns the mid rate between two currencies, without the spread.
+func (c *FxCon) Rate(ctx *gin.Context) {
+	from := ctx.Query("from")
+	to := ctx.Query("to")
+	if from == "" || to == "" {
+		currencies, err := c.FxQuoteUsecase.Currencies()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required", "currencies": currencies})
+		return
+	}
+
+	rate, err := c.FxQuoteUsecase.Rate(from, to)
+	var currencyErr *fx.UnsupportedCurrencyError
+	if errors.As(err, &currencyErr) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"from": from, "to": to, "rate": rate})
+}
+
+type quoteRequest struct {
+	FromAccountID int64   `json:"from_account_id" binding:"required"`
+	ToCurrency    string  `json:"to_currency" binding:"required"`
+	Amount        float64 `json:"amount" binding:"required"`
+}
+
+func (c *FxCon) Quote(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	req := quoteRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := c.FxQuoteUsecase.Quote(userID, req.FromAccountID, req.ToCurrency, req.Amount)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Quote": quote})
+}
+
+func (c *FxCon) FindQuoteByID(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := c.FxQuoteUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if quote.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "quote does not belong to the current user"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Quote": quote})
+}
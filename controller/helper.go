@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID returns the user id set by middleware.AuthMiddleware, writing
+// an Unauthorized response when it is missing.
+func currentUserID(ctx *gin.Context) (int64, bool) {
+	currentUserID, exists := ctx.Get("currentUserID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return 0, false
+	}
+	return currentUserID.(int64), true
+}
@@ -80,6 +80,22 @@ func (c *HistoryCon) Delete(ctx *gin.Context) {
 }
 
 func (c *HistoryCon) FindAll(ctx *gin.Context) {
+	if transferIDParam := ctx.Query("transfer_id"); transferIDParam != "" {
+		transferID, err := strconv.ParseInt(transferIDParam, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		Historys, err := c.HistoryUsecase.FindByTransferId(transferID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"Historys": Historys})
+		return
+	}
+
 	Historys, err := c.HistoryUsecase.FindAll()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
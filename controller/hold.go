@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type HoldCon struct {
+	HoldUsecase    usecase.HoldUsecase
+	AccountUsecase usecase.AccountUsecase
+}
+
+func NewHoldController(HoldUsecase usecase.HoldUsecase, AccountUsecase usecase.AccountUsecase) *HoldCon {
+	return &HoldCon{
+		HoldUsecase:    HoldUsecase,
+		AccountUsecase: AccountUsecase,
+	}
+}
+
+type captureRequest struct {
+	Amount      float64 `json:"amount"`
+	ToAccountID int64   `json:"to_account_id"`
+}
+
+func (c *HoldCon) Create(ctx *gin.Context) {
+	insertHold := model.Hold{}
+	if err := ctx.ShouldBindJSON(&insertHold); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if insertHold.AccountID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "id_account is required"})
+		return
+	}
+	if insertHold.Amount <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Amount must be greater than 0"})
+		return
+	}
+	if _, ok := c.ownedAccount(ctx, insertHold.AccountID); !ok {
+		return
+	}
+
+	newHold, err := c.HoldUsecase.Place(insertHold)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Hold": newHold})
+}
+
+func (c *HoldCon) FindByAccountID(ctx *gin.Context) {
+	accountID, err := strconv.ParseInt(ctx.Query("account_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "account_id is required"})
+		return
+	}
+
+	account, ok := c.ownedAccount(ctx, accountID)
+	if !ok {
+		return
+	}
+
+	holds, err := c.HoldUsecase.FindByAccountId(accountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"Holds":             holds,
+		"balance":           account.Balance,
+		"held_balance":      account.HeldBalance,
+		"available_balance": account.AvailableBalance(),
+	})
+}
+
+func (c *HoldCon) FindByID(ctx *gin.Context) {
+	hold, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Hold": hold})
+}
+
+func (c *HoldCon) Capture(ctx *gin.Context) {
+	hold, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	req := captureRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	capturedHold, err := c.HoldUsecase.Capture(hold.ID, req.Amount, req.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Hold": capturedHold})
+}
+
+func (c *HoldCon) Release(ctx *gin.Context) {
+	hold, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	releasedHold, err := c.HoldUsecase.Release(hold.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Hold": releasedHold})
+}
+
+// findOwned loads the hold from the :id param and checks that its account
+// belongs to the current user.
+func (c *HoldCon) findOwned(ctx *gin.Context) (model.Hold, bool) {
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.Hold{}, false
+	}
+
+	hold, err := c.HoldUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.Hold{}, false
+	}
+
+	if _, ok := c.ownedAccount(ctx, hold.AccountID); !ok {
+		return model.Hold{}, false
+	}
+
+	return hold, true
+}
+
+func (c *HoldCon) ownedAccount(ctx *gin.Context, accountID int64) (model.Account, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return model.Account{}, false
+	}
+
+	account, err := c.AccountUsecase.FindById(accountID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.Account{}, false
+	}
+	if account.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account does not belong to the current user"})
+		return model.Account{}, false
+	}
+
+	return account, true
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+	"github.com/sferawann/test_mnc/utils"
+)
+
+type InterestCon struct {
+	InterestUsecase usecase.InterestUsecase
+	AccountUsecase  usecase.AccountUsecase
+}
+
+func NewInterestController(InterestUsecase usecase.InterestUsecase, AccountUsecase usecase.AccountUsecase) *InterestCon {
+	return &InterestCon{
+		InterestUsecase: InterestUsecase,
+		AccountUsecase:  AccountUsecase,
+	}
+}
+
+// Accruals returns the daily accruals of an account and the interest that
+// is accrued but not posted yet.
+func (c *InterestCon) Accruals(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := c.AccountUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if account.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account does not belong to the current user"})
+		return
+	}
+
+	accruals, err := c.InterestUsecase.FindByAccountId(account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var accrued float64
+	for _, accrual := range accruals {
+		if !accrual.Posted() {
+			accrued += accrual.Amount
+		}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Accrued": accrued, "Accruals": accruals})
+}
+
+type accrueRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to"`
+}
+
+func (c *InterestCon) Accrue(ctx *gin.Context) {
+	req := accrueRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.To == "" {
+		req.To = req.From
+	}
+
+	from, to, err := utils.ParsePeriod(req.From, req.To)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	accruals, err := c.InterestUsecase.Accrue(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Accruals": accruals})
+}
+
+type postInterestRequest struct {
+	Month string `json:"month" binding:"required"`
+}
+
+func (c *InterestCon) Post(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	req := postInterestRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	month, err := time.ParseInLocation("2006-01", req.Month, time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "month must be formatted as YYYY-MM"})
+		return
+	}
+
+	historys, err := c.InterestUsecase.Post(month, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Historys": historys})
+}
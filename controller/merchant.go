@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type MerchantCon struct {
+	MerchantUsecase usecase.MerchantUsecase
+}
+
+func NewMerchantController(MerchantUsecase usecase.MerchantUsecase) *MerchantCon {
+	return &MerchantCon{
+		MerchantUsecase: MerchantUsecase,
+	}
+}
+
+type generateQRRequest struct {
+	Amount     float64 `json:"amount"`
+	BillNumber string  `json:"bill_number"`
+}
+
+type decodeQRRequest struct {
+	Payload string `json:"payload" binding:"required"`
+}
+
+func (c *MerchantCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	insertMerchant := model.Merchant{}
+	if err := ctx.ShouldBindJSON(&insertMerchant); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insertMerchant.UserID = userID
+
+	newMerchant, err := c.MerchantUsecase.Save(insertMerchant)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Merchant": newMerchant})
+}
+
+func (c *MerchantCon) FindAll(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	merchants, err := c.MerchantUsecase.FindByUserId(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Merchants": merchants})
+}
+
+func (c *MerchantCon) FindByID(ctx *gin.Context) {
+	merchant, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Merchant": merchant})
+}
+
+func (c *MerchantCon) GenerateQR(ctx *gin.Context) {
+	merchant, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	req := generateQRRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	qr, err := c.MerchantUsecase.GenerateQR(merchant.ID, req.Amount, req.BillNumber)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"QR": qr})
+}
+
+func (c *MerchantCon) FindPayments(ctx *gin.Context) {
+	merchant, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	payments, err := c.MerchantUsecase.FindPayments(merchant.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Payments": payments})
+}
+
+func (c *MerchantCon) DecodeQR(ctx *gin.Context) {
+	req := decodeQRRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	qr, merchant, err := c.MerchantUsecase.Decode(req.Payload)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"QR": qr, "Merchant": gin.H{"id": merchant.ID, "name": merchant.Name, "city": merchant.City}})
+}
+
+func (c *MerchantCon) PayQR(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	payment := model.QRPayment{}
+	if err := ctx.ShouldBindJSON(&payment); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := c.MerchantUsecase.Pay(userID, payment)
+	if err != nil {
+		var limitErr *usecase.LimitExceededError
+		var ruleErr *usecase.ProductRuleError
+		var statusErr *usecase.AccountStatusError
+		switch {
+		case errors.As(err, &limitErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": limitErr.Code, "error": limitErr.Message})
+		case errors.As(err, &ruleErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": ruleErr.Code, "error": ruleErr.Message})
+		case errors.As(err, &statusErr), errors.Is(err, usecase.ErrInsufficientBalance), errors.Is(err, usecase.ErrCurrencyMismatch):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if transfer.Status == model.TransferStatusPendingApproval {
+		ctx.JSON(http.StatusAccepted, gin.H{"Transfer": transfer, "message": "Transfer is waiting for approval"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Transfer": transfer})
+}
+
+// findOwned loads the merchant from the :id param and checks that it belongs
+// to the current user.
+func (c *MerchantCon) findOwned(ctx *gin.Context) (model.Merchant, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return model.Merchant{}, false
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.Merchant{}, false
+	}
+
+	merchant, err := c.MerchantUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.Merchant{}, false
+	}
+	if merchant.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "merchant does not belong to the current user"})
+		return model.Merchant{}, false
+	}
+
+	return merchant, true
+}
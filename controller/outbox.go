@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type OutboxCon struct {
+	OutboxUsecase usecase.OutboxUsecase
+}
+
+func NewOutboxController(OutboxUsecase usecase.OutboxUsecase) *OutboxCon {
+	return &OutboxCon{
+		OutboxUsecase: OutboxUsecase,
+	}
+}
+
+// FindAll lists the outbox, filtered by the status query parameter.
+func (c *OutboxCon) FindAll(ctx *gin.Context) {
+	events, err := c.OutboxUsecase.FindAll(ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Events": events})
+}
+
+// Relay publishes the pending events now instead of waiting for the job.
+func (c *OutboxCon) Relay(ctx *gin.Context) {
+	relayErr := c.OutboxUsecase.Relay(time.Now())
+
+	pending, err := c.OutboxUsecase.FindAll(model.OutboxStatusPending)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if relayErr != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": relayErr.Error(), "Pending": len(pending)})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Pending": len(pending)})
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type PaymentRequestCon struct {
+	PaymentRequestUsecase usecase.PaymentRequestUsecase
+}
+
+func NewPaymentRequestController(PaymentRequestUsecase usecase.PaymentRequestUsecase) *PaymentRequestCon {
+	return &PaymentRequestCon{
+		PaymentRequestUsecase: PaymentRequestUsecase,
+	}
+}
+
+func (c *PaymentRequestCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	insertRequest := model.PaymentRequest{}
+	if err := ctx.ShouldBindJSON(&insertRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insertRequest.RequesterID = userID
+	insertRequest.SplitID = 0
+
+	if insertRequest.PayerID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "id_payer is required"})
+		return
+	}
+	if insertRequest.ToAccountID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "to_account_id is required"})
+		return
+	}
+
+	newRequest, err := c.PaymentRequestUsecase.Save(insertRequest)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequest": newRequest})
+}
+
+func (c *PaymentRequestCon) Split(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	bill := model.SplitBill{}
+	if err := ctx.ShouldBindJSON(&bill); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if bill.ToAccountID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "to_account_id is required"})
+		return
+	}
+
+	requests, err := c.PaymentRequestUsecase.Split(userID, bill)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequests": requests})
+}
+
+func (c *PaymentRequestCon) FindIncoming(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	requests, err := c.PaymentRequestUsecase.FindIncoming(userID, ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequests": requests})
+}
+
+func (c *PaymentRequestCon) FindOutgoing(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	requests, err := c.PaymentRequestUsecase.FindOutgoing(userID, ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequests": requests})
+}
+
+func (c *PaymentRequestCon) FindByID(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := c.PaymentRequestUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if request.RequesterID != userID && request.PayerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "payment request does not belong to the current user"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequest": request})
+}
+
+type acceptRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required"`
+}
+
+func (c *PaymentRequestCon) Accept(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req := acceptRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := c.PaymentRequestUsecase.Accept(id, userID, req.FromAccountID)
+	if err != nil {
+		var limitErr *usecase.LimitExceededError
+		var ruleErr *usecase.ProductRuleError
+		var statusErr *usecase.AccountStatusError
+		switch {
+		case errors.As(err, &limitErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": limitErr.Code, "error": limitErr.Message})
+		case errors.As(err, &ruleErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": ruleErr.Code, "error": ruleErr.Message})
+		case errors.As(err, &statusErr), errors.Is(err, usecase.ErrInsufficientBalance):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if request.Status == model.PaymentRequestStatusAccepted {
+		ctx.JSON(http.StatusAccepted, gin.H{"PaymentRequest": request, "message": "Transfer is waiting for approval"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequest": request})
+}
+
+type declineRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *PaymentRequestCon) Decline(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Alasan menolak boleh dikosongkan
+	req := declineRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := c.PaymentRequestUsecase.Decline(id, userID, req.Reason)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequest": request})
+}
+
+func (c *PaymentRequestCon) Cancel(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := c.PaymentRequestUsecase.Cancel(id, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"PaymentRequest": request})
+}
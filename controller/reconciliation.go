@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type ReconciliationCon struct {
+	ReconciliationUsecase usecase.ReconciliationUsecase
+}
+
+func NewReconciliationController(ReconciliationUsecase usecase.ReconciliationUsecase) *ReconciliationCon {
+	return &ReconciliationCon{
+		ReconciliationUsecase: ReconciliationUsecase,
+	}
+}
+
+func (c *ReconciliationCon) Report(ctx *gin.Context) {
+	report, err := c.ReconciliationUsecase.Run(false, 0)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Reconciliation": report})
+}
+
+func (c *ReconciliationCon) Adjust(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.ReconciliationUsecase.Run(true, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Reconciliation": report})
+}
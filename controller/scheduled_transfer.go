@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type ScheduledTransferCon struct {
+	ScheduledTransferUsecase usecase.ScheduledTransferUsecase
+}
+
+func NewScheduledTransferController(ScheduledTransferUsecase usecase.ScheduledTransferUsecase) *ScheduledTransferCon {
+	return &ScheduledTransferCon{
+		ScheduledTransferUsecase: ScheduledTransferUsecase,
+	}
+}
+
+func (c *ScheduledTransferCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	insertSchedule := model.ScheduledTransfer{}
+	if err := ctx.ShouldBindJSON(&insertSchedule); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insertSchedule.UserID = userID
+
+	if insertSchedule.FromAccountID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from_account_id is required"})
+		return
+	}
+	if insertSchedule.ToAccountID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "to_account_id is required"})
+		return
+	}
+	if insertSchedule.Amount <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Amount must be greater than 0"})
+		return
+	}
+
+	newSchedule, err := c.ScheduledTransferUsecase.Save(insertSchedule)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"ScheduledTransfer": newSchedule})
+}
+
+func (c *ScheduledTransferCon) FindAll(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	schedules, err := c.ScheduledTransferUsecase.FindByUserId(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"ScheduledTransfers": schedules})
+}
+
+func (c *ScheduledTransferCon) FindByID(ctx *gin.Context) {
+	schedule, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"ScheduledTransfer": schedule})
+}
+
+func (c *ScheduledTransferCon) FindExecutions(ctx *gin.Context) {
+	schedule, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	executions, err := c.ScheduledTransferUsecase.FindExecutions(schedule.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Executions": executions})
+}
+
+func (c *ScheduledTransferCon) Update(ctx *gin.Context) {
+	schedule, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	updateID := model.ScheduledTransfer{}
+	if err := ctx.ShouldBindJSON(&updateID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateID.ID = schedule.ID
+
+	updatedSchedule, err := c.ScheduledTransferUsecase.Update(updateID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"ScheduledTransfer": updatedSchedule})
+}
+
+func (c *ScheduledTransferCon) Delete(ctx *gin.Context) {
+	schedule, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := c.ScheduledTransferUsecase.Cancel(schedule.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully cancelled Scheduled Transfer!"})
+}
+
+// findOwned loads the schedule from the :id param and checks that it belongs
+// to the current user.
+func (c *ScheduledTransferCon) findOwned(ctx *gin.Context) (model.ScheduledTransfer, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return model.ScheduledTransfer{}, false
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.ScheduledTransfer{}, false
+	}
+
+	schedule, err := c.ScheduledTransferUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.ScheduledTransfer{}, false
+	}
+	if schedule.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "scheduled transfer does not belong to the current user"})
+		return model.ScheduledTransfer{}, false
+	}
+
+	return schedule, true
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/report"
+	"github.com/sferawann/test_mnc/usecase"
+	"github.com/sferawann/test_mnc/utils"
+)
+
+type StatementCon struct {
+	StatementUsecase usecase.StatementUsecase
+	AccountUsecase   usecase.AccountUsecase
+}
+
+func NewStatementController(StatementUsecase usecase.StatementUsecase, AccountUsecase usecase.AccountUsecase) *StatementCon {
+	return &StatementCon{
+		StatementUsecase: StatementUsecase,
+		AccountUsecase:   AccountUsecase,
+	}
+}
+
+func (c *StatementCon) Download(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := c.AccountUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if account.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account does not belong to the current user"})
+		return
+	}
+
+	from, to, err := utils.ParsePeriod(ctx.Query("from"), ctx.Query("to"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := ctx.DefaultQuery("format", report.FormatPDF)
+	if !report.Supported(format) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of " + strings.Join(report.Formats(), ", ")})
+		return
+	}
+
+	statement, err := c.StatementUsecase.Generate(account.ID, from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("statement_%d_%s_%s.%s", account.ID, from.Format("20060102"), to.AddDate(0, 0, -1).Format("20060102"), report.FileExtension(format))
+	ctx.Header("Content-Disposition", "attachment; filename="+filename)
+	ctx.Header("Content-Type", report.ContentType(format))
+	ctx.Status(http.StatusOK)
+	if err := report.WriteStatement(ctx.Writer, statement, format); err != nil {
+		ctx.Error(err)
+	}
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/eventbus"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+	"golang.org/x/net/websocket"
+)
+
+// streamResetEvent tells a client that the events after its last event ID are
+// no longer available, it should reload its balances before continuing.
+const streamResetEvent = "stream.reset"
+
+type StreamCon struct {
+	StreamUsecase usecase.StreamUsecase
+	Heartbeat     time.Duration
+}
+
+func NewStreamController(StreamUsecase usecase.StreamUsecase, Heartbeat time.Duration) *StreamCon {
+	return &StreamCon{
+		StreamUsecase: StreamUsecase,
+		Heartbeat:     Heartbeat,
+	}
+}
+
+// streamMessage is one WebSocket message, Type is an event type, heartbeat or
+// stream.reset.
+type streamMessage struct {
+	Type  string       `json:"type"`
+	Event *model.Event `json:"event,omitempty"`
+	Time  time.Time    `json:"time"`
+}
+
+// SSE streams the events of the caller's accounts as server-sent events.
+func (c *StreamCon) SSE(ctx *gin.Context) {
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+
+	subscription, ok := c.subscribe(ctx, lastEventID)
+	if !ok {
+		return
+	}
+	defer c.StreamUsecase.Unsubscribe(subscription)
+
+	// Stream tidak boleh diputus oleh WriteTimeout server
+	http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	if !subscription.Resumed {
+		fmt.Fprintf(ctx.Writer, "event: %s\ndata: {}\n\n", streamResetEvent)
+	}
+	for _, event := range subscription.Missed {
+		writeSSE(ctx.Writer, event)
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(c.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			writeSSE(ctx.Writer, event)
+		case <-heartbeat.C:
+			fmt.Fprint(ctx.Writer, ": heartbeat\n\n")
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+func writeSSE(w gin.ResponseWriter, event model.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
+
+// WebSocket streams the same events as SSE as JSON messages.
+func (c *StreamCon) WebSocket(ctx *gin.Context) {
+	subscription, ok := c.subscribe(ctx, ctx.Query("last_event_id"))
+	if !ok {
+		return
+	}
+	defer c.StreamUsecase.Unsubscribe(subscription)
+
+	server := websocket.Server{
+		// Origin tidak dicek karena autentikasi memakai Bearer token, bukan cookie
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			c.serveWebSocket(ws, subscription)
+		},
+	}
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+func (c *StreamCon) serveWebSocket(ws *websocket.Conn, subscription *eventbus.Subscription) {
+	defer ws.Close()
+	ws.SetDeadline(time.Time{})
+
+	// Pesan dari client diabaikan, hanya dibaca untuk mengetahui koneksi ditutup
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var message string
+		for websocket.Message.Receive(ws, &message) == nil {
+		}
+	}()
+
+	send := func(message streamMessage) bool {
+		return websocket.JSON.Send(ws, message) == nil
+	}
+
+	if !subscription.Resumed && !send(streamMessage{Type: streamResetEvent, Time: time.Now()}) {
+		return
+	}
+	for i := range subscription.Missed {
+		if !send(streamMessage{Type: subscription.Missed[i].Type, Event: &subscription.Missed[i], Time: time.Now()}) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(c.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		var message streamMessage
+		select {
+		case <-closed:
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			message = streamMessage{Type: event.Type, Event: &event, Time: time.Now()}
+		case <-heartbeat.C:
+			message = streamMessage{Type: "heartbeat", Time: time.Now()}
+		}
+		if !send(message) {
+			return
+		}
+	}
+}
+
+func (c *StreamCon) subscribe(ctx *gin.Context, lastEventID string) (*eventbus.Subscription, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	subscription, err := c.StreamUsecase.Subscribe(userID, lastEventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return subscription, true
+}
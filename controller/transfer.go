@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,53 +11,119 @@ import (
 )
 
 type TransferCon struct {
-	TransferUsecase usecase.TransferUsecase
-	AccountUsecase  usecase.AccountUsecase
+	TransferUsecase    usecase.TransferUsecase
+	AccountUsecase     usecase.AccountUsecase
+	BeneficiaryUsecase usecase.BeneficiaryUsecase
 }
 
-func NewTransferController(TransferUsecase usecase.TransferUsecase, AccountUsecase usecase.AccountUsecase) *TransferCon {
+func NewTransferController(TransferUsecase usecase.TransferUsecase, AccountUsecase usecase.AccountUsecase, BeneficiaryUsecase usecase.BeneficiaryUsecase) *TransferCon {
 	return &TransferCon{
-		TransferUsecase: TransferUsecase,
-		AccountUsecase:  AccountUsecase,
+		TransferUsecase:    TransferUsecase,
+		AccountUsecase:     AccountUsecase,
+		BeneficiaryUsecase: BeneficiaryUsecase,
 	}
 }
 
 func (c *TransferCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
 	insertTransfer := model.Transfer{}
 	if err := ctx.ShouldBind(&insertTransfer); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	insertTransfer.InitiatedBy = userID
+	insertTransfer.Purpose = ""
+	insertTransfer.MerchantID = 0
+	insertTransfer.HoldID = 0
+	insertTransfer.PaymentRequestID = 0
+	insertTransfer.Channel = ""
+
 	if insertTransfer.FromAccountID == 0 {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "from_account_id is required"})
 		return
 	}
-	if insertTransfer.ToAccountID == 0 {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "to_account_id is required"})
-		return
-	}
 	if insertTransfer.Amount <= 0 {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Amount must be greater than 0"})
 		return
 	}
 
+	// Tujuan bisa diambil dari beneficiary yang sudah disimpan
+	if insertTransfer.BeneficiaryID != 0 {
+		beneficiary, err := c.BeneficiaryUsecase.FindById(insertTransfer.BeneficiaryID)
+		if err != nil || beneficiary.UserID != userID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "id_beneficiary not found"})
+			return
+		}
+
+		err = c.BeneficiaryUsecase.CheckTransfer(beneficiary, insertTransfer.Amount)
+		var coolingErr *usecase.CoolingOffError
+		if errors.As(err, &coolingErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": "BENEFICIARY_COOLING_OFF", "error": err.Error()})
+			return
+		}
+
+		insertTransfer.ToAccountID = beneficiary.AccountID
+		insertTransfer.ToAccountNumber = ""
+	}
+
+	if insertTransfer.ToAccountID == 0 && insertTransfer.ToAccountNumber == "" {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "to_account_id, to_account_number or id_beneficiary is required"})
+		return
+	}
+
 	_, err := c.AccountUsecase.FindById(insertTransfer.FromAccountID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "from_account_id not found"})
 		return
 	}
 
-	_, err = c.AccountUsecase.FindById(insertTransfer.ToAccountID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "to_account_id not found"})
-		return
+	if insertTransfer.ToAccountID != 0 {
+		_, err = c.AccountUsecase.FindById(insertTransfer.ToAccountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "to_account_id not found"})
+			return
+		}
+	} else {
+		_, err = c.AccountUsecase.FindByNumber(insertTransfer.ToAccountNumber)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	newTransfer, err := c.TransferUsecase.Save(insertTransfer)
+	var limitErr *usecase.LimitExceededError
+	if errors.As(err, &limitErr) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": limitErr.Code, "error": limitErr.Message})
+		return
+	}
+	var ruleErr *usecase.ProductRuleError
+	if errors.As(err, &ruleErr) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": ruleErr.Code, "error": ruleErr.Message})
+		return
+	}
+	var statusErr *usecase.AccountStatusError
+	if errors.As(err, &statusErr) || errors.Is(err, usecase.ErrCurrencyMismatch) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if newTransfer.BeneficiaryID != 0 && newTransfer.Status == model.TransferStatusCompleted {
+		if _, err := c.BeneficiaryUsecase.MarkUsed(newTransfer.BeneficiaryID); err != nil {
+			ctx.Error(err)
+		}
+	}
+	if newTransfer.Status == model.TransferStatusPendingApproval {
+		ctx.JSON(http.StatusAccepted, gin.H{"Transfer": newTransfer, "message": "Transfer is waiting for approval"})
+		return
+	}
 	ctx.JSON(http.StatusOK, gin.H{"Transfer": newTransfer})
 }
 
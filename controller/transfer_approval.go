@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type TransferApprovalCon struct {
+	ApprovalUsecase usecase.TransferApprovalUsecase
+}
+
+func NewTransferApprovalController(ApprovalUsecase usecase.TransferApprovalUsecase) *TransferApprovalCon {
+	return &TransferApprovalCon{
+		ApprovalUsecase: ApprovalUsecase,
+	}
+}
+
+type rejectRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *TransferApprovalCon) FindAll(ctx *gin.Context) {
+	approvals, err := c.ApprovalUsecase.FindAll(ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferApprovals": approvals})
+}
+
+func (c *TransferApprovalCon) FindByID(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	approval, err := c.ApprovalUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferApproval": approval})
+}
+
+func (c *TransferApprovalCon) Approve(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	approval, err := c.ApprovalUsecase.Approve(id, userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferApproval": approval})
+}
+
+func (c *TransferApprovalCon) Reject(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req := rejectRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Reason == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+
+	approval, err := c.ApprovalUsecase.Reject(id, userID, req.Reason)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferApproval": approval})
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type TransferBatchCon struct {
+	BatchUsecase usecase.TransferBatchUsecase
+}
+
+func NewTransferBatchController(BatchUsecase usecase.TransferBatchUsecase) *TransferBatchCon {
+	return &TransferBatchCon{
+		BatchUsecase: BatchUsecase,
+	}
+}
+
+// Create accepts either a JSON body or a CSV file uploaded as the "file" form
+// field with the columns from_account_id, to_account_id and amount.
+func (c *TransferBatchCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	insertBatch := model.TransferBatch{}
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+
+		insertBatch.Items, err = parseBatchCSV(file)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		insertBatch.Mode = ctx.PostForm("mode")
+	} else if err := ctx.ShouldBindJSON(&insertBatch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insertBatch.UserID = userID
+
+	newBatch, err := c.BatchUsecase.Submit(insertBatch)
+	var validationErr *usecase.BatchValidationError
+	if errors.As(err, &validationErr) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "items": validationErr.Items})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"TransferBatch": newBatch})
+}
+
+func (c *TransferBatchCon) FindAll(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	batches, err := c.BatchUsecase.FindByUserId(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferBatches": batches})
+}
+
+func (c *TransferBatchCon) FindByID(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	batch, err := c.BatchUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if batch.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "batch does not belong to the current user"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferBatch": batch})
+}
+
+func parseBatchCSV(r io.Reader) ([]model.TransferBatchItem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"from_account_id", "to_account_id", "amount"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv column %s is required", name)
+		}
+	}
+
+	var items []model.TransferBatchItem
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		fromID, err := strconv.ParseInt(record[columns["from_account_id"]], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid from_account_id", line)
+		}
+		toID, err := strconv.ParseInt(record[columns["to_account_id"]], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid to_account_id", line)
+		}
+		amount, err := strconv.ParseFloat(record[columns["amount"]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount", line)
+		}
+
+		items = append(items, model.TransferBatchItem{
+			Line:          line,
+			FromAccountID: fromID,
+			ToAccountID:   toID,
+			Amount:        amount,
+		})
+	}
+
+	return items, nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type TransferLimitCon struct {
+	LimitUsecase   usecase.TransferLimitUsecase
+	AccountUsecase usecase.AccountUsecase
+}
+
+func NewTransferLimitController(LimitUsecase usecase.TransferLimitUsecase, AccountUsecase usecase.AccountUsecase) *TransferLimitCon {
+	return &TransferLimitCon{
+		LimitUsecase:   LimitUsecase,
+		AccountUsecase: AccountUsecase,
+	}
+}
+
+func (c *TransferLimitCon) FindAll(ctx *gin.Context) {
+	limits, err := c.LimitUsecase.FindAll()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferLimits": limits})
+}
+
+func (c *TransferLimitCon) Remaining(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := c.AccountUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if account.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account does not belong to the current user"})
+		return
+	}
+
+	usage, err := c.LimitUsecase.Usage(account)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"TransferLimit": usage})
+}
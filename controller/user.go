@@ -158,3 +158,29 @@ func (c *UserCon) Get(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"users": users})
 }
+
+type roleRequest struct {
+	Role string `json:"role"`
+}
+
+func (c *UserCon) UpdateRole(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req := roleRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userUsecase.UpdateRole(id, req.Role)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"user": user})
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type VirtualAccountCon struct {
+	VirtualAccountUsecase usecase.VirtualAccountUsecase
+}
+
+func NewVirtualAccountController(VirtualAccountUsecase usecase.VirtualAccountUsecase) *VirtualAccountCon {
+	return &VirtualAccountCon{
+		VirtualAccountUsecase: VirtualAccountUsecase,
+	}
+}
+
+func (c *VirtualAccountCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	insertVirtualAccount := model.VirtualAccount{}
+	if err := ctx.ShouldBindJSON(&insertVirtualAccount); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insertVirtualAccount.UserID = userID
+
+	newVirtualAccount, err := c.VirtualAccountUsecase.Save(insertVirtualAccount)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"VirtualAccount": newVirtualAccount})
+}
+
+func (c *VirtualAccountCon) FindAll(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	virtualAccounts, err := c.VirtualAccountUsecase.FindByUserId(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"VirtualAccounts": virtualAccounts})
+}
+
+func (c *VirtualAccountCon) FindByID(ctx *gin.Context) {
+	virtualAccount, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"VirtualAccount": virtualAccount})
+}
+
+func (c *VirtualAccountCon) FindPayments(ctx *gin.Context) {
+	virtualAccount, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	payments, err := c.VirtualAccountUsecase.FindPayments(virtualAccount.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Payments": payments})
+}
+
+func (c *VirtualAccountCon) Close(ctx *gin.Context) {
+	virtualAccount, ok := c.findOwned(ctx)
+	if !ok {
+		return
+	}
+
+	closedVirtualAccount, err := c.VirtualAccountUsecase.Close(virtualAccount.ID, virtualAccount.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"VirtualAccount": closedVirtualAccount})
+}
+
+// Inbound simulates the clearing network delivering a payment to a virtual
+// account number.
+func (c *VirtualAccountCon) Inbound(ctx *gin.Context) {
+	inbound := model.VirtualAccountInbound{}
+	if err := ctx.ShouldBindJSON(&inbound); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := c.VirtualAccountUsecase.Inbound(inbound)
+	if err != nil {
+		var vaErr *usecase.VirtualAccountError
+		var statusErr *usecase.AccountStatusError
+		switch {
+		case errors.As(err, &vaErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"code": vaErr.Code, "error": vaErr.Message})
+		case errors.As(err, &statusErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Payment": payment})
+}
+
+// findOwned loads the virtual account from the :id param and checks that it
+// belongs to the current user.
+func (c *VirtualAccountCon) findOwned(ctx *gin.Context) (model.VirtualAccount, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return model.VirtualAccount{}, false
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.VirtualAccount{}, false
+	}
+
+	virtualAccount, err := c.VirtualAccountUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.VirtualAccount{}, false
+	}
+	if virtualAccount.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "virtual account does not belong to the current user"})
+		return model.VirtualAccount{}, false
+	}
+
+	return virtualAccount, true
+}
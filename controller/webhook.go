@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+type WebhookCon struct {
+	WebhookUsecase usecase.WebhookUsecase
+}
+
+func NewWebhookController(WebhookUsecase usecase.WebhookUsecase) *WebhookCon {
+	return &WebhookCon{
+		WebhookUsecase: WebhookUsecase,
+	}
+}
+
+// updateWebhookRequest only changes the fields that are present
+type updateWebhookRequest struct {
+	URL         *string  `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	Secret      *string  `json:"secret"`
+	Active      *bool    `json:"active"`
+}
+
+func (c *WebhookCon) Create(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	insertSubscription := model.WebhookSubscription{}
+	if err := ctx.ShouldBindJSON(&insertSubscription); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insertSubscription.CreatedBy = userID
+
+	newSubscription, err := c.WebhookUsecase.Save(insertSubscription)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Webhook": newSubscription})
+}
+
+func (c *WebhookCon) FindAll(ctx *gin.Context) {
+	subscriptions, err := c.WebhookUsecase.FindAll()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Webhooks": subscriptions, "EventTypes": model.EventTypes})
+}
+
+func (c *WebhookCon) FindByID(ctx *gin.Context) {
+	subscription, ok := c.find(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Webhook": subscription})
+}
+
+func (c *WebhookCon) Update(ctx *gin.Context) {
+	subscription, ok := c.find(ctx)
+	if !ok {
+		return
+	}
+
+	req := updateWebhookRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.URL != nil {
+		subscription.URL = *req.URL
+	}
+	if req.Description != nil {
+		subscription.Description = *req.Description
+	}
+	if req.Events != nil {
+		subscription.Events = req.Events
+	}
+	if req.Secret != nil {
+		subscription.Secret = *req.Secret
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+
+	updatedSubscription, err := c.WebhookUsecase.Update(subscription)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Webhook": updatedSubscription})
+}
+
+func (c *WebhookCon) Delete(ctx *gin.Context) {
+	subscription, ok := c.find(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := c.WebhookUsecase.Delete(subscription.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully deleted Webhook!"})
+}
+
+// FindDeliveries lists the delivery log, of one subscription when the route
+// has an :id and filtered by the status query parameter.
+func (c *WebhookCon) FindDeliveries(ctx *gin.Context) {
+	var subscriptionID int64
+	if ctx.Param("id") != "" {
+		subscription, ok := c.find(ctx)
+		if !ok {
+			return
+		}
+		subscriptionID = subscription.ID
+	}
+
+	deliveries, err := c.WebhookUsecase.FindDeliveries(subscriptionID, ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Deliveries": deliveries})
+}
+
+func (c *WebhookCon) RetryDelivery(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	delivery, err := c.WebhookUsecase.RetryDelivery(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"Delivery": delivery})
+}
+
+func (c *WebhookCon) find(ctx *gin.Context) (model.WebhookSubscription, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.WebhookSubscription{}, false
+	}
+
+	subscription, err := c.WebhookUsecase.FindById(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return model.WebhookSubscription{}, false
+	}
+	return subscription, true
+}
@@ -0,0 +1,126 @@
+package eventbus
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is dropped, it can resume from its last event ID.
+const subscriberBuffer = 64
+
+// Publisher receives every event published on the bus.
+type Publisher interface {
+	Publish(event model.Event) error
+}
+
+// Bus fans events out to in-process subscribers and forwards them to other
+// publishers. The last events are kept so a subscriber that reconnects can
+// resume after the last event it saw.
+type Bus struct {
+	mu          sync.Mutex
+	recent      []model.Event
+	size        int
+	subscribers map[*Subscription]struct{}
+	forward     []Publisher
+}
+
+// Subscription receives the events matching its filter. Missed holds the
+// buffered events after the requested last event ID, Resumed is false when
+// that ID was too old to resume from.
+type Subscription struct {
+	Missed  []model.Event
+	Resumed bool
+
+	events chan model.Event
+	filter func(event model.Event) bool
+}
+
+// Events is closed when the subscriber fell too far behind or unsubscribed.
+func (s *Subscription) Events() <-chan model.Event {
+	return s.events
+}
+
+func New(size int, forward ...Publisher) *Bus {
+	return &Bus{
+		size:        size,
+		subscribers: map[*Subscription]struct{}{},
+		forward:     forward,
+	}
+}
+
+// Publish implements Publisher. Subscribers never block the publisher, one
+// whose buffer is full is dropped.
+func (b *Bus) Publish(event model.Event) error {
+	b.mu.Lock()
+	b.recent = append(b.recent, event)
+	if len(b.recent) > b.size {
+		b.recent = b.recent[len(b.recent)-b.size:]
+	}
+	for subscription := range b.subscribers {
+		if !subscription.filter(event) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			b.remove(subscription)
+		}
+	}
+	b.mu.Unlock()
+
+	var errs []error
+	for _, publisher := range b.forward {
+		if err := publisher.Publish(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe registers a subscriber for the events matching filter. An empty
+// lastEventID starts with the next event.
+func (b *Bus) Subscribe(filter func(event model.Event) bool, lastEventID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscription := &Subscription{
+		Resumed: lastEventID == "",
+		events:  make(chan model.Event, subscriberBuffer),
+		filter:  filter,
+	}
+
+	if lastEventID != "" {
+		for i, event := range b.recent {
+			if event.ID != lastEventID {
+				continue
+			}
+			subscription.Resumed = true
+			for _, missed := range b.recent[i+1:] {
+				if filter(missed) {
+					subscription.Missed = append(subscription.Missed, missed)
+				}
+			}
+			break
+		}
+	}
+
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+func (b *Bus) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(subscription)
+}
+
+func (b *Bus) remove(subscription *Subscription) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+	delete(b.subscribers, subscription)
+	close(subscription.events)
+}
@@ -0,0 +1,90 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sferawann/test_mnc/eventbus"
+	"github.com/sferawann/test_mnc/model"
+)
+
+type recordingPublisher struct {
+	events []model.Event
+}
+
+func (p *recordingPublisher) Publish(event model.Event) error {
+	p.events = append(p.events, event)
+	return errors.New("webhook queue unavailable")
+}
+
+func event(id int, accountID int64) model.Event {
+	return model.Event{ID: fmt.Sprint("evt_", id), Type: model.EventAccountBalanceChanged, AccountIDs: []int64{accountID}}
+}
+
+func forAccount(accountID int64) func(model.Event) bool {
+	return func(e model.Event) bool {
+		return len(e.AccountIDs) > 0 && e.AccountIDs[0] == accountID
+	}
+}
+
+func TestPublishFiltersAndForwards(t *testing.T) {
+	forward := &recordingPublisher{}
+	bus := eventbus.New(10, forward)
+	subscription := bus.Subscribe(forAccount(1), "")
+
+	if err := bus.Publish(event(1, 2)); err == nil {
+		t.Error("expected the forward error to be returned")
+	}
+	bus.Publish(event(2, 1))
+
+	if got := <-subscription.Events(); got.ID != "evt_2" {
+		t.Errorf("expected evt_2, got %s", got.ID)
+	}
+	if len(subscription.Events()) != 0 {
+		t.Error("expected the event of another account to be filtered out")
+	}
+	if len(forward.events) != 2 {
+		t.Errorf("expected 2 forwarded events, got %d", len(forward.events))
+	}
+
+	bus.Unsubscribe(subscription)
+	if _, ok := <-subscription.Events(); ok {
+		t.Error("expected the channel to be closed after unsubscribing")
+	}
+}
+
+func TestSubscribeResumesAfterLastEventID(t *testing.T) {
+	bus := eventbus.New(3)
+	for i := 1; i <= 5; i++ {
+		bus.Publish(event(i, 1))
+	}
+
+	subscription := bus.Subscribe(forAccount(1), "evt_3")
+	if !subscription.Resumed || len(subscription.Missed) != 2 || subscription.Missed[0].ID != "evt_4" {
+		t.Errorf("expected to resume with evt_4 and evt_5, got %+v", subscription.Missed)
+	}
+
+	// evt_1 is no longer in the replay buffer of 3 events
+	subscription = bus.Subscribe(forAccount(1), "evt_1")
+	if subscription.Resumed || len(subscription.Missed) != 0 {
+		t.Errorf("expected an evicted event ID not to resume, got %+v", subscription)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	bus := eventbus.New(10)
+	slow := bus.Subscribe(forAccount(1), "")
+
+	for i := 0; i < 100; i++ {
+		bus.Publish(event(i, 1))
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received == 0 || received >= 100 {
+		t.Errorf("expected the slow subscriber to be dropped after its buffer filled, received %d", received)
+	}
+}
@@ -0,0 +1,155 @@
+package eventsource
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// Account is an account rebuilt from its event stream. HeldBalance is not
+// part of the stream, holds only reserve funds without moving them.
+type Account struct {
+	State   model.Account `json:"state"`
+	Version int64         `json:"version"`
+	AsOf    time.Time     `json:"as_of"`
+}
+
+// Rehydrate rebuilds an account from snapshot, when it is not nil, and the
+// events that follow it.
+func Rehydrate(snapshot *model.AccountSnapshot, events []model.AccountEvent) (Account, error) {
+	account := Account{}
+	if snapshot != nil {
+		account = Account{State: snapshot.State, Version: snapshot.Version, AsOf: snapshot.AsOf}
+	}
+
+	for _, event := range events {
+		if event.Version <= account.Version {
+			continue
+		}
+		if err := account.Apply(event); err != nil {
+			return Account{}, err
+		}
+	}
+	return account, nil
+}
+
+// Apply moves the account to the state after event, events must be applied
+// in version order without gaps.
+func (a *Account) Apply(event model.AccountEvent) error {
+	if event.Version != a.Version+1 {
+		return fmt.Errorf("account %d: event version %d does not follow version %d", event.AccountID, event.Version, a.Version)
+	}
+	if (a.Version == 0) != (event.Type == model.AccountEventOpened) {
+		return fmt.Errorf("account %d: stream must start with a single %s event, got %s at version %d", event.AccountID, model.AccountEventOpened, event.Type, event.Version)
+	}
+
+	switch event.Type {
+	case model.AccountEventOpened:
+		a.State = model.Account{
+			ID:        event.AccountID,
+			Number:    event.Number,
+			UserID:    event.UserID,
+			Type:      event.AccountType,
+			Currency:  event.Currency,
+			Status:    model.AccountStatusActive,
+			CreatedAt: event.OccurredAt,
+		}
+	case model.AccountEventDeposited, model.AccountEventWithdrawn, model.AccountEventTransferredIn, model.AccountEventTransferredOut:
+		// Dibulatkan ke sen supaya penjumlahan float tidak menumpuk selisih
+		a.State.Balance = math.Round((a.State.Balance+event.Amount)*100) / 100
+	case model.AccountEventFrozen:
+		a.setStatus(model.AccountStatusFrozen, event.Reason)
+	case model.AccountEventUnfrozen:
+		a.setStatus(model.AccountStatusActive, event.Reason)
+	case model.AccountEventMarkedDormant:
+		a.setStatus(model.AccountStatusDormant, event.Reason)
+	case model.AccountEventClosed:
+		a.setStatus(model.AccountStatusClosed, event.Reason)
+		a.State.ClosedAt = event.OccurredAt
+	default:
+		return fmt.Errorf("account %d: unknown event type %s", event.AccountID, event.Type)
+	}
+
+	a.Version = event.Version
+	a.AsOf = event.OccurredAt
+	return nil
+}
+
+func (a *Account) setStatus(status string, reason string) {
+	a.State.Status = status
+	a.State.StatusReason = reason
+}
+
+// Snapshot captures the current state so later rehydrations can start from
+// it.
+func (a Account) Snapshot() model.AccountSnapshot {
+	return model.AccountSnapshot{
+		AccountID: a.State.ID,
+		Version:   a.Version,
+		State:     a.State,
+		AsOf:      a.AsOf,
+	}
+}
+
+// Opened is the first event of the stream of acc, the opening balance follows
+// as a Deposited event of its history row.
+func Opened(acc model.Account) model.AccountEvent {
+	return model.AccountEvent{
+		AccountID:   acc.ID,
+		Type:        model.AccountEventOpened,
+		UserID:      acc.UserID,
+		Number:      acc.Number,
+		AccountType: acc.Type,
+		Currency:    acc.CurrencyCode(),
+		OccurredAt:  acc.CreatedAt,
+	}
+}
+
+// FromHistory maps a history row to the event that moves the balance the same
+// way. Rows of a transfer, its fee and its reversal are transfers, every other
+// row is a deposit or a withdrawal.
+func FromHistory(history model.History) (model.AccountEvent, bool) {
+	if history.Amount == 0 {
+		return model.AccountEvent{}, false
+	}
+
+	eventType := model.AccountEventDeposited
+	switch {
+	case history.Amount > 0 && history.TransferID != 0:
+		eventType = model.AccountEventTransferredIn
+	case history.Amount < 0 && history.TransferID != 0:
+		eventType = model.AccountEventTransferredOut
+	case history.Amount < 0:
+		eventType = model.AccountEventWithdrawn
+	}
+
+	return model.AccountEvent{
+		AccountID:             history.AccountID,
+		Type:                  eventType,
+		Amount:                history.Amount,
+		HistoryID:             history.ID,
+		HistoryType:           history.Type,
+		TransferID:            history.TransferID,
+		CounterpartyAccountID: history.CounterpartyAccountID,
+		OccurredAt:            history.CreatedAt,
+	}, true
+}
+
+var statusEvents = map[string]string{
+	model.AccountStatusActive:  model.AccountEventUnfrozen,
+	model.AccountStatusFrozen:  model.AccountEventFrozen,
+	model.AccountStatusDormant: model.AccountEventMarkedDormant,
+	model.AccountStatusClosed:  model.AccountEventClosed,
+}
+
+// StatusChanged is the event of acc moving to its current status.
+func StatusChanged(acc model.Account, at time.Time) model.AccountEvent {
+	return model.AccountEvent{
+		AccountID:  acc.ID,
+		Type:       statusEvents[acc.CurrentStatus()],
+		Reason:     acc.StatusReason,
+		OccurredAt: at,
+	}
+}
@@ -0,0 +1,110 @@
+package eventsource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/eventsource"
+	"github.com/sferawann/test_mnc/model"
+)
+
+var opened = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func stream() []model.AccountEvent {
+	events := []model.AccountEvent{
+		eventsource.Opened(model.Account{ID: 7, UserID: 3, Number: "4850010000007", Type: model.AccountTypeSavings, CreatedAt: opened}),
+		{Type: model.AccountEventDeposited, Amount: 1000000, OccurredAt: opened},
+		{Type: model.AccountEventTransferredOut, Amount: -250000.10, TransferID: 1, OccurredAt: opened.Add(time.Hour)},
+		{Type: model.AccountEventTransferredIn, Amount: 50000.20, TransferID: 2, OccurredAt: opened.Add(2 * time.Hour)},
+		{Type: model.AccountEventFrozen, Reason: "fraud check", OccurredAt: opened.Add(3 * time.Hour)},
+		{Type: model.AccountEventUnfrozen, Reason: "cleared", OccurredAt: opened.Add(4 * time.Hour)},
+		{Type: model.AccountEventWithdrawn, Amount: -800000.10, OccurredAt: opened.Add(5 * time.Hour)},
+		{Type: model.AccountEventClosed, Reason: "customer request", OccurredAt: opened.Add(6 * time.Hour)},
+	}
+	for i := range events {
+		events[i].AccountID = 7
+		events[i].Version = int64(i + 1)
+	}
+	return events
+}
+
+func TestRehydrate(t *testing.T) {
+	account, err := eventsource.Rehydrate(nil, stream())
+	if err != nil {
+		t.Fatalf("rehydrate failed: %v", err)
+	}
+
+	state := account.State
+	if state.ID != 7 || state.UserID != 3 || state.Currency != model.DefaultCurrency || !state.CreatedAt.Equal(opened) {
+		t.Errorf("unexpected opened state: %+v", state)
+	}
+	if state.Balance != 0 || state.Status != model.AccountStatusClosed || state.StatusReason != "customer request" {
+		t.Errorf("unexpected final state: balance %.2f, status %s (%s)", state.Balance, state.Status, state.StatusReason)
+	}
+	if account.Version != 8 || !state.ClosedAt.Equal(opened.Add(6*time.Hour)) {
+		t.Errorf("unexpected version %d or closed at %s", account.Version, state.ClosedAt)
+	}
+}
+
+func TestRehydrateFromSnapshot(t *testing.T) {
+	events := stream()
+	partial, err := eventsource.Rehydrate(nil, events[:5])
+	if err != nil {
+		t.Fatalf("rehydrate failed: %v", err)
+	}
+	snapshot := partial.Snapshot()
+	if snapshot.Version != 5 || snapshot.State.Balance != 800000.10 || snapshot.State.Status != model.AccountStatusFrozen {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+
+	// The events already in the snapshot are skipped
+	account, err := eventsource.Rehydrate(&snapshot, events)
+	if err != nil {
+		t.Fatalf("rehydrate from snapshot failed: %v", err)
+	}
+	full, _ := eventsource.Rehydrate(nil, events)
+	if account.Version != full.Version || account.State != full.State {
+		t.Errorf("snapshot rehydration differs: %+v vs %+v", account, full)
+	}
+}
+
+func TestApplyRejectsBrokenStreams(t *testing.T) {
+	events := stream()
+
+	if _, err := eventsource.Rehydrate(nil, []model.AccountEvent{events[0], events[2]}); err == nil {
+		t.Error("expected a version gap to be rejected")
+	}
+	if _, err := eventsource.Rehydrate(nil, events[1:]); err == nil {
+		t.Error("expected a stream without Opened to be rejected")
+	}
+
+	account, _ := eventsource.Rehydrate(nil, events[:1])
+	if err := account.Apply(model.AccountEvent{AccountID: 7, Version: 2, Type: "Renamed"}); err == nil {
+		t.Error("expected an unknown event type to be rejected")
+	}
+}
+
+func TestFromHistory(t *testing.T) {
+	tests := []struct {
+		history model.History
+		want    string
+	}{
+		{model.History{Type: model.HistoryTypeDeposit, Amount: 100}, model.AccountEventDeposited},
+		{model.History{Type: model.HistoryTypeInterest, Amount: 5}, model.AccountEventDeposited},
+		{model.History{Type: model.HistoryTypeWithdrawal, Amount: -100}, model.AccountEventWithdrawn},
+		{model.History{Type: model.HistoryTypeTax, Amount: -1}, model.AccountEventWithdrawn},
+		{model.History{Type: model.HistoryTypeTransferOut, Amount: -100, TransferID: 1}, model.AccountEventTransferredOut},
+		{model.History{Type: model.HistoryTypeFee, Amount: -2, TransferID: 1}, model.AccountEventTransferredOut},
+		{model.History{Type: model.HistoryTypeReversal, Amount: 100, TransferID: 2}, model.AccountEventTransferredIn},
+	}
+	for _, tt := range tests {
+		event, ok := eventsource.FromHistory(tt.history)
+		if !ok || event.Type != tt.want || event.Amount != tt.history.Amount || event.HistoryType != tt.history.Type {
+			t.Errorf("%s of %.2f: got %+v, want %s", tt.history.Type, tt.history.Amount, event, tt.want)
+		}
+	}
+
+	if _, ok := eventsource.FromHistory(model.History{Type: model.HistoryTypeAdjustment}); ok {
+		t.Error("expected a row without amount to have no event")
+	}
+}
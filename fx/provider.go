@@ -0,0 +1,20 @@
+package fx
+
+import "fmt"
+
+// RateProvider supplies mid-market exchange rates.
+type RateProvider interface {
+	// Rate returns how many units of to one unit of from buys.
+	Rate(from string, to string) (float64, error)
+	Currencies() ([]string, error)
+}
+
+// UnsupportedCurrencyError is returned for a currency the provider has no
+// rate for.
+type UnsupportedCurrencyError struct {
+	Currency string
+}
+
+func (e *UnsupportedCurrencyError) Error() string {
+	return fmt.Sprintf("unsupported currency: %s", e.Currency)
+}
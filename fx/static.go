@@ -0,0 +1,86 @@
+package fx
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+)
+
+// staticRates is the layout of the rate file, Rates holds the price of one
+// unit of each currency in Base.
+type staticRates struct {
+	Base      string             `json:"base"`
+	Rates     map[string]float64 `json:"rates"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// StaticProvider reads rates from a JSON file. The file is read on every
+// call so rates can be updated without a restart.
+type StaticProvider struct {
+	filePath string
+}
+
+// Rate implements RateProvider
+func (p *StaticProvider) Rate(from string, to string) (float64, error) {
+	rates, err := p.load()
+	if err != nil {
+		return 0, err
+	}
+
+	fromPrice, ok := rates.price(from)
+	if !ok {
+		return 0, &UnsupportedCurrencyError{Currency: from}
+	}
+	toPrice, ok := rates.price(to)
+	if !ok {
+		return 0, &UnsupportedCurrencyError{Currency: to}
+	}
+
+	return fromPrice / toPrice, nil
+}
+
+// Currencies implements RateProvider
+func (p *StaticProvider) Currencies() ([]string, error) {
+	rates, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := []string{rates.Base}
+	for currency := range rates.Rates {
+		if currency != rates.Base {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies[1:])
+	return currencies, nil
+}
+
+func (p *StaticProvider) load() (staticRates, error) {
+	file, err := os.Open(p.filePath)
+	if err != nil {
+		return staticRates{}, err
+	}
+	defer file.Close()
+
+	var rates staticRates
+	if err := json.NewDecoder(file).Decode(&rates); err != nil {
+		return staticRates{}, err
+	}
+	return rates, nil
+}
+
+func (r staticRates) price(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	price, ok := r.Rates[currency]
+	return price, ok && price > 0
+}
+
+func NewStaticProvider(filePath string) RateProvider {
+	return &StaticProvider{
+		filePath: filePath,
+	}
+}
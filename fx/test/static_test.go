@@ -0,0 +1,73 @@
+package fx
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/fx"
+)
+
+const testFilePathRates = "fx_rate.json"
+
+func writeTestRates(t *testing.T) {
+	rates := `{"base":"IDR","rates":{"USD":15000,"SGD":11250}}`
+	if err := os.WriteFile(testFilePathRates, []byte(rates), 0644); err != nil {
+		t.Fatalf("failed to write rate file: %v", err)
+	}
+}
+
+func TestRateStaticProvider(t *testing.T) {
+	writeTestRates(t)
+	defer os.Remove(testFilePathRates)
+
+	provider := fx.NewStaticProvider(testFilePathRates)
+
+	tests := []struct {
+		from string
+		to   string
+		want float64
+	}{
+		{"USD", "IDR", 15000},
+		{"IDR", "USD", 1.0 / 15000},
+		{"USD", "SGD", 15000.0 / 11250},
+		{"IDR", "IDR", 1},
+	}
+	for _, tt := range tests {
+		rate, err := provider.Rate(tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("failed to get rate %s/%s: %v", tt.from, tt.to, err)
+		}
+		if math.Abs(rate-tt.want) > 1e-9 {
+			t.Errorf("incorrect rate %s/%s: got %v, want %v", tt.from, tt.to, rate, tt.want)
+		}
+	}
+
+	// Unknown currency returns UnsupportedCurrencyError
+	_, err := provider.Rate("USD", "XYZ")
+	var currencyErr *fx.UnsupportedCurrencyError
+	if !errors.As(err, &currencyErr) || currencyErr.Currency != "XYZ" {
+		t.Errorf("expected UnsupportedCurrencyError for XYZ, got %v", err)
+	}
+}
+
+func TestCurrenciesStaticProvider(t *testing.T) {
+	writeTestRates(t)
+	defer os.Remove(testFilePathRates)
+
+	currencies, err := fx.NewStaticProvider(testFilePathRates).Currencies()
+	if err != nil {
+		t.Fatalf("failed to get currencies: %v", err)
+	}
+
+	want := []string{"IDR", "SGD", "USD"}
+	if len(currencies) != len(want) {
+		t.Fatalf("incorrect currencies: got %v, want %v", currencies, want)
+	}
+	for i := range want {
+		if currencies[i] != want[i] {
+			t.Errorf("incorrect currencies: got %v, want %v", currencies, want)
+		}
+	}
+}
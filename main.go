@@ -3,13 +3,21 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/sferawann/test_mnc/broker"
+	"github.com/sferawann/test_mnc/cli"
 	"github.com/sferawann/test_mnc/config"
 	"github.com/sferawann/test_mnc/controller"
+	"github.com/sferawann/test_mnc/eventbus"
+	"github.com/sferawann/test_mnc/fx"
+	"github.com/sferawann/test_mnc/model"
 	"github.com/sferawann/test_mnc/repository"
 	"github.com/sferawann/test_mnc/router"
+	"github.com/sferawann/test_mnc/scheduler"
 	"github.com/sferawann/test_mnc/usecase"
+	"github.com/sferawann/test_mnc/webhook"
 )
 
 func main() {
@@ -23,25 +31,210 @@ func main() {
 	hisRepo := repository.NewHistoryRepoImpl("json/history.json")
 	traRepo := repository.NewTransferRepoImpl("json/transfer.json")
 	sesRepo := repository.NewSessionRepoImpl("json/session.json")
+	schRepo := repository.NewScheduledTransferRepoImpl("json/scheduled_transfer.json")
+	schExecRepo := repository.NewScheduledTransferExecutionRepoImpl("json/scheduled_transfer_execution.json")
+	limRepo := repository.NewTransferLimitRepoImpl("json/transfer_limit.json")
+	feeRepo := repository.NewFeeRuleRepoImpl("json/fee_rule.json")
+	holdRepo := repository.NewHoldRepoImpl("json/hold.json")
+	batchRepo := repository.NewTransferBatchRepoImpl("json/transfer_batch.json")
+	apprRepo := repository.NewTransferApprovalRepoImpl("json/transfer_approval.json")
+	auditRepo := repository.NewAuditLogRepoImpl("json/audit_log.json")
+	prodRepo := repository.NewAccountProductRepoImpl("json/account_product.json")
+	accrualRepo := repository.NewInterestAccrualRepoImpl("json/interest_accrual.json")
+	quoteRepo := repository.NewFxQuoteRepoImpl("json/fx_quote.json")
+	benRepo := repository.NewBeneficiaryRepoImpl("json/beneficiary.json")
+	payReqRepo := repository.NewPaymentRequestRepoImpl("json/payment_request.json")
+	merchantRepo := repository.NewMerchantRepoImpl("json/merchant.json")
+	vaRepo := repository.NewVirtualAccountRepoImpl("json/virtual_account.json")
+	vaPaymentRepo := repository.NewVirtualAccountPaymentRepoImpl("json/virtual_account_payment.json")
+	webhookRepo := repository.NewWebhookSubscriptionRepoImpl("json/webhook_subscription.json")
+	deliveryRepo := repository.NewWebhookDeliveryRepoImpl("json/webhook_delivery.json")
+	outboxRepo := repository.NewOutboxEventRepoImpl("json/outbox_event.json")
+	accEventRepo := repository.NewAccountEventRepoImpl("json/account_event.jsonl")
+	snapshotRepo := repository.NewAccountSnapshotRepoImpl("json/account_snapshot.json")
+	dailyBalanceRepo := repository.NewDailyBalanceRepoImpl("json/daily_balance.json")
+	rateProvider := fx.NewStaticProvider(loadConfig.FxRateFile)
+	eventBroker, err := broker.New(loadConfig.OutboxBroker, loadConfig.OutboxBrokerURL, loadConfig.OutboxBrokerTimeout)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	//init usecase
-	userUsecase := usecase.NewUserUsecaseImpl(userRepo)
-	accUsecase := usecase.NewAccountUsecaseImpl(accRepo, userRepo)
+	webhookUsecase := usecase.NewWebhookUsecaseImpl(webhookRepo, deliveryRepo, webhook.Sender{Client: &http.Client{Timeout: loadConfig.WebhookTimeout}}, loadConfig.WebhookMaxAttempts, loadConfig.WebhookRetryBase, loadConfig.WebhookRetryMax)
+	bus := eventbus.New(loadConfig.StreamReplaySize, webhookUsecase)
+	accEventUsecase := usecase.NewAccountEventUsecaseImpl(accEventRepo, snapshotRepo, accRepo, hisRepo, loadConfig.AccountSnapshotEvery)
+	//account streams are only written when event sourcing is turned on
+	var accountEvents usecase.AccountEventRecorder
+	if loadConfig.AccountEventSourcing {
+		accountEvents = accEventUsecase
+	}
+	userUsecase := usecase.NewUserUsecaseImpl(userRepo, outboxRepo)
 	hisUsecase := usecase.NewHistoryUsecaseImpl(hisRepo, userRepo, accRepo)
-	traUsecase := usecase.NewTransferUsecaseImpl(traRepo, userRepo, accRepo, hisRepo)
+	limUsecase := usecase.NewTransferLimitUsecaseImpl(limRepo, hisRepo, traRepo, model.TransferLimit{
+		MaxPerTransaction: loadConfig.LimitMaxPerTransaction,
+		DailyAmount:       loadConfig.LimitDailyAmount,
+		MonthlyAmount:     loadConfig.LimitMonthlyAmount,
+		DailyCount:        loadConfig.LimitDailyCount,
+	})
+	feeUsecase := usecase.NewFeeUsecaseImpl(feeRepo)
+	auditUsecase := usecase.NewAuditUsecaseImpl(auditRepo)
+	prodUsecase := usecase.NewAccountProductUsecaseImpl(prodRepo, hisRepo)
+	traUsecase := usecase.NewTransferUsecaseImpl(usecase.TransferUsecaseConfig{
+		TransferRepo:        traRepo,
+		AccRepo:             accRepo,
+		UserRepo:            userRepo,
+		HisRepo:             hisRepo,
+		LimitUsecase:        limUsecase,
+		FeeUsecase:          feeUsecase,
+		FeeAccountID:        loadConfig.FeeRevenueAccountID,
+		ApprovalRepo:        apprRepo,
+		AuditUsecase:        auditUsecase,
+		ApprovalThreshold:   loadConfig.ApprovalThreshold,
+		ApprovalTTL:         loadConfig.ApprovalTTL,
+		FrozenBlocksCredits: loadConfig.AccountFrozenBlocksCredits,
+		ProductUsecase:      prodUsecase,
+		QuoteRepo:           quoteRepo,
+		HoldRepo:            holdRepo,
+		RequestRepo:         payReqRepo,
+		Events:              bus,
+		OutboxRepo:          outboxRepo,
+		AccountEvents:       accountEvents,
+	})
+	accUsecase := usecase.NewAccountUsecaseImpl(usecase.AccountUsecaseConfig{
+		AccountRepo:         accRepo,
+		UserRepo:            userRepo,
+		HisRepo:             hisRepo,
+		Audit:               auditUsecase,
+		TransferUsecase:     traUsecase,
+		ProductUsecase:      prodUsecase,
+		FrozenBlocksCredits: loadConfig.AccountFrozenBlocksCredits,
+		DormantAfter:        loadConfig.AccountDormantAfter,
+		BankCode:            loadConfig.BankCode,
+		BranchCode:          loadConfig.AccountBranchCode,
+		Events:              bus,
+		OutboxRepo:          outboxRepo,
+		AccountEvents:       accountEvents,
+	})
+	apprUsecase := usecase.NewTransferApprovalUsecaseImpl(apprRepo, traRepo, userRepo, traUsecase, auditUsecase)
 	sesUsecase := usecase.NewSessionUsecaseImpl(sesRepo, userRepo)
-	authUsecase := usecase.NewAuthUsecaseImpl(userRepo, sesRepo)
+	authUsecase := usecase.NewAuthUsecaseImpl(userRepo, sesRepo, outboxRepo)
+	holdUsecase := usecase.NewHoldUsecaseImpl(holdRepo, accRepo, traUsecase, loadConfig.HoldDefaultTTL)
+	batchUsecase := usecase.NewTransferBatchUsecaseImpl(batchRepo, accRepo, hisRepo, traUsecase, feeUsecase, loadConfig.BatchMaxItems)
+	stmtUsecase := usecase.NewStatementUsecaseImpl(accRepo, hisRepo, userRepo, loadConfig.BankCode, loadConfig.StatementDir)
+	balanceUsecase := usecase.NewBalanceUsecaseImpl(accRepo, hisRepo, dailyBalanceRepo)
+	recUsecase := usecase.NewReconciliationUsecaseImpl(accRepo, hisRepo, auditUsecase, bus, accountEvents)
+	fxUsecase := usecase.NewFxQuoteUsecaseImpl(quoteRepo, accRepo, rateProvider, loadConfig.FxSpread, loadConfig.FxQuoteTTL)
+	benUsecase := usecase.NewBeneficiaryUsecaseImpl(benRepo, accRepo, loadConfig.BeneficiaryCoolingOff, loadConfig.BeneficiaryCoolingOffAmount)
+	payReqUsecase := usecase.NewPaymentRequestUsecaseImpl(payReqRepo, accRepo, userRepo, traUsecase, auditUsecase, loadConfig.PaymentRequestTTL)
+	merchantUsecase := usecase.NewMerchantUsecaseImpl(merchantRepo, accRepo, traRepo, traUsecase, auditUsecase, loadConfig.AccountFrozenBlocksCredits)
+	vaNotifier := usecase.HTTPVirtualAccountNotifier{Client: &http.Client{Timeout: loadConfig.VirtualAccountCallbackTimeout}}
+	vaUsecase := usecase.NewVirtualAccountUsecaseImpl(vaRepo, vaPaymentRepo, accRepo, hisRepo, auditUsecase, vaNotifier, loadConfig.BankCode, loadConfig.VirtualAccountTTL, loadConfig.VirtualAccountCallbackMaxAttempts, loadConfig.AccountFrozenBlocksCredits, bus, accountEvents)
+	intUsecase := usecase.NewInterestUsecaseImpl(accRepo, hisRepo, accrualRepo, prodRepo, auditUsecase, loadConfig.InterestExpenseAccountID, loadConfig.InterestTaxRate, bus, accountEvents)
+	streamUsecase := usecase.NewStreamUsecaseImpl(bus, accRepo)
+	outboxUsecase := usecase.NewOutboxUsecaseImpl(outboxRepo, eventBroker, loadConfig.OutboxTopicPrefix, loadConfig.OutboxBatchSize, loadConfig.OutboxRetryBase, loadConfig.OutboxRetryMax)
+	schUsecase := usecase.NewScheduledTransferUsecaseImpl(schRepo, schExecRepo, accRepo, traUsecase, usecase.LogScheduleNotifier{}, loadConfig.SchedulerMaxRetries, loadConfig.SchedulerRetryDelay)
+
+	//accounts created before account numbers existed get one on startup
+	if _, err := accUsecase.AssignNumbers(); err != nil {
+		log.Printf("assign account numbers: %v", err)
+	}
+
+	//transfers that are charged a fee credit FEE_REVENUE_ACCOUNT_ID, it has to exist once fee rules are configured
+	feeRules, err := feeUsecase.FindAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(feeRules) > 0 {
+		if _, err := accRepo.FindById(loadConfig.FeeRevenueAccountID); err != nil {
+			log.Fatalf("%d fee rules are configured but FEE_REVENUE_ACCOUNT_ID %d is not an account: %v", len(feeRules), loadConfig.FeeRevenueAccountID, err)
+		}
+	}
+
+	//the first admin cannot be made through the API, it is promoted from BOOTSTRAP_ADMIN_USERNAME
+	if loadConfig.BootstrapAdminUsername != "" {
+		if _, err := userUsecase.Promote(loadConfig.BootstrapAdminUsername, model.RoleAdmin); err != nil {
+			log.Printf("bootstrap admin %s: %v", loadConfig.BootstrapAdminUsername, err)
+		}
+	}
+
+	//interest is still accrued without an expense account but it cannot be posted
+	if loadConfig.InterestExpenseAccountID == 0 {
+		products, err := prodRepo.FindAll()
+		if err != nil {
+			log.Printf("check interest products: %v", err)
+		}
+		for _, product := range products {
+			if product.InterestRate > 0 {
+				log.Printf("warning: product %s earns interest but INTEREST_EXPENSE_ACCOUNT_ID is not set, interest will not be posted", product.Code)
+			}
+		}
+	}
+
+	//accounts opened before event sourcing was turned on get a stream from their history
+	if loadConfig.AccountEventSourcing {
+		if _, err := accEventUsecase.Import(); err != nil {
+			log.Printf("import account events: %v", err)
+		}
+	}
+
+	//run a command line command instead of the server
+	if len(os.Args) > 1 {
+		app := cli.App{UserUsecase: userUsecase, StatementUsecase: stmtUsecase, ReconciliationUsecase: recUsecase, InterestUsecase: intUsecase, Stdout: os.Stdout}
+		if err := app.Run(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	//init controller
 	userCon := controller.NewUserController(userUsecase)
 	accCon := controller.NewAccountController(accUsecase)
 	hisCon := controller.NewHistoryController(hisUsecase)
-	traCon := controller.NewTransferController(traUsecase, accUsecase)
+	traCon := controller.NewTransferController(traUsecase, accUsecase, benUsecase)
 	sesCon := controller.NewSessionController(sesUsecase)
 	authCon := controller.NewAuthController(authUsecase)
+	schCon := controller.NewScheduledTransferController(schUsecase)
+	limCon := controller.NewTransferLimitController(limUsecase, accUsecase)
+	feeCon := controller.NewFeeController(feeUsecase, accUsecase)
+	holdCon := controller.NewHoldController(holdUsecase, accUsecase)
+	batchCon := controller.NewTransferBatchController(batchUsecase)
+	apprCon := controller.NewTransferApprovalController(apprUsecase)
+	auditCon := controller.NewAuditController(auditUsecase)
+	stmtCon := controller.NewStatementController(stmtUsecase, accUsecase)
+	recCon := controller.NewReconciliationController(recUsecase)
+	prodCon := controller.NewAccountProductController(prodUsecase)
+	intCon := controller.NewInterestController(intUsecase, accUsecase)
+	fxCon := controller.NewFxController(fxUsecase)
+	benCon := controller.NewBeneficiaryController(benUsecase)
+	payReqCon := controller.NewPaymentRequestController(payReqUsecase)
+	merchantCon := controller.NewMerchantController(merchantUsecase)
+	vaCon := controller.NewVirtualAccountController(vaUsecase)
+	webhookCon := controller.NewWebhookController(webhookUsecase)
+	streamCon := controller.NewStreamController(streamUsecase, loadConfig.StreamHeartbeat)
+	outboxCon := controller.NewOutboxController(outboxUsecase)
+	accEventCon := controller.NewAccountEventController(accEventUsecase)
+	balanceCon := controller.NewBalanceController(balanceUsecase, accUsecase)
+
+	//init background jobs
+	jobs := scheduler.NewScheduler(loadConfig.SchedulerInterval)
+	jobs.Register("scheduled-transfers", schUsecase.RunDue)
+	jobs.Register("hold-expiry", holdUsecase.ExpireDue)
+	jobs.Register("approval-expiry", apprUsecase.ExpireDue)
+	jobs.Register("payment-request-expiry", payReqUsecase.ExpireDue)
+	jobs.Register("virtual-account-expiry", vaUsecase.ExpireDue)
+	jobs.Register("virtual-account-callbacks", vaUsecase.DeliverCallbacks)
+	jobs.Register("webhook-deliveries", webhookUsecase.DeliverDue)
+	jobs.Register("outbox-relay", outboxUsecase.Relay)
+	jobs.Register("monthly-statements", stmtUsecase.GenerateMonthly)
+	jobs.RegisterEvery("account-dormancy", time.Hour, accUsecase.MarkDormant)
+	jobs.RegisterEvery("reconciliation", loadConfig.ReconciliationInterval, recUsecase.RunScheduled)
+	jobs.RegisterEvery("interest", time.Hour, intUsecase.RunScheduled)
+	jobs.RegisterEvery("daily-balances", time.Hour, balanceUsecase.SnapshotDaily)
+	jobs.Start()
+	defer jobs.Stop()
 
 	//init routes
-	routes := router.NewRouter(userCon, accCon, hisCon, traCon, sesCon, authCon)
+	routes := router.NewRouter(userCon, accCon, hisCon, traCon, sesCon, authCon, schCon, limCon, feeCon, holdCon, batchCon, apprCon, auditCon, stmtCon, recCon, prodCon, intCon, fxCon, benCon, payReqCon, merchantCon, vaCon, webhookCon, streamCon, outboxCon, accEventCon, balanceCon, userRepo)
 	server := &http.Server{
 		Addr:           ":" + loadConfig.ServerPort,
 		Handler:        routes,
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+// RequireRole only lets users with one of roles through, it must run after
+// AuthMiddleware.
+func RequireRole(userRepo repository.UserRepo, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentUserID, exists := c.Get("currentUserID")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+			return
+		}
+
+		user, err := userRepo.FindById(currentUserID.(int64))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Forbidden"})
+	}
+}
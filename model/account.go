@@ -2,10 +2,57 @@ package model
 
 import "time"
 
+const (
+	AccountTypeSavings        = "savings"
+	AccountTypeChecking       = "checking"
+	AccountTypeTimeDeposit    = "time_deposit"
+	AccountTypeForeignSavings = "foreign_savings"
+
+	AccountStatusActive  = "active"
+	AccountStatusFrozen  = "frozen"
+	AccountStatusDormant = "dormant"
+	AccountStatusClosed  = "closed"
+
+	// DefaultCurrency is the currency of accounts stored before accounts had
+	// one, fee rules and interest are defined in it.
+	DefaultCurrency = "IDR"
+)
+
 type Account struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"id_user"`
-	User      User      `json:"user"`
-	Balance   float64   `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           int64     `json:"id"`
+	Number       string    `json:"account_number"`
+	UserID       int64     `json:"id_user"`
+	User         User      `json:"user"`
+	Type         string    `json:"type"`
+	Currency     string    `json:"currency"`
+	Balance      float64   `json:"balance"`
+	HeldBalance  float64   `json:"held_balance"`
+	Status       string    `json:"status"`
+	StatusReason string    `json:"status_reason"`
+	ClosedAt     time.Time `json:"closed_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AvailableBalance is the ledger balance minus HeldBalance, the funds
+// reserved by active holds.
+func (a Account) AvailableBalance() float64 {
+	return a.Balance - a.HeldBalance
+}
+
+// CurrentStatus returns Status, accounts stored before statuses existed are
+// active.
+func (a Account) CurrentStatus() string {
+	if a.Status == "" {
+		return AccountStatusActive
+	}
+	return a.Status
+}
+
+// CurrencyCode returns Currency, accounts stored before currencies existed
+// are in DefaultCurrency.
+func (a Account) CurrencyCode() string {
+	if a.Currency == "" {
+		return DefaultCurrency
+	}
+	return a.Currency
 }
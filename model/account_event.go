@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+const (
+	AccountEventOpened         = "Opened"
+	AccountEventDeposited      = "Deposited"
+	AccountEventWithdrawn      = "Withdrawn"
+	AccountEventTransferredOut = "TransferredOut"
+	AccountEventTransferredIn  = "TransferredIn"
+	AccountEventFrozen         = "Frozen"
+	AccountEventUnfrozen       = "Unfrozen"
+	AccountEventMarkedDormant  = "MarkedDormant"
+	AccountEventClosed         = "Closed"
+)
+
+// AccountEvent is one entry in the append-only stream of an account, Version
+// counts from 1 per account. Amount is signed like History.Amount, the
+// account fields are only set on Opened.
+type AccountEvent struct {
+	ID                    int64     `json:"id"`
+	AccountID             int64     `json:"id_account"`
+	Version               int64     `json:"version"`
+	Type                  string    `json:"type"`
+	Amount                float64   `json:"amount,omitempty"`
+	HistoryID             int64     `json:"id_history,omitempty"`
+	HistoryType           string    `json:"history_type,omitempty"`
+	TransferID            int64     `json:"id_transfer,omitempty"`
+	CounterpartyAccountID int64     `json:"counterparty_account_id,omitempty"`
+	Reason                string    `json:"reason,omitempty"`
+	UserID                int64     `json:"id_user,omitempty"`
+	Number                string    `json:"account_number,omitempty"`
+	AccountType           string    `json:"account_type,omitempty"`
+	Currency              string    `json:"currency,omitempty"`
+	OccurredAt            time.Time `json:"occurred_at"`
+	RecordedAt            time.Time `json:"recorded_at"`
+}
+
+// AccountSnapshot is the state of an account after Version events, AsOf is
+// when the last of them occurred.
+type AccountSnapshot struct {
+	ID        int64     `json:"id"`
+	AccountID int64     `json:"id_account"`
+	Version   int64     `json:"version"`
+	State     Account   `json:"state"`
+	AsOf      time.Time `json:"as_of"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// AccountProduct holds the rules of an account type, Code matches
+// Account.Type. Zero values mean the rule does not apply, amounts are in the
+// currency of the account and no Currencies means DefaultCurrency only.
+type AccountProduct struct {
+	ID                     int64     `json:"id"`
+	Code                   string    `json:"code"`
+	Name                   string    `json:"name"`
+	Currencies             []string  `json:"currencies"`
+	MinimumBalance         float64   `json:"minimum_balance"`
+	OverdraftLimit         float64   `json:"overdraft_limit"`
+	MonthlyWithdrawalLimit int       `json:"monthly_withdrawal_limit"`
+	InterestRate           float64   `json:"interest_rate"`
+	InterestCompounding    string    `json:"interest_compounding"`
+	InterestDayCount       string    `json:"interest_day_count"`
+	LockInDays             int       `json:"lock_in_days"`
+	Active                 bool      `json:"active"`
+	CreatedAt              time.Time `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type AuditLog struct {
+	ID        int64     `json:"id"`
+	Action    string    `json:"action"`
+	ActorID   int64     `json:"actor_id"`
+	Entity    string    `json:"entity"`
+	EntityID  int64     `json:"entity_id"`
+	Detail    string    `json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
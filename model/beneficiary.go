@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Beneficiary is a saved transfer destination of a user. Verified is set
+// once a transfer to it has completed.
+type Beneficiary struct {
+	ID            int64     `json:"id"`
+	UserID        int64     `json:"id_user"`
+	Nickname      string    `json:"nickname"`
+	AccountID     int64     `json:"id_account"`
+	AccountNumber string    `json:"account_number"`
+	Currency      string    `json:"currency"`
+	Verified      bool      `json:"verified"`
+	VerifiedAt    time.Time `json:"verified_at"`
+	LastUsedAt    time.Time `json:"last_used_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
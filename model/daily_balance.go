@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// DailyBalanceDateLayout is the layout of DailyBalance.Date, days are in
+// local time.
+const DailyBalanceDateLayout = "2006-01-02"
+
+// DailyBalance is the balance of an account at the end of Date, it is the sum
+// of the history rows written until then.
+type DailyBalance struct {
+	ID        int64     `json:"id"`
+	AccountID int64     `json:"id_account"`
+	Date      string    `json:"date"`
+	Balance   float64   `json:"balance"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HistoricalBalance is the balance of an account at At. SnapshotDate is the
+// daily balance it started from, Transactions the history rows added to it.
+type HistoricalBalance struct {
+	AccountID    int64     `json:"id_account"`
+	At           time.Time `json:"at"`
+	Balance      float64   `json:"balance"`
+	Currency     string    `json:"currency"`
+	SnapshotDate string    `json:"snapshot_date,omitempty"`
+	Transactions int       `json:"transactions"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+const (
+	EventTransferCompleted       = "transfer.completed"
+	EventTransferPendingApproval = "transfer.pending_approval"
+	EventTransferReversed        = "transfer.reversed"
+	EventAccountBalanceChanged   = "account.balance_changed"
+)
+
+// EventTypes lists every event type that can be published.
+var EventTypes = []string{
+	EventTransferCompleted,
+	EventTransferPendingApproval,
+	EventTransferReversed,
+	EventAccountBalanceChanged,
+}
+
+// Event is a committed change other services can react to. AccountIDs are
+// the accounts the event is about.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	AccountIDs []int64     `json:"account_ids"`
+	Data       interface{} `json:"data"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// BalanceChange is the data of an account.balance_changed event, one per
+// history row.
+type BalanceChange struct {
+	AccountID    int64   `json:"id_account"`
+	HistoryID    int64   `json:"id_history"`
+	Type         string  `json:"type"`
+	TransferID   int64   `json:"id_transfer"`
+	Amount       float64 `json:"amount"`
+	BalanceAfter float64 `json:"balance_after"`
+	Currency     string  `json:"currency"`
+}
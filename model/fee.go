@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	FeeTypeFlat       = "flat"
+	FeeTypePercentage = "percentage"
+	FeeTypeTiered     = "tiered"
+)
+
+// FeeTier charges Fee for amounts up to and including UpTo, the last tier
+// may leave UpTo at zero to cover every larger amount.
+type FeeTier struct {
+	UpTo float64 `json:"up_to"`
+	Fee  float64 `json:"fee"`
+}
+
+// FeeRule is one entry of the fee schedule. Rules are evaluated in order and
+// the first one matching the source account type and amount range applies.
+type FeeRule struct {
+	ID               int64     `json:"id"`
+	Name             string    `json:"name"`
+	AccountType      string    `json:"account_type"`
+	MinAmount        float64   `json:"min_amount"`
+	MaxAmount        float64   `json:"max_amount"`
+	Type             string    `json:"type"`
+	FlatFee          float64   `json:"flat_fee"`
+	Percentage       float64   `json:"percentage"`
+	MinFee           float64   `json:"min_fee"`
+	MaxFee           float64   `json:"max_fee"`
+	Tiers            []FeeTier `json:"tiers"`
+	FreeForSameOwner bool      `json:"free_for_same_owner"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type FeeQuote struct {
+	FromAccountID int64   `json:"from_account_id"`
+	ToAccountID   int64   `json:"to_account_id"`
+	Amount        float64 `json:"amount"`
+	Fee           float64 `json:"fee"`
+	Total         float64 `json:"total"`
+	FeeRuleID     int64   `json:"id_fee_rule"`
+	FeeRuleName   string  `json:"fee_rule_name"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// FxQuote locks an exchange rate for converting SourceAmount out of an
+// account until ExpiresAt. Rate is the units of ToCurrency bought by one
+// unit of FromCurrency after the spread. TransferID is set once a transfer
+// uses the quote.
+type FxQuote struct {
+	ID            int64     `json:"id"`
+	UserID        int64     `json:"id_user"`
+	FromAccountID int64     `json:"from_account_id"`
+	FromCurrency  string    `json:"from_currency"`
+	ToCurrency    string    `json:"to_currency"`
+	SourceAmount  float64   `json:"source_amount"`
+	TargetAmount  float64   `json:"target_amount"`
+	MidRate       float64   `json:"mid_rate"`
+	Rate          float64   `json:"rate"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	TransferID    int64     `json:"id_transfer"`
+	CreatedAt     time.Time `json:"created_at"`
+}
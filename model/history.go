@@ -2,10 +2,36 @@ package model
 
 import "time"
 
+const (
+	HistoryTypeDeposit     = "deposit"
+	HistoryTypeWithdrawal  = "withdrawal"
+	HistoryTypeTransferIn  = "transfer_in"
+	HistoryTypeTransferOut = "transfer_out"
+	HistoryTypeFee         = "fee"
+	HistoryTypeReversal    = "reversal"
+	HistoryTypeAdjustment  = "adjustment"
+	HistoryTypeInterest    = "interest"
+	HistoryTypeTax         = "tax"
+
+	ChannelTeller         = "teller"
+	ChannelATM            = "atm"
+	ChannelBankTransfer   = "bank_transfer"
+	ChannelMobile         = "mobile"
+	ChannelQRIS           = "qris"
+	ChannelVirtualAccount = "virtual_account"
+)
+
 type History struct {
-	ID        int64     `json:"id"`
-	AccountID int64     `json:"id_account"`
-	Account   Account   `json:"account"`
-	Amount    float64   `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                    int64     `json:"id"`
+	AccountID             int64     `json:"id_account"`
+	Account               Account   `json:"account"`
+	Type                  string    `json:"type"`
+	TransferID            int64     `json:"id_transfer"`
+	CounterpartyAccountID int64     `json:"counterparty_account_id"`
+	Description           string    `json:"description"`
+	Channel               string    `json:"channel"`
+	Reference             string    `json:"reference"`
+	Amount                float64   `json:"amount"`
+	BalanceAfter          float64   `json:"balance_after"`
+	CreatedAt             time.Time `json:"created_at"`
 }
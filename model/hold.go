@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+// Hold reserves Amount of an account. A capture that waits for approval
+// keeps the hold active with TransferID set until the transfer is decided.
+type Hold struct {
+	ID             int64     `json:"id"`
+	AccountID      int64     `json:"id_account"`
+	ToAccountID    int64     `json:"to_account_id"`
+	Amount         float64   `json:"amount"`
+	CapturedAmount float64   `json:"captured_amount"`
+	Reference      string    `json:"reference"`
+	Status         string    `json:"status"`
+	TransferID     int64     `json:"id_transfer"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	InterestSimple   = "simple"
+	InterestCompound = "compound"
+
+	DayCountACT365 = "ACT/365"
+	DayCountACT360 = "ACT/360"
+	DayCountACTACT = "ACT/ACT"
+)
+
+// InterestAccrual is the interest earned by an account for one day, Date is
+// YYYY-MM-DD so an account is accrued at most once per day. PostedAt is set
+// when the month-end posting has paid it out.
+type InterestAccrual struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"id_account"`
+	Date        string    `json:"date"`
+	Balance     float64   `json:"balance"`
+	Rate        float64   `json:"rate"`
+	DayCount    string    `json:"day_count"`
+	Compounding string    `json:"compounding"`
+	Amount      float64   `json:"amount"`
+	HistoryID   int64     `json:"id_history"`
+	PostedAt    time.Time `json:"posted_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (a InterestAccrual) Posted() bool {
+	return !a.PostedAt.IsZero()
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Merchant lets AccountID receive QR payments. Name and City are printed in
+// the QR payload, CategoryCode is the 4 digit ISO 18245 merchant category
+// code.
+type Merchant struct {
+	ID           int64     `json:"id"`
+	UserID       int64     `json:"id_user"`
+	AccountID    int64     `json:"id_account"`
+	Name         string    `json:"name"`
+	City         string    `json:"city"`
+	PostalCode   string    `json:"postal_code"`
+	CategoryCode string    `json:"category_code"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MerchantQR is a generated payment QR payload, a dynamic one carries the
+// amount and bill number and can be paid once.
+type MerchantQR struct {
+	MerchantID int64   `json:"id_merchant"`
+	Payload    string  `json:"payload"`
+	Dynamic    bool    `json:"dynamic"`
+	Amount     float64 `json:"amount"`
+	BillNumber string  `json:"bill_number"`
+}
+
+// QRPayment is a payment of a merchant QR payload. Amount is only used for
+// static payloads.
+type QRPayment struct {
+	Payload       string  `json:"payload"`
+	FromAccountID int64   `json:"from_account_id"`
+	Amount        float64 `json:"amount"`
+}
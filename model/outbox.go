@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DomainEventTransferCreated = "TransferCreated"
+	DomainEventAccountOpened   = "AccountOpened"
+	DomainEventUserRegistered  = "UserRegistered"
+	DomainEventSessionStarted  = "SessionStarted"
+)
+
+const (
+	AggregateTransfer = "transfer"
+	AggregateAccount  = "account"
+	AggregateUser     = "user"
+	AggregateSession  = "session"
+)
+
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusPublished = "published"
+)
+
+// OutboxEvent is a domain event written together with the change it
+// describes, the relay publishes it to the broker afterwards. EventID stays
+// the same on every retry so consumers can drop duplicates.
+type OutboxEvent struct {
+	ID            int64           `json:"id"`
+	EventID       string          `json:"event_id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int64           `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error"`
+	PublishedAt   time.Time       `json:"published_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// TransferCreated is the payload of a TransferCreated event.
+type TransferCreated struct {
+	TransferID     int64     `json:"id_transfer"`
+	FromAccountID  int64     `json:"from_account_id"`
+	ToAccountID    int64     `json:"to_account_id"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+	TargetAmount   float64   `json:"target_amount"`
+	TargetCurrency string    `json:"target_currency"`
+	Fee            float64   `json:"fee"`
+	Status         string    `json:"status"`
+	Channel        string    `json:"channel"`
+	Reference      string    `json:"reference"`
+	ReversalOf     int64     `json:"reversal_of"`
+	InitiatedBy    int64     `json:"initiated_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AccountOpened is the payload of an AccountOpened event.
+type AccountOpened struct {
+	AccountID int64     `json:"id_account"`
+	UserID    int64     `json:"id_user"`
+	Number    string    `json:"number"`
+	Type      string    `json:"type"`
+	Currency  string    `json:"currency"`
+	Balance   float64   `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserRegistered is the payload of a UserRegistered event, it never carries
+// the password hash.
+type UserRegistered struct {
+	UserID    int64     `json:"id_user"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionStarted is the payload of a SessionStarted event, it never carries
+// the session token.
+type SessionStarted struct {
+	SessionID int64     `json:"id_session"`
+	UserID    int64     `json:"id_user"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+const (
+	PaymentRequestStatusPending   = "pending"
+	PaymentRequestStatusPaid      = "paid"
+	PaymentRequestStatusAccepted  = "accepted"
+	PaymentRequestStatusDeclined  = "declined"
+	PaymentRequestStatusCancelled = "cancelled"
+	PaymentRequestStatusExpired   = "expired"
+)
+
+// PaymentRequest asks PayerID to pay Amount into ToAccountID of RequesterID.
+// Accepting it executes a transfer, the status is accepted while that
+// transfer waits for approval. When the transfer is not approved the request
+// is pending again, or expired when its time ran out meanwhile. Requests from
+// one split bill have the ID of the first one as SplitID.
+type PaymentRequest struct {
+	ID            int64     `json:"id"`
+	RequesterID   int64     `json:"id_requester"`
+	ToAccountID   int64     `json:"to_account_id"`
+	PayerID       int64     `json:"id_payer"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Note          string    `json:"note"`
+	SplitID       int64     `json:"split_id"`
+	Status        string    `json:"status"`
+	FromAccountID int64     `json:"from_account_id"`
+	TransferID    int64     `json:"id_transfer"`
+	Reason        string    `json:"reason"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	RespondedAt   time.Time `json:"responded_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SplitBill fans Total out into one payment request per payer. The total is
+// divided equally over the payers, and the requester too when IncludeSelf is
+// set.
+type SplitBill struct {
+	ToAccountID int64   `json:"to_account_id"`
+	Total       float64 `json:"total"`
+	Note        string  `json:"note"`
+	PayerIDs    []int64 `json:"payer_ids"`
+	IncludeSelf bool    `json:"include_self"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ReconciliationItem is an account whose stored balance does not match the
+// sum of its history.
+type ReconciliationItem struct {
+	AccountID       int64   `json:"id_account"`
+	RecordedBalance float64 `json:"recorded_balance"`
+	ComputedBalance float64 `json:"computed_balance"`
+	Difference      float64 `json:"difference"`
+	HistoryCount    int     `json:"history_count"`
+	AdjustmentID    int64   `json:"id_adjustment"`
+}
+
+type ReconciliationReport struct {
+	RunAt           time.Time            `json:"run_at"`
+	AccountsChecked int                  `json:"accounts_checked"`
+	Adjusted        bool                 `json:"adjusted"`
+	Discrepancies   []ReconciliationItem `json:"discrepancies"`
+}
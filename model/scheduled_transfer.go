@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+const (
+	FrequencyOnce    = "once"
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+
+	ScheduleStatusActive    = "active"
+	ScheduleStatusCompleted = "completed"
+	ScheduleStatusCancelled = "cancelled"
+
+	ExecutionStatusSuccess         = "success"
+	ExecutionStatusPendingApproval = "pending_approval"
+	ExecutionStatusSkipped         = "skipped"
+	ExecutionStatusFailed          = "failed"
+)
+
+type ScheduledTransfer struct {
+	ID            int64     `json:"id"`
+	UserID        int64     `json:"id_user"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        float64   `json:"amount"`
+	Frequency     string    `json:"frequency"`
+	StartAt       time.Time `json:"start_at"`
+	EndAt         time.Time `json:"end_at"`
+	MaxRuns       int       `json:"max_runs"`
+	RunCount      int       `json:"run_count"`
+	NextRunAt     time.Time `json:"next_run_at"`
+	Attempts      int       `json:"attempts"`
+	NextRetryAt   time.Time `json:"next_retry_at"`
+	LastError     string    `json:"last_error"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DueAt is when the schedule runs next, the retry of a failed attempt of the
+// current occurrence comes before the occurrence after it.
+func (s ScheduledTransfer) DueAt() time.Time {
+	if !s.NextRetryAt.IsZero() {
+		return s.NextRetryAt
+	}
+	return s.NextRunAt
+}
+
+type ScheduledTransferExecution struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"id_scheduled_transfer"`
+	TransferID          int64     `json:"id_transfer"`
+	Status              string    `json:"status"`
+	Attempts            int       `json:"attempts"`
+	Error               string    `json:"error"`
+	ScheduledFor        time.Time `json:"scheduled_for"`
+	CreatedAt           time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Statement covers the transactions of one account with CreatedAt in
+// [From, To).
+type Statement struct {
+	AccountID      int64     `json:"id_account"`
+	AccountNumber  string    `json:"account_number"`
+	AccountHolder  string    `json:"account_holder"`
+	AccountType    string    `json:"account_type"`
+	BankCode       string    `json:"bank_code"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance float64   `json:"opening_balance"`
+	TotalCredit    float64   `json:"total_credit"`
+	TotalDebit     float64   `json:"total_debit"`
+	ClosingBalance float64   `json:"closing_balance"`
+	Transactions   []History `json:"transactions"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
@@ -2,12 +2,52 @@ package model
 
 import "time"
 
+const (
+	TransferStatusCompleted       = "completed"
+	TransferStatusPendingApproval = "pending_approval"
+	TransferStatusRejected        = "rejected"
+	TransferStatusExpired         = "expired"
+	TransferStatusCancelled       = "cancelled"
+	TransferStatusFailed          = "failed"
+
+	TransferPurposeClosingPayout = "closing_payout"
+)
+
+// Transfer moves Amount in Currency out of the source account and credits
+// TargetAmount in TargetCurrency, the two only differ for FX transfers.
 type Transfer struct {
-	ID            int64     `json:"id"`
-	FromAccountID int64     `json:"from_account_id"`
-	FromAccount   Account   `json:"from_account"`
-	ToAccountID   int64     `json:"to_account_id"`
-	ToAccount     Account   `json:"to_account"`
-	Amount        float64   `json:"amount"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID               int64     `json:"id"`
+	FromAccountID    int64     `json:"from_account_id"`
+	FromAccount      Account   `json:"from_account"`
+	ToAccountID      int64     `json:"to_account_id"`
+	ToAccountNumber  string    `json:"to_account_number"`
+	ToAccount        Account   `json:"to_account"`
+	Amount           float64   `json:"amount"`
+	Currency         string    `json:"currency"`
+	TargetAmount     float64   `json:"target_amount"`
+	TargetCurrency   string    `json:"target_currency"`
+	Rate             float64   `json:"rate"`
+	QuoteID          int64     `json:"id_fx_quote"`
+	BeneficiaryID    int64     `json:"id_beneficiary"`
+	MerchantID       int64     `json:"id_merchant"`
+	HoldID           int64     `json:"id_hold"`
+	PaymentRequestID int64     `json:"id_payment_request"`
+	Channel          string    `json:"channel"`
+	Reference        string    `json:"reference"`
+	Fee              float64   `json:"fee"`
+	ReversalOf       int64     `json:"reversal_of"`
+	Reversed         bool      `json:"reversed"`
+	Status           string    `json:"status"`
+	InitiatedBy      int64     `json:"initiated_by"`
+	Purpose          string    `json:"purpose"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// CreditAmount is the amount credited to the target account, transfers
+// stored before FX transfers existed credit Amount.
+func (t Transfer) CreditAmount() float64 {
+	if t.TargetAmount == 0 {
+		return t.Amount
+	}
+	return t.TargetAmount
 }
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	ApprovalStatusPending   = "pending"
+	ApprovalStatusApproved  = "approved"
+	ApprovalStatusRejected  = "rejected"
+	ApprovalStatusExpired   = "expired"
+	ApprovalStatusCancelled = "cancelled"
+	ApprovalStatusFailed    = "failed"
+)
+
+type TransferApproval struct {
+	ID          int64     `json:"id"`
+	TransferID  int64     `json:"id_transfer"`
+	Transfer    Transfer  `json:"transfer"`
+	RequestedBy int64     `json:"requested_by"`
+	Status      string    `json:"status"`
+	DecidedBy   int64     `json:"decided_by"`
+	Reason      string    `json:"reason"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	DecidedAt   time.Time `json:"decided_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+const (
+	BatchModeAllOrNothing = "all_or_nothing"
+	BatchModeBestEffort   = "best_effort"
+
+	BatchStatusPending    = "pending"
+	BatchStatusProcessing = "processing"
+	BatchStatusCompleted  = "completed"
+	BatchStatusPartial    = "partially_completed"
+	BatchStatusFailed     = "failed"
+
+	BatchStatusPendingApproval = "pending_approval"
+
+	BatchItemStatusPending  = "pending"
+	BatchItemStatusSuccess  = "success"
+	BatchItemStatusFailed   = "failed"
+	BatchItemStatusReversed = "reversed"
+
+	BatchItemStatusPendingApproval = "pending_approval"
+)
+
+type TransferBatchItem struct {
+	Line          int     `json:"line"`
+	FromAccountID int64   `json:"from_account_id"`
+	ToAccountID   int64   `json:"to_account_id"`
+	Amount        float64 `json:"amount"`
+	Status        string  `json:"status"`
+	TransferID    int64   `json:"id_transfer"`
+	Error         string  `json:"error"`
+}
+
+type TransferBatch struct {
+	ID           int64               `json:"id"`
+	UserID       int64               `json:"id_user"`
+	Mode         string              `json:"mode"`
+	Status       string              `json:"status"`
+	Items        []TransferBatchItem `json:"items"`
+	TotalAmount  float64             `json:"total_amount"`
+	SuccessCount int                 `json:"success_count"`
+	FailedCount  int                 `json:"failed_count"`
+	PendingCount int                 `json:"pending_count"`
+	CreatedAt    time.Time           `json:"created_at"`
+	CompletedAt  time.Time           `json:"completed_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// TransferLimit caps the outflow of an account. A limit with UserID set is an
+// override for that user, otherwise it applies to every account of
+// AccountType. Zero values mean unlimited.
+type TransferLimit struct {
+	ID                int64     `json:"id"`
+	AccountType       string    `json:"account_type"`
+	UserID            int64     `json:"id_user"`
+	MaxPerTransaction float64   `json:"max_per_transaction"`
+	DailyAmount       float64   `json:"daily_amount"`
+	MonthlyAmount     float64   `json:"monthly_amount"`
+	DailyCount        int       `json:"daily_count"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// TransferLimitUsage reports how much of a limit has been used, remaining
+// values are -1 when the corresponding limit is unlimited.
+type TransferLimitUsage struct {
+	AccountID           int64         `json:"id_account"`
+	Limit               TransferLimit `json:"limit"`
+	UsedToday           float64       `json:"used_today"`
+	UsedThisMonth       float64       `json:"used_this_month"`
+	CountToday          int           `json:"count_today"`
+	RemainingToday      float64       `json:"remaining_today"`
+	RemainingThisMonth  float64       `json:"remaining_this_month"`
+	RemainingCountToday int           `json:"remaining_count_today"`
+}
@@ -2,11 +2,18 @@ package model
 
 import "time"
 
+const (
+	RoleUser     = "user"
+	RoleApprover = "approver"
+	RoleAdmin    = "admin"
+)
+
 type User struct {
 	ID        int64     `json:"id"`
 	Username  string    `json:"username"`
 	Password  string    `json:"password"`
 	Email     string    `json:"email"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
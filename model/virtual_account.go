@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+const (
+	VirtualAccountStatusActive  = "active"
+	VirtualAccountStatusPaid    = "paid"
+	VirtualAccountStatusExpired = "expired"
+	VirtualAccountStatusClosed  = "closed"
+
+	CallbackStatusPending   = "pending"
+	CallbackStatusDelivered = "delivered"
+	CallbackStatusFailed    = "failed"
+)
+
+// VirtualAccount is a number issued for one invoice, money paid into it is
+// credited to AccountID. A fixed Amount is paid once, a zero Amount accepts
+// any number of payments until it expires or is closed.
+type VirtualAccount struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"id_user"`
+	AccountID   int64     `json:"id_account"`
+	Number      string    `json:"number"`
+	Name        string    `json:"name"`
+	ExternalID  string    `json:"external_id"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"`
+	PaidAmount  float64   `json:"paid_amount"`
+	CallbackURL string    `json:"callback_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	PaidAt      time.Time `json:"paid_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// VirtualAccountPayment is one inbound payment into a virtual account.
+// Reference is the clearing network reference and makes retries idempotent.
+type VirtualAccountPayment struct {
+	ID               int64     `json:"id"`
+	VirtualAccountID int64     `json:"id_virtual_account"`
+	AccountID        int64     `json:"id_account"`
+	Amount           float64   `json:"amount"`
+	Reference        string    `json:"reference"`
+	PayerName        string    `json:"payer_name"`
+	HistoryID        int64     `json:"id_history"`
+	CallbackStatus   string    `json:"callback_status"`
+	CallbackAttempts int       `json:"callback_attempts"`
+	CallbackError    string    `json:"callback_error"`
+	CallbackAt       time.Time `json:"callback_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// VirtualAccountInbound is a payment into a virtual account number as sent
+// by the clearing network.
+type VirtualAccountInbound struct {
+	Number    string  `json:"number" binding:"required"`
+	Amount    float64 `json:"amount" binding:"required"`
+	Reference string  `json:"reference" binding:"required"`
+	PayerName string  `json:"payer_name"`
+}
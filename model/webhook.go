@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+
+	// WebhookAllEvents subscribes to every event type
+	WebhookAllEvents = "*"
+)
+
+// WebhookSubscription receives the events listed in Events at URL, signed
+// with Secret. Inactive subscriptions get no new deliveries and pending ones
+// wait until it is active again.
+type WebhookSubscription struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Events      []string  `json:"events"`
+	Secret      string    `json:"secret"`
+	Active      bool      `json:"active"`
+	CreatedBy   int64     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (s WebhookSubscription) Subscribed(eventType string) bool {
+	for _, event := range s.Events {
+		if event == eventType || event == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event queued for one subscription. A delivery that
+// keeps failing is retried with backoff until it is dead.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"id_webhook"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus int             `json:"response_status"`
+	LastError      string          `json:"last_error"`
+	DeliveredAt    time.Time       `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
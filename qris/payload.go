@@ -0,0 +1,213 @@
+package qris
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MerchantAccountGUI identifies merchant account information issued by this
+// bank in tag 26.
+const MerchantAccountGUI = "ID.CO.TESTMNC"
+
+const (
+	tagPayloadFormat   = "00"
+	tagInitiation      = "01"
+	tagMerchantAccount = "26"
+	tagCategoryCode    = "52"
+	tagCurrency        = "53"
+	tagAmount          = "54"
+	tagCountryCode     = "58"
+	tagMerchantName    = "59"
+	tagMerchantCity    = "60"
+	tagPostalCode      = "61"
+	tagAdditionalData  = "62"
+	tagCRC             = "63"
+
+	initiationStatic  = "11"
+	initiationDynamic = "12"
+
+	subtagGUI           = "00"
+	subtagAccountNumber = "01"
+	subtagMerchantID    = "02"
+	subtagBillNumber    = "01"
+)
+
+// numericCurrencies maps ISO 4217 codes to the numeric codes of tag 53.
+var numericCurrencies = map[string]string{
+	"IDR": "360",
+	"USD": "840",
+	"SGD": "702",
+	"EUR": "978",
+	"JPY": "392",
+}
+
+// Payload is a merchant presented QR code. A payload with an Amount is
+// dynamic and meant to be paid once, without one it is static and the payer
+// enters the amount.
+type Payload struct {
+	AccountNumber string
+	MerchantID    string
+	CategoryCode  string
+	Currency      string
+	Amount        float64
+	CountryCode   string
+	MerchantName  string
+	MerchantCity  string
+	PostalCode    string
+	BillNumber    string
+}
+
+func (p Payload) Dynamic() bool {
+	return p.Amount > 0
+}
+
+// Encode builds the EMVCo MPM string of p, ending with its CRC.
+func Encode(p Payload) (string, error) {
+	numeric, ok := numericCurrencies[p.Currency]
+	if !ok {
+		return "", fmt.Errorf("unsupported currency: %s", p.Currency)
+	}
+	if len(p.CategoryCode) != 4 {
+		return "", errors.New("merchant category code must have 4 digits")
+	}
+	if p.MerchantName == "" || len(p.MerchantName) > 25 {
+		return "", errors.New("merchant name must have 1 to 25 characters")
+	}
+	if p.MerchantCity == "" || len(p.MerchantCity) > 15 {
+		return "", errors.New("merchant city must have 1 to 15 characters")
+	}
+
+	var account strings.Builder
+	writeField(&account, subtagGUI, MerchantAccountGUI)
+	writeField(&account, subtagAccountNumber, p.AccountNumber)
+	writeField(&account, subtagMerchantID, p.MerchantID)
+
+	var b strings.Builder
+	writeField(&b, tagPayloadFormat, "01")
+	if p.Dynamic() {
+		writeField(&b, tagInitiation, initiationDynamic)
+	} else {
+		writeField(&b, tagInitiation, initiationStatic)
+	}
+	writeField(&b, tagMerchantAccount, account.String())
+	writeField(&b, tagCategoryCode, p.CategoryCode)
+	writeField(&b, tagCurrency, numeric)
+	if p.Dynamic() {
+		writeField(&b, tagAmount, strconv.FormatFloat(p.Amount, 'f', -1, 64))
+	}
+	countryCode := p.CountryCode
+	if countryCode == "" {
+		countryCode = "ID"
+	}
+	writeField(&b, tagCountryCode, countryCode)
+	writeField(&b, tagMerchantName, p.MerchantName)
+	writeField(&b, tagMerchantCity, p.MerchantCity)
+	if p.PostalCode != "" {
+		writeField(&b, tagPostalCode, p.PostalCode)
+	}
+	if p.BillNumber != "" {
+		var additional strings.Builder
+		writeField(&additional, subtagBillNumber, p.BillNumber)
+		writeField(&b, tagAdditionalData, additional.String())
+	}
+
+	if b.Len() > 512-8 {
+		return "", errors.New("payload is longer than 512 characters")
+	}
+
+	b.WriteString(tagCRC + "04")
+	return b.String() + fmt.Sprintf("%04X", CRC16(b.String())), nil
+}
+
+// Decode parses an EMVCo MPM string issued by this bank and verifies its
+// CRC.
+func Decode(s string) (Payload, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 8 || s[len(s)-8:len(s)-4] != tagCRC+"04" {
+		return Payload{}, errors.New("payload does not end with a CRC")
+	}
+	want := fmt.Sprintf("%04X", CRC16(s[:len(s)-4]))
+	if !strings.EqualFold(s[len(s)-4:], want) {
+		return Payload{}, errors.New("payload CRC does not match, the QR code may be damaged")
+	}
+
+	fields, err := parseFields(s[:len(s)-8])
+	if err != nil {
+		return Payload{}, err
+	}
+
+	var p Payload
+	var initiation, numeric string
+	var ours bool
+	for _, f := range fields {
+		switch f.id {
+		case tagPayloadFormat:
+			if f.value != "01" {
+				return Payload{}, fmt.Errorf("unsupported payload format %s", f.value)
+			}
+		case tagInitiation:
+			initiation = f.value
+		case tagMerchantAccount:
+			account, err := parseFields(f.value)
+			if err != nil {
+				return Payload{}, err
+			}
+			for _, sub := range account {
+				switch sub.id {
+				case subtagGUI:
+					ours = sub.value == MerchantAccountGUI
+				case subtagAccountNumber:
+					p.AccountNumber = sub.value
+				case subtagMerchantID:
+					p.MerchantID = sub.value
+				}
+			}
+		case tagCategoryCode:
+			p.CategoryCode = f.value
+		case tagCurrency:
+			numeric = f.value
+		case tagAmount:
+			p.Amount, err = strconv.ParseFloat(f.value, 64)
+			if err != nil || p.Amount <= 0 {
+				return Payload{}, fmt.Errorf("invalid amount %q", f.value)
+			}
+		case tagCountryCode:
+			p.CountryCode = f.value
+		case tagMerchantName:
+			p.MerchantName = f.value
+		case tagMerchantCity:
+			p.MerchantCity = f.value
+		case tagPostalCode:
+			p.PostalCode = f.value
+		case tagAdditionalData:
+			additional, err := parseFields(f.value)
+			if err != nil {
+				return Payload{}, err
+			}
+			for _, sub := range additional {
+				if sub.id == subtagBillNumber {
+					p.BillNumber = sub.value
+				}
+			}
+		}
+	}
+
+	if !ours {
+		return Payload{}, errors.New("QR code is not issued by this bank")
+	}
+	if initiation == initiationDynamic && !p.Dynamic() {
+		return Payload{}, errors.New("dynamic QR code has no amount")
+	}
+	for code, n := range numericCurrencies {
+		if n == numeric {
+			p.Currency = code
+		}
+	}
+	if p.Currency == "" {
+		return Payload{}, fmt.Errorf("unsupported currency %s", numeric)
+	}
+
+	return p, nil
+}
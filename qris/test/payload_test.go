@@ -0,0 +1,100 @@
+package qris
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sferawann/test_mnc/qris"
+)
+
+func TestCRC16(t *testing.T) {
+	// Check value of CRC-16/CCITT-FALSE
+	if got := qris.CRC16("123456789"); got != 0x29B1 {
+		t.Errorf("incorrect crc: got %04X, want %04X", got, 0x29B1)
+	}
+}
+
+func TestEncodeDecodeStatic(t *testing.T) {
+	payload, err := qris.Encode(qris.Payload{
+		AccountNumber: "4850010000017",
+		MerchantID:    "1",
+		CategoryCode:  "5812",
+		Currency:      "IDR",
+		MerchantName:  "Warung Sedap",
+		MerchantCity:  "Jakarta",
+	})
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	if !strings.HasPrefix(payload, "000201010211") {
+		t.Errorf("static payload must start with point of initiation 11: %s", payload)
+	}
+
+	decoded, err := qris.Decode(payload)
+	if err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if decoded.Dynamic() {
+		t.Errorf("static payload decoded as dynamic")
+	}
+	if decoded.AccountNumber != "4850010000017" || decoded.MerchantID != "1" || decoded.Currency != "IDR" {
+		t.Errorf("incorrect payload decoded: %+v", decoded)
+	}
+	if decoded.MerchantName != "Warung Sedap" || decoded.MerchantCity != "Jakarta" || decoded.CountryCode != "ID" {
+		t.Errorf("incorrect merchant decoded: %+v", decoded)
+	}
+}
+
+func TestEncodeDecodeDynamic(t *testing.T) {
+	payload, err := qris.Encode(qris.Payload{
+		AccountNumber: "4850010000017",
+		MerchantID:    "1",
+		CategoryCode:  "5812",
+		Currency:      "IDR",
+		Amount:        25000.5,
+		MerchantName:  "Warung Sedap",
+		MerchantCity:  "Jakarta",
+		BillNumber:    "INV-001",
+	})
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+
+	decoded, err := qris.Decode(payload)
+	if err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if !decoded.Dynamic() || decoded.Amount != 25000.5 {
+		t.Errorf("incorrect amount: got %v, want %v", decoded.Amount, 25000.5)
+	}
+	if decoded.BillNumber != "INV-001" {
+		t.Errorf("incorrect bill number: got %s, want %s", decoded.BillNumber, "INV-001")
+	}
+}
+
+func TestDecodeRejectsDamagedPayload(t *testing.T) {
+	payload, err := qris.Encode(qris.Payload{
+		AccountNumber: "4850010000017",
+		MerchantID:    "1",
+		CategoryCode:  "5812",
+		Currency:      "IDR",
+		Amount:        25000,
+		MerchantName:  "Warung Sedap",
+		MerchantCity:  "Jakarta",
+	})
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+
+	// Ubah nominal tanpa menghitung ulang CRC
+	tampered := strings.Replace(payload, "540525000", "540599000", 1)
+	if tampered == payload {
+		t.Fatalf("amount field not found in %s", payload)
+	}
+	if _, err := qris.Decode(tampered); err == nil {
+		t.Errorf("expected tampered payload to be rejected")
+	}
+	if _, err := qris.Decode(payload[:len(payload)-8]); err == nil {
+		t.Errorf("expected payload without crc to be rejected")
+	}
+}
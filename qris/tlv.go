@@ -0,0 +1,57 @@
+package qris
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// field is one ID-length-value element of an EMVCo payload.
+type field struct {
+	id    string
+	value string
+}
+
+func writeField(b *strings.Builder, id string, value string) {
+	fmt.Fprintf(b, "%s%02d%s", id, len(value), value)
+}
+
+// parseFields splits s into its top level fields.
+func parseFields(s string) ([]field, error) {
+	var fields []field
+	for i := 0; i < len(s); {
+		if len(s)-i < 4 {
+			return nil, fmt.Errorf("truncated field at position %d", i)
+		}
+
+		id := s[i : i+2]
+		length, err := strconv.Atoi(s[i+2 : i+4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid length of field %s", id)
+		}
+		i += 4
+
+		if len(s)-i < length {
+			return nil, fmt.Errorf("field %s is longer than the payload", id)
+		}
+		fields = append(fields, field{id: id, value: s[i : i+length]})
+		i += length
+	}
+	return fields, nil
+}
+
+// CRC16 is the CRC-16/CCITT-FALSE checksum EMVCo uses for tag 63.
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
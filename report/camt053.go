@@ -0,0 +1,171 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+const camtDateTime = "2006-01-02T15:04:05"
+
+type camtDocument struct {
+	XMLName xml.Name     `xml:"Document"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Stmt    camtBkToCstm `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstm struct {
+	GrpHdr camtGrpHdr    `xml:"GrpHdr"`
+	Stmt   camtStatement `xml:"Stmt"`
+}
+
+type camtGrpHdr struct {
+	MsgId   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type camtStatement struct {
+	Id      string         `xml:"Id"`
+	CreDtTm string         `xml:"CreDtTm"`
+	FrToDt  camtFrToDt     `xml:"FrToDt"`
+	Acct    camtAcct       `xml:"Acct"`
+	Bal     []camtBalance  `xml:"Bal"`
+	Summary camtTxsSummary `xml:"TxsSummry"`
+	Ntry    []camtEntry    `xml:"Ntry"`
+}
+
+type camtFrToDt struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAcct struct {
+	Id   string `xml:"Id>Othr>Id"`
+	Ccy  string `xml:"Ccy"`
+	Ownr string `xml:"Ownr>Nm,omitempty"`
+	Svcr string `xml:"Svcr>FinInstnId>ClrSysMmbId>MmbId,omitempty"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Dt        string     `xml:"Dt>Dt"`
+}
+
+type camtTxsSummary struct {
+	Total  camtNumberOfEntries `xml:"TtlNtries"`
+	Credit camtNumberOfEntries `xml:"TtlCdtNtries"`
+	Debit  camtNumberOfEntries `xml:"TtlDbtNtries"`
+}
+
+type camtNumberOfEntries struct {
+	NbOfNtries int    `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	NtryRef   string     `xml:"NtryRef"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Sts       string     `xml:"Sts"`
+	BookgDt   string     `xml:"BookgDt>DtTm"`
+	ValDt     string     `xml:"ValDt>Dt"`
+	BkTxCd    string     `xml:"BkTxCd>Prtry>Cd"`
+	TxId      string     `xml:"NtryDtls>TxDtls>Refs>TxId,omitempty"`
+	Info      string     `xml:"NtryDtls>TxDtls>AddtlTxInf,omitempty"`
+}
+
+// WriteStatementCAMT053 renders statement as an ISO 20022 camt.053 bank to
+// customer statement.
+func WriteStatementCAMT053(w io.Writer, statement model.Statement) error {
+	id := fmt.Sprintf("%d-%s", statement.AccountID, statement.From.Format("20060102"))
+	created := statement.GeneratedAt.Format(camtDateTime)
+
+	doc := camtDocument{
+		Xmlns: camt053Namespace,
+		Stmt: camtBkToCstm{
+			GrpHdr: camtGrpHdr{MsgId: "STMT-" + id, CreDtTm: created},
+			Stmt: camtStatement{
+				Id:      id,
+				CreDtTm: created,
+				FrToDt: camtFrToDt{
+					FrDtTm: statement.From.Format(camtDateTime),
+					ToDtTm: statement.To.Add(-time.Second).Format(camtDateTime),
+				},
+				Acct: camtAcct{
+					Id:   accountIdentifier(statement),
+					Ccy:  statement.Currency,
+					Ownr: statement.AccountHolder,
+					Svcr: statement.BankCode,
+				},
+				Bal: []camtBalance{
+					camtBal("OPBD", statement.OpeningBalance, statement.From, statement.Currency),
+					camtBal("CLBD", statement.ClosingBalance, periodEnd(statement), statement.Currency),
+				},
+			},
+		},
+	}
+
+	summary := &doc.Stmt.Stmt.Summary
+	for _, history := range statement.Transactions {
+		indicator, amount := "CRDT", history.Amount
+		if amount < 0 {
+			indicator, amount = "DBIT", -amount
+			summary.Debit.NbOfNtries++
+		} else {
+			summary.Credit.NbOfNtries++
+		}
+
+		doc.Stmt.Stmt.Ntry = append(doc.Stmt.Stmt.Ntry, camtEntry{
+			NtryRef:   strconv.FormatInt(history.ID, 10),
+			Amt:       camtAmount{Ccy: statement.Currency, Value: formatAmount(amount)},
+			CdtDbtInd: indicator,
+			Sts:       "BOOK",
+			BookgDt:   history.CreatedAt.Format(camtDateTime),
+			ValDt:     history.CreatedAt.Format(dateLayout),
+			BkTxCd:    history.Type,
+			TxId:      formatID(history.TransferID),
+			Info:      history.Description,
+		})
+	}
+	summary.Total.NbOfNtries = len(statement.Transactions)
+	summary.Total.Sum = formatAmount(statement.TotalCredit + statement.TotalDebit)
+	summary.Credit.Sum = formatAmount(statement.TotalCredit)
+	summary.Debit.Sum = formatAmount(statement.TotalDebit)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func camtBal(code string, balance float64, date time.Time, currency string) camtBalance {
+	indicator := "CRDT"
+	if balance < 0 {
+		indicator, balance = "DBIT", -balance
+	}
+	return camtBalance{
+		Code:      code,
+		Amt:       camtAmount{Ccy: currency, Value: formatAmount(balance)},
+		CdtDbtInd: indicator,
+		Dt:        date.Format(dateLayout),
+	}
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// mt940Line is the maximum length of a line inside an MT940 field.
+const mt940Line = 65
+
+// WriteStatementMT940 renders statement as a SWIFT MT940 customer statement,
+// without the SWIFT message envelope.
+func WriteStatementMT940(w io.Writer, statement model.Statement) error {
+	var b strings.Builder
+	field := func(tag string, value string) {
+		fmt.Fprintf(&b, ":%s:%s\r\n", tag, value)
+	}
+
+	field("20", truncate(fmt.Sprintf("STMT%d%s", statement.AccountID, statement.From.Format("060102")), 16))
+	field("25", mt940Account(statement))
+	field("28C", statement.From.Format("0601")+"/1")
+	field("60F", mt940Balance(statement.OpeningBalance, statement.From.Format("060102"), statement.Currency))
+	for _, history := range statement.Transactions {
+		mark, amount := "C", history.Amount
+		if amount < 0 {
+			mark, amount = "D", -amount
+		}
+
+		reference := "NONREF"
+		if history.TransferID != 0 {
+			reference = strconv.FormatInt(history.TransferID, 10)
+		}
+
+		field("61", fmt.Sprintf("%s%s%s%sN%s%s//%d",
+			history.CreatedAt.Format("060102"),
+			history.CreatedAt.Format("0102"),
+			mark,
+			mt940Amount(amount),
+			mt940TransactionType(history.Type),
+			truncate(reference, 16),
+			history.ID))
+		if history.Description != "" {
+			field("86", truncate(swiftText(history.Description), mt940Line))
+		}
+	}
+	field("62F", mt940Balance(statement.ClosingBalance, periodEnd(statement).Format("060102"), statement.Currency))
+	b.WriteString("-\r\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mt940Account returns the account number, which already starts with the
+// bank code, or bank code/id for accounts that have no number.
+func mt940Account(statement model.Statement) string {
+	if statement.AccountNumber != "" || statement.BankCode == "" {
+		return accountIdentifier(statement)
+	}
+	return statement.BankCode + "/" + accountIdentifier(statement)
+}
+
+func mt940Balance(balance float64, date string, currency string) string {
+	mark := "C"
+	if balance < 0 {
+		mark, balance = "D", -balance
+	}
+	return mark + date + currency + mt940Amount(balance)
+}
+
+// mt940Amount formats amount with a decimal comma, as MT940 requires.
+func mt940Amount(amount float64) string {
+	return strings.Replace(formatAmount(amount), ".", ",", 1)
+}
+
+func mt940TransactionType(historyType string) string {
+	switch historyType {
+	case model.HistoryTypeFee:
+		return "CHG"
+	case model.HistoryTypeDeposit, model.HistoryTypeWithdrawal:
+		return "MSC"
+	}
+	return "TRF"
+}
+
+// swiftText replaces characters outside the SWIFT X character set.
+func swiftText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("/-?:().,'+ ", r):
+			return r
+		}
+		return ' '
+	}, s)
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+const ofxDateTime = "20060102150405"
+
+const ofxHeader = "OFXHEADER:100\r\n" +
+	"DATA:OFXSGML\r\n" +
+	"VERSION:102\r\n" +
+	"SECURITY:NONE\r\n" +
+	"ENCODING:USASCII\r\n" +
+	"CHARSET:1252\r\n" +
+	"COMPRESSION:NONE\r\n" +
+	"OLDFILEUID:NONE\r\n" +
+	"NEWFILEUID:NONE\r\n" +
+	"\r\n"
+
+// WriteStatementOFX renders statement as an OFX 1.0.2 bank statement
+// response, the SGML variant most personal finance tools import.
+func WriteStatementOFX(w io.Writer, statement model.Statement) error {
+	var b strings.Builder
+	b.WriteString(ofxHeader)
+
+	tag := func(name string, value string) {
+		fmt.Fprintf(&b, "<%s>%s\r\n", name, ofxEscape(value))
+	}
+	open := func(name string) { fmt.Fprintf(&b, "<%s>\r\n", name) }
+	end := func(name string) { fmt.Fprintf(&b, "</%s>\r\n", name) }
+
+	open("OFX")
+	open("SIGNONMSGSRSV1")
+	open("SONRS")
+	open("STATUS")
+	tag("CODE", "0")
+	tag("SEVERITY", "INFO")
+	end("STATUS")
+	tag("DTSERVER", ofxDate(statement.GeneratedAt))
+	tag("LANGUAGE", "ENG")
+	end("SONRS")
+	end("SIGNONMSGSRSV1")
+
+	open("BANKMSGSRSV1")
+	open("STMTTRNRS")
+	tag("TRNUID", "0")
+	open("STATUS")
+	tag("CODE", "0")
+	tag("SEVERITY", "INFO")
+	end("STATUS")
+	open("STMTRS")
+	tag("CURDEF", statement.Currency)
+	open("BANKACCTFROM")
+	tag("BANKID", statement.BankCode)
+	tag("ACCTID", accountIdentifier(statement))
+	tag("ACCTTYPE", ofxAccountType(statement.AccountType))
+	end("BANKACCTFROM")
+
+	open("BANKTRANLIST")
+	tag("DTSTART", ofxDate(statement.From))
+	tag("DTEND", ofxDate(statement.To))
+	for _, history := range statement.Transactions {
+		trnType := "CREDIT"
+		if history.Amount < 0 {
+			trnType = "DEBIT"
+		}
+		if history.Type == model.HistoryTypeFee && history.Amount < 0 {
+			trnType = "FEE"
+		}
+
+		open("STMTTRN")
+		tag("TRNTYPE", trnType)
+		tag("DTPOSTED", ofxDate(history.CreatedAt))
+		tag("TRNAMT", formatAmount(history.Amount))
+		tag("FITID", strconv.FormatInt(history.ID, 10))
+		if history.TransferID != 0 {
+			tag("REFNUM", strconv.FormatInt(history.TransferID, 10))
+		}
+		if history.Type != "" {
+			tag("NAME", truncate(history.Type, 32))
+		}
+		if history.Description != "" {
+			tag("MEMO", truncate(history.Description, 255))
+		}
+		end("STMTTRN")
+	}
+	end("BANKTRANLIST")
+
+	open("LEDGERBAL")
+	tag("BALAMT", formatAmount(statement.ClosingBalance))
+	tag("DTASOF", ofxDate(statement.To))
+	end("LEDGERBAL")
+	end("STMTRS")
+	end("STMTTRNRS")
+	end("BANKMSGSRSV1")
+	end("OFX")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func ofxDate(t time.Time) string {
+	return t.Format(ofxDateTime)
+}
+
+func ofxAccountType(accountType string) string {
+	if accountType == model.AccountTypeChecking {
+		return "CHECKING"
+	}
+	return "SAVINGS"
+}
+
+func ofxEscape(s string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return replacer.Replace(s)
+}
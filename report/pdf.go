@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pdfPageWidth   = 595
+	pdfPageHeight  = 842
+	pdfMargin      = 40
+	pdfFontSize    = 8
+	pdfLeading     = 11
+	pdfLinesOnPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// pdfDocument is a minimal text-only PDF writer using the built-in Courier
+// font, monospaced lines are enough to lay out report tables.
+type pdfDocument struct {
+	lines []string
+}
+
+func (d *pdfDocument) println(format string, args ...interface{}) {
+	d.lines = append(d.lines, fmt.Sprintf(format, args...))
+}
+
+func (d *pdfDocument) pages() [][]string {
+	var pages [][]string
+	for start := 0; start < len(d.lines); start += pdfLinesOnPage {
+		end := start + pdfLinesOnPage
+		if end > len(d.lines) {
+			end = len(d.lines)
+		}
+		pages = append(pages, d.lines[start:end])
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+	return pages
+}
+
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages()
+
+	// Objek 1 catalog, 2 pages, 3 font, lalu sepasang page dan content per halaman
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+	for i, lines := range pages {
+		content := pdfContent(lines)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+func pdfContent(lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+	for _, line := range lines {
+		fmt.Fprintf(&b, "(%s) Tj T*\n", pdfEscape(line))
+	}
+	b.WriteString("ET")
+	return b.String()
+}
+
+func pdfEscape(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,188 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatPDF     = "pdf"
+	FormatMT940   = "mt940"
+	FormatCAMT053 = "camt053"
+	FormatOFX     = "ofx"
+)
+
+const dateLayout = "2006-01-02"
+
+type statementFormat struct {
+	contentType string
+	extension   string
+	write       func(w io.Writer, statement model.Statement) error
+}
+
+var statementFormats = map[string]statementFormat{
+	FormatCSV:     {"text/csv", "csv", WriteStatementCSV},
+	FormatPDF:     {"application/pdf", "pdf", WriteStatementPDF},
+	FormatMT940:   {"text/plain", "sta", WriteStatementMT940},
+	FormatCAMT053: {"application/xml", "xml", WriteStatementCAMT053},
+	FormatOFX:     {"application/x-ofx", "ofx", WriteStatementOFX},
+}
+
+// Formats returns the supported statement formats in a stable order.
+func Formats() []string {
+	formats := make([]string, 0, len(statementFormats))
+	for format := range statementFormats {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func Supported(format string) bool {
+	_, ok := statementFormats[format]
+	return ok
+}
+
+// ContentType returns the MIME type of a statement format.
+func ContentType(format string) string {
+	return statementFormats[format].contentType
+}
+
+// FileExtension returns the usual file extension of a statement format.
+func FileExtension(format string) string {
+	return statementFormats[format].extension
+}
+
+// WriteStatement renders statement in the given format.
+func WriteStatement(w io.Writer, statement model.Statement, format string) error {
+	statementFormat, ok := statementFormats[format]
+	if !ok {
+		return &UnsupportedFormatError{Format: format}
+	}
+	return statementFormat.write(w, statement)
+}
+
+type UnsupportedFormatError struct {
+	Format string
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return "unsupported statement format: " + e.Format
+}
+
+func WriteStatementCSV(w io.Writer, statement model.Statement) error {
+	writer := csv.NewWriter(w)
+
+	records := [][]string{
+		{"account", accountIdentifier(statement)},
+		{"account_holder", statement.AccountHolder},
+		{"period", statement.From.Format(dateLayout), periodEnd(statement).Format(dateLayout)},
+		{"opening_balance", formatAmount(statement.OpeningBalance)},
+		{},
+		{"date", "type", "description", "id_transfer", "counterparty_account_id", "debit", "credit", "balance"},
+	}
+	for _, history := range statement.Transactions {
+		debit, credit := splitAmount(history.Amount)
+		records = append(records, []string{
+			history.CreatedAt.Format(time.RFC3339),
+			history.Type,
+			history.Description,
+			formatID(history.TransferID),
+			formatID(history.CounterpartyAccountID),
+			debit,
+			credit,
+			formatAmount(history.BalanceAfter),
+		})
+	}
+	records = append(records,
+		[]string{},
+		[]string{"total_debit", formatAmount(statement.TotalDebit)},
+		[]string{"total_credit", formatAmount(statement.TotalCredit)},
+		[]string{"closing_balance", formatAmount(statement.ClosingBalance)},
+	)
+
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func WriteStatementPDF(w io.Writer, statement model.Statement) error {
+	doc := &pdfDocument{}
+	doc.println("ACCOUNT STATEMENT")
+	doc.println("")
+	doc.println("Account        : %s (%s)", accountIdentifier(statement), statement.AccountType)
+	doc.println("Account holder : %s", statement.AccountHolder)
+	doc.println("Period         : %s - %s", statement.From.Format(dateLayout), periodEnd(statement).Format(dateLayout))
+	doc.println("Generated at   : %s", statement.GeneratedAt.Format("2006-01-02 15:04:05"))
+	doc.println("")
+	doc.println("Opening balance: %18s", formatAmount(statement.OpeningBalance))
+	doc.println("")
+	doc.println("%-16s %-12s %-28s %15s %15s %15s", "Date", "Type", "Description", "Debit", "Credit", "Balance")
+	doc.println("%s", strings.Repeat("-", 106))
+	for _, history := range statement.Transactions {
+		debit, credit := splitAmount(history.Amount)
+		doc.println("%-16s %-12s %-28s %15s %15s %15s",
+			history.CreatedAt.Format("2006-01-02 15:04"),
+			truncate(history.Type, 12),
+			truncate(history.Description, 28),
+			debit, credit,
+			formatAmount(history.BalanceAfter))
+	}
+	if len(statement.Transactions) == 0 {
+		doc.println("No transactions in this period.")
+	}
+	doc.println("%s", strings.Repeat("-", 106))
+	doc.println("Total debit    : %18s", formatAmount(statement.TotalDebit))
+	doc.println("Total credit   : %18s", formatAmount(statement.TotalCredit))
+	doc.println("Closing balance: %18s", formatAmount(statement.ClosingBalance))
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// accountIdentifier returns the account number, or the id for accounts that
+// have no number.
+func accountIdentifier(statement model.Statement) string {
+	if statement.AccountNumber != "" {
+		return statement.AccountNumber
+	}
+	return strconv.FormatInt(statement.AccountID, 10)
+}
+
+// periodEnd returns the last day covered by statement, To is exclusive.
+func periodEnd(statement model.Statement) time.Time {
+	return statement.To.AddDate(0, 0, -1)
+}
+
+func splitAmount(amount float64) (debit string, credit string) {
+	if amount < 0 {
+		return formatAmount(-amount), ""
+	}
+	return "", formatAmount(amount)
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
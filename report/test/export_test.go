@@ -0,0 +1,48 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sferawann/test_mnc/report"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestWriteStatementGolden(t *testing.T) {
+	tests := []struct {
+		format string
+		golden string
+	}{
+		{report.FormatMT940, "statement.sta"},
+		{report.FormatCAMT053, "statement.xml"},
+		{report.FormatOFX, "statement.ofx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := report.WriteStatement(&buf, testStatement, tt.format); err != nil {
+				t.Fatalf("failed to write statement: %v", err)
+			}
+
+			golden := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("%s output does not match %s:\ngot:\n%s\nwant:\n%s", tt.format, golden, buf.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/report"
+)
+
+var testStatement = model.Statement{
+	AccountID:      1,
+	AccountNumber:  "4850010000123433",
+	AccountHolder:  "syahrul",
+	AccountType:    model.AccountTypeSavings,
+	BankCode:       "485",
+	Currency:       "IDR",
+	From:           time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	To:             time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+	OpeningBalance: 1000,
+	TotalCredit:    250,
+	TotalDebit:     100,
+	ClosingBalance: 1150,
+	GeneratedAt:    time.Date(2023, 7, 1, 6, 0, 0, 0, time.UTC),
+	Transactions: []model.History{
+		{ID: 11, AccountID: 1, Type: model.HistoryTypeTransferOut, TransferID: 3, CounterpartyAccountID: 2, Description: "Transfer to account 2", Amount: -100, BalanceAfter: 900, CreatedAt: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)},
+		{ID: 14, AccountID: 1, Type: model.HistoryTypeTransferIn, TransferID: 4, CounterpartyAccountID: 2, Description: "Transfer from account 2", Amount: 250, BalanceAfter: 1150, CreatedAt: time.Date(2023, 6, 9, 10, 0, 0, 0, time.UTC)},
+	},
+}
+
+func TestWriteStatementCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := report.WriteStatement(&buf, testStatement, report.FormatCSV); err != nil {
+		t.Fatalf("failed to write statement: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"account,4850010000123433",
+		"period,2023-06-01,2023-06-30",
+		"opening_balance,1000.00",
+		"2023-06-05T10:00:00Z,transfer_out,Transfer to account 2,3,2,100.00,,900.00",
+		"closing_balance,1150.00",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("statement CSV does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteStatementPDF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := report.WriteStatement(&buf, testStatement, report.FormatPDF); err != nil {
+		t.Fatalf("failed to write statement: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatalf("output is not a PDF document")
+	}
+	if !strings.Contains(out, "Closing balance:            1150.00") {
+		t.Errorf("statement PDF does not contain the closing balance")
+	}
+}
+
+func TestWriteStatementUnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := report.WriteStatement(&buf, testStatement, "xls"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
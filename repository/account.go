@@ -7,6 +7,7 @@ type AccountRepo interface {
 	Update(updatedAccount model.Account) (model.Account, error)
 	Delete(id int64) (model.Account, error)
 	FindById(id int64) (model.Account, error)
+	FindByNumber(number string) (model.Account, error)
 	FindByUserId(userID int64) ([]model.Account, error)
 	FindAll() ([]model.Account, error)
 }
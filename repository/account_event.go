@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// AccountEventRepo is append-only, events are never updated or deleted.
+type AccountEventRepo interface {
+	Append(newEvents []model.AccountEvent) ([]model.AccountEvent, error)
+	FindAll() ([]model.AccountEvent, error)
+	FindByAccountId(accountID int64, afterVersion int64) ([]model.AccountEvent, error)
+}
+
+type AccountSnapshotRepo interface {
+	Save(newSnapshot model.AccountSnapshot) (model.AccountSnapshot, error)
+	FindAll() ([]model.AccountSnapshot, error)
+	FindLatest(accountID int64, asOf time.Time) (model.AccountSnapshot, error)
+}
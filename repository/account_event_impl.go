@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// AccountEventRepoImpl stores one event per line, appending never rewrites
+// the events already in the file.
+type AccountEventRepoImpl struct {
+	filePath string
+}
+
+// Append implements AccountEventRepo. The events get the next versions of
+// their accounts and are written with a single write.
+func (r *AccountEventRepoImpl) Append(newEvents []model.AccountEvent) ([]model.AccountEvent, error) {
+	events, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var lastID int64
+	versions := map[int64]int64{}
+	for _, event := range events {
+		if event.ID > lastID {
+			lastID = event.ID
+		}
+		versions[event.AccountID] = event.Version
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	appended := make([]model.AccountEvent, 0, len(newEvents))
+	for _, event := range newEvents {
+		lastID++
+		versions[event.AccountID]++
+		event.ID = lastID
+		event.Version = versions[event.AccountID]
+		event.RecordedAt = now
+
+		line, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		appended = append(appended, event)
+	}
+
+	file, err := os.OpenFile(r.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return appended, nil
+}
+
+// FindAll implements AccountEventRepo
+func (r *AccountEventRepoImpl) FindAll() ([]model.AccountEvent, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.AccountEvent{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	events := []model.AccountEvent{}
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			var event model.AccountEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return nil, fmt.Errorf("account event line %d: %w", line, err)
+			}
+			events = append(events, event)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// FindByAccountId implements AccountEventRepo
+func (r *AccountEventRepoImpl) FindByAccountId(accountID int64, afterVersion int64) ([]model.AccountEvent, error) {
+	events, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var accountEvents []model.AccountEvent
+	for _, event := range events {
+		if event.AccountID == accountID && event.Version > afterVersion {
+			accountEvents = append(accountEvents, event)
+		}
+	}
+
+	return accountEvents, nil
+}
+
+func NewAccountEventRepoImpl(filePath string) AccountEventRepo {
+	return &AccountEventRepoImpl{
+		filePath: filePath,
+	}
+}
+
+type AccountSnapshotRepoImpl struct {
+	filePath string
+}
+
+// FindAll implements AccountSnapshotRepo
+func (r *AccountSnapshotRepoImpl) FindAll() ([]model.AccountSnapshot, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.AccountSnapshot{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var snapshots []model.AccountSnapshot
+	err = json.NewDecoder(file).Decode(&snapshots)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// FindLatest implements AccountSnapshotRepo, it returns the snapshot with the
+// highest version taken as of asOf, a zero asOf accepts every snapshot.
+func (r *AccountSnapshotRepoImpl) FindLatest(accountID int64, asOf time.Time) (model.AccountSnapshot, error) {
+	snapshots, err := r.FindAll()
+	if err != nil {
+		return model.AccountSnapshot{}, err
+	}
+
+	var latest model.AccountSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.AccountID != accountID || (!asOf.IsZero() && snapshot.AsOf.After(asOf)) {
+			continue
+		}
+		if snapshot.Version > latest.Version {
+			latest = snapshot
+		}
+	}
+
+	if latest.ID == 0 {
+		return model.AccountSnapshot{}, fmt.Errorf("snapshot of account %d not found", accountID)
+	}
+	return latest, nil
+}
+
+// Save implements AccountSnapshotRepo
+func (r *AccountSnapshotRepoImpl) Save(newSnapshot model.AccountSnapshot) (model.AccountSnapshot, error) {
+	snapshots, err := r.FindAll()
+	if err != nil {
+		return model.AccountSnapshot{}, err
+	}
+
+	var maxID int64
+	for _, snapshot := range snapshots {
+		if snapshot.ID > maxID {
+			maxID = snapshot.ID
+		}
+	}
+	newSnapshot.ID = maxID + 1
+	newSnapshot.CreatedAt = time.Now()
+
+	snapshots = append(snapshots, newSnapshot)
+
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return model.AccountSnapshot{}, err
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(snapshots); err != nil {
+		return model.AccountSnapshot{}, err
+	}
+
+	return newSnapshot, nil
+}
+
+func NewAccountSnapshotRepoImpl(filePath string) AccountSnapshotRepo {
+	return &AccountSnapshotRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -74,6 +74,22 @@ func (r *AccountRepoImpl) FindById(id int64) (model.Account, error) {
 	return model.Account{}, fmt.Errorf("account by id: %d not found", id)
 }
 
+// FindByNumber implements AccountRepo
+func (r *AccountRepoImpl) FindByNumber(number string) (model.Account, error) {
+	Accounts, err := r.FindAll()
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	for _, Account := range Accounts {
+		if Account.Number != "" && Account.Number == number {
+			return Account, nil
+		}
+	}
+
+	return model.Account{}, fmt.Errorf("account by number: %s not found", number)
+}
+
 // FindByUserID implements AccountRepo
 func (r *AccountRepoImpl) FindByUserId(userID int64) ([]model.Account, error) {
 	Accounts, err := r.FindAll()
@@ -102,6 +118,10 @@ func (r *AccountRepoImpl) Save(newAccount model.Account) (model.Account, error)
 		return model.Account{}, err
 	}
 
+	if accountNumberTaken(Accounts, newAccount.Number, 0) {
+		return model.Account{}, fmt.Errorf("account number %s already exists", newAccount.Number)
+	}
+
 	newAccount.ID = generateUniqueIDAccount(Accounts)
 	newAccount.CreatedAt = time.Now()
 
@@ -122,6 +142,10 @@ func (r *AccountRepoImpl) Update(updatedAccount model.Account) (model.Account, e
 		return model.Account{}, err
 	}
 
+	if accountNumberTaken(Accounts, updatedAccount.Number, updatedAccount.ID) {
+		return model.Account{}, fmt.Errorf("account number %s already exists", updatedAccount.Number)
+	}
+
 	var found bool
 	for i, Account := range Accounts {
 		if Account.ID == updatedAccount.ID {
@@ -168,6 +192,20 @@ func generateUniqueIDAccount(Accounts []model.Account) int64 {
 	return maxID + 1
 }
 
+// accountNumberTaken keeps account numbers unique, exceptID is the account
+// being updated.
+func accountNumberTaken(Accounts []model.Account, number string, exceptID int64) bool {
+	if number == "" {
+		return false
+	}
+	for _, Account := range Accounts {
+		if Account.Number == number && Account.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
 func NewAccountRepoImpl(filePath string) AccountRepo {
 	return &AccountRepoImpl{
 		filePath: filePath,
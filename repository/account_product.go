@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type AccountProductRepo interface {
+	Save(newProduct model.AccountProduct) (model.AccountProduct, error)
+	Update(updatedProduct model.AccountProduct) (model.AccountProduct, error)
+	Delete(id int64) (model.AccountProduct, error)
+	FindById(id int64) (model.AccountProduct, error)
+	FindByCode(code string) (model.AccountProduct, error)
+	FindAll() ([]model.AccountProduct, error)
+}
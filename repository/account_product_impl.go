@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type AccountProductRepoImpl struct {
+	filePath string
+}
+
+// Delete implements AccountProductRepo
+func (r *AccountProductRepoImpl) Delete(id int64) (model.AccountProduct, error) {
+	products, err := r.FindAll()
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	var deletedProduct model.AccountProduct
+	for i, product := range products {
+		if product.ID == id {
+			deletedProduct = product
+			products = append(products[:i], products[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeProductsToFile(products)
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	return deletedProduct, nil
+}
+
+// FindAll implements AccountProductRepo
+func (r *AccountProductRepoImpl) FindAll() ([]model.AccountProduct, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.AccountProduct{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var products []model.AccountProduct
+	err = json.NewDecoder(file).Decode(&products)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// FindById implements AccountProductRepo
+func (r *AccountProductRepoImpl) FindById(id int64) (model.AccountProduct, error) {
+	products, err := r.FindAll()
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	for _, product := range products {
+		if product.ID == id {
+			return product, nil
+		}
+	}
+
+	return model.AccountProduct{}, fmt.Errorf("transfer product by id: %d not found", id)
+}
+
+// FindByCode implements AccountProductRepo
+func (r *AccountProductRepoImpl) FindByCode(code string) (model.AccountProduct, error) {
+	products, err := r.FindAll()
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	for _, product := range products {
+		if product.Code == code {
+			return product, nil
+		}
+	}
+
+	return model.AccountProduct{}, fmt.Errorf("account product by code: %s not found", code)
+}
+
+// Save implements AccountProductRepo
+func (r *AccountProductRepoImpl) Save(newProduct model.AccountProduct) (model.AccountProduct, error) {
+	products, err := r.FindAll()
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	newProduct.ID = generateUniqueIDAccountProduct(products)
+	newProduct.CreatedAt = time.Now()
+
+	products = append(products, newProduct)
+
+	err = r.writeProductsToFile(products)
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	return newProduct, nil
+}
+
+// Update implements AccountProductRepo
+func (r *AccountProductRepoImpl) Update(updatedProduct model.AccountProduct) (model.AccountProduct, error) {
+	products, err := r.FindAll()
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	var found bool
+	for i, product := range products {
+		if product.ID == updatedProduct.ID {
+			products[i] = updatedProduct
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.AccountProduct{}, fmt.Errorf("transfer product by id: %d not found", updatedProduct.ID)
+	}
+
+	err = r.writeProductsToFile(products)
+	if err != nil {
+		return model.AccountProduct{}, err
+	}
+
+	return updatedProduct, nil
+}
+
+func (r *AccountProductRepoImpl) writeProductsToFile(products []model.AccountProduct) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(products)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDAccountProduct(products []model.AccountProduct) int64 {
+	var maxID int64
+	for _, product := range products {
+		if product.ID > maxID {
+			maxID = product.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewAccountProductRepoImpl(filePath string) AccountProductRepo {
+	return &AccountProductRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type AuditLogRepo interface {
+	Save(newAuditLog model.AuditLog) (model.AuditLog, error)
+	FindByEntity(entity string, entityID int64) ([]model.AuditLog, error)
+	FindAll() ([]model.AuditLog, error)
+}
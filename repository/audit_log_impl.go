@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// AuditLogRepoImpl is append only, audit entries are never updated or deleted.
+type AuditLogRepoImpl struct {
+	filePath string
+}
+
+// FindAll implements AuditLogRepo
+func (r *AuditLogRepoImpl) FindAll() ([]model.AuditLog, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.AuditLog{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var logs []model.AuditLog
+	err = json.NewDecoder(file).Decode(&logs)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// FindByEntity implements AuditLogRepo
+func (r *AuditLogRepoImpl) FindByEntity(entity string, entityID int64) ([]model.AuditLog, error) {
+	logs, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var entityLogs []model.AuditLog
+	for _, log := range logs {
+		if log.Entity == entity && log.EntityID == entityID {
+			entityLogs = append(entityLogs, log)
+		}
+	}
+
+	return entityLogs, nil
+}
+
+// Save implements AuditLogRepo
+func (r *AuditLogRepoImpl) Save(newLog model.AuditLog) (model.AuditLog, error) {
+	logs, err := r.FindAll()
+	if err != nil {
+		return model.AuditLog{}, err
+	}
+
+	var maxID int64
+	for _, log := range logs {
+		if log.ID > maxID {
+			maxID = log.ID
+		}
+	}
+	newLog.ID = maxID + 1
+	newLog.CreatedAt = time.Now()
+
+	logs = append(logs, newLog)
+
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return model.AuditLog{}, err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(logs)
+	if err != nil {
+		return model.AuditLog{}, err
+	}
+
+	return newLog, nil
+}
+
+func NewAuditLogRepoImpl(filePath string) AuditLogRepo {
+	return &AuditLogRepoImpl{
+		filePath: filePath,
+	}
+}
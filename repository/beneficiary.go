@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type BeneficiaryRepo interface {
+	Save(newBeneficiary model.Beneficiary) (model.Beneficiary, error)
+	Update(updatedBeneficiary model.Beneficiary) (model.Beneficiary, error)
+	Delete(id int64) (model.Beneficiary, error)
+	FindById(id int64) (model.Beneficiary, error)
+	FindAll() ([]model.Beneficiary, error)
+	FindByUserId(userID int64) ([]model.Beneficiary, error)
+}
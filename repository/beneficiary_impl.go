@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type BeneficiaryRepoImpl struct {
+	filePath string
+}
+
+// Delete implements BeneficiaryRepo
+func (r *BeneficiaryRepoImpl) Delete(id int64) (model.Beneficiary, error) {
+	beneficiaries, err := r.FindAll()
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	var deletedBeneficiary model.Beneficiary
+	for i, beneficiary := range beneficiaries {
+		if beneficiary.ID == id {
+			deletedBeneficiary = beneficiary
+			beneficiaries = append(beneficiaries[:i], beneficiaries[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeBeneficiariesToFile(beneficiaries)
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	return deletedBeneficiary, nil
+}
+
+// FindAll implements BeneficiaryRepo
+func (r *BeneficiaryRepoImpl) FindAll() ([]model.Beneficiary, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.Beneficiary{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var beneficiaries []model.Beneficiary
+	err = json.NewDecoder(file).Decode(&beneficiaries)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return beneficiaries, nil
+}
+
+// FindById implements BeneficiaryRepo
+func (r *BeneficiaryRepoImpl) FindById(id int64) (model.Beneficiary, error) {
+	beneficiaries, err := r.FindAll()
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	for _, beneficiary := range beneficiaries {
+		if beneficiary.ID == id {
+			return beneficiary, nil
+		}
+	}
+
+	return model.Beneficiary{}, fmt.Errorf("beneficiary by id: %d not found", id)
+}
+
+// FindByUserId implements BeneficiaryRepo
+func (r *BeneficiaryRepoImpl) FindByUserId(userID int64) ([]model.Beneficiary, error) {
+	beneficiaries, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var userBeneficiaries []model.Beneficiary
+	for _, beneficiary := range beneficiaries {
+		if beneficiary.UserID == userID {
+			userBeneficiaries = append(userBeneficiaries, beneficiary)
+		}
+	}
+
+	return userBeneficiaries, nil
+}
+
+// Save implements BeneficiaryRepo
+func (r *BeneficiaryRepoImpl) Save(newBeneficiary model.Beneficiary) (model.Beneficiary, error) {
+	beneficiaries, err := r.FindAll()
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	newBeneficiary.ID = generateUniqueIDBeneficiary(beneficiaries)
+	newBeneficiary.CreatedAt = time.Now()
+
+	beneficiaries = append(beneficiaries, newBeneficiary)
+
+	err = r.writeBeneficiariesToFile(beneficiaries)
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	return newBeneficiary, nil
+}
+
+// Update implements BeneficiaryRepo
+func (r *BeneficiaryRepoImpl) Update(updatedBeneficiary model.Beneficiary) (model.Beneficiary, error) {
+	beneficiaries, err := r.FindAll()
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	var found bool
+	for i, beneficiary := range beneficiaries {
+		if beneficiary.ID == updatedBeneficiary.ID {
+			beneficiaries[i] = updatedBeneficiary
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.Beneficiary{}, fmt.Errorf("beneficiary by id: %d not found", updatedBeneficiary.ID)
+	}
+
+	err = r.writeBeneficiariesToFile(beneficiaries)
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	return updatedBeneficiary, nil
+}
+
+func (r *BeneficiaryRepoImpl) writeBeneficiariesToFile(beneficiaries []model.Beneficiary) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(beneficiaries)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDBeneficiary(beneficiaries []model.Beneficiary) int64 {
+	var maxID int64
+	for _, beneficiary := range beneficiaries {
+		if beneficiary.ID > maxID {
+			maxID = beneficiary.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewBeneficiaryRepoImpl(filePath string) BeneficiaryRepo {
+	return &BeneficiaryRepoImpl{
+		filePath: filePath,
+	}
+}
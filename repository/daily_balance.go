@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type DailyBalanceRepo interface {
+	SaveAll(newBalances []model.DailyBalance) ([]model.DailyBalance, error)
+	FindAll() ([]model.DailyBalance, error)
+	FindByDate(date string) ([]model.DailyBalance, error)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type DailyBalanceRepoImpl struct {
+	filePath string
+}
+
+// FindAll implements DailyBalanceRepo
+func (r *DailyBalanceRepoImpl) FindAll() ([]model.DailyBalance, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.DailyBalance{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var balances []model.DailyBalance
+	err = json.NewDecoder(file).Decode(&balances)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// FindByDate implements DailyBalanceRepo
+func (r *DailyBalanceRepoImpl) FindByDate(date string) ([]model.DailyBalance, error) {
+	balances, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var dateBalances []model.DailyBalance
+	for _, balance := range balances {
+		if balance.Date == date {
+			dateBalances = append(dateBalances, balance)
+		}
+	}
+
+	return dateBalances, nil
+}
+
+// SaveAll implements DailyBalanceRepo, the balances of one day are written
+// with a single write.
+func (r *DailyBalanceRepoImpl) SaveAll(newBalances []model.DailyBalance) ([]model.DailyBalance, error) {
+	balances, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var maxID int64
+	for _, balance := range balances {
+		if balance.ID > maxID {
+			maxID = balance.ID
+		}
+	}
+
+	now := time.Now()
+	saved := make([]model.DailyBalance, 0, len(newBalances))
+	for _, balance := range newBalances {
+		maxID++
+		balance.ID = maxID
+		balance.CreatedAt = now
+		saved = append(saved, balance)
+	}
+	balances = append(balances, saved...)
+
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(balances); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func NewDailyBalanceRepoImpl(filePath string) DailyBalanceRepo {
+	return &DailyBalanceRepoImpl{
+		filePath: filePath,
+	}
+}
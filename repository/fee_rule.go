@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type FeeRuleRepo interface {
+	Save(newFeeRule model.FeeRule) (model.FeeRule, error)
+	Update(updatedFeeRule model.FeeRule) (model.FeeRule, error)
+	Delete(id int64) (model.FeeRule, error)
+	FindById(id int64) (model.FeeRule, error)
+	FindAll() ([]model.FeeRule, error)
+}
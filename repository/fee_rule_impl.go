@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type FeeRuleRepoImpl struct {
+	filePath string
+}
+
+// Delete implements FeeRuleRepo
+func (r *FeeRuleRepoImpl) Delete(id int64) (model.FeeRule, error) {
+	rules, err := r.FindAll()
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+
+	var deletedRule model.FeeRule
+	for i, rule := range rules {
+		if rule.ID == id {
+			deletedRule = rule
+			rules = append(rules[:i], rules[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeRulesToFile(rules)
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+
+	return deletedRule, nil
+}
+
+// FindAll implements FeeRuleRepo
+func (r *FeeRuleRepoImpl) FindAll() ([]model.FeeRule, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.FeeRule{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var rules []model.FeeRule
+	err = json.NewDecoder(file).Decode(&rules)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// FindById implements FeeRuleRepo
+func (r *FeeRuleRepoImpl) FindById(id int64) (model.FeeRule, error) {
+	rules, err := r.FindAll()
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+
+	return model.FeeRule{}, fmt.Errorf("transfer rule by id: %d not found", id)
+}
+
+// Save implements FeeRuleRepo
+func (r *FeeRuleRepoImpl) Save(newRule model.FeeRule) (model.FeeRule, error) {
+	rules, err := r.FindAll()
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+
+	newRule.ID = generateUniqueIDFeeRule(rules)
+	newRule.CreatedAt = time.Now()
+
+	rules = append(rules, newRule)
+
+	err = r.writeRulesToFile(rules)
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+
+	return newRule, nil
+}
+
+// Update implements FeeRuleRepo
+func (r *FeeRuleRepoImpl) Update(updatedRule model.FeeRule) (model.FeeRule, error) {
+	rules, err := r.FindAll()
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+
+	var found bool
+	for i, rule := range rules {
+		if rule.ID == updatedRule.ID {
+			rules[i] = updatedRule
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.FeeRule{}, fmt.Errorf("transfer rule by id: %d not found", updatedRule.ID)
+	}
+
+	err = r.writeRulesToFile(rules)
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+
+	return updatedRule, nil
+}
+
+func (r *FeeRuleRepoImpl) writeRulesToFile(rules []model.FeeRule) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(rules)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDFeeRule(rules []model.FeeRule) int64 {
+	var maxID int64
+	for _, rule := range rules {
+		if rule.ID > maxID {
+			maxID = rule.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewFeeRuleRepoImpl(filePath string) FeeRuleRepo {
+	return &FeeRuleRepoImpl{
+		filePath: filePath,
+	}
+}
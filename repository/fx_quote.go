@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type FxQuoteRepo interface {
+	Save(newQuote model.FxQuote) (model.FxQuote, error)
+	Update(updatedQuote model.FxQuote) (model.FxQuote, error)
+	Delete(id int64) (model.FxQuote, error)
+	FindById(id int64) (model.FxQuote, error)
+	FindAll() ([]model.FxQuote, error)
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type FxQuoteRepoImpl struct {
+	filePath string
+}
+
+// Delete implements FxQuoteRepo
+func (r *FxQuoteRepoImpl) Delete(id int64) (model.FxQuote, error) {
+	quotes, err := r.FindAll()
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+
+	var deletedQuote model.FxQuote
+	for i, quote := range quotes {
+		if quote.ID == id {
+			deletedQuote = quote
+			quotes = append(quotes[:i], quotes[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeQuotesToFile(quotes)
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+
+	return deletedQuote, nil
+}
+
+// FindAll implements FxQuoteRepo
+func (r *FxQuoteRepoImpl) FindAll() ([]model.FxQuote, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.FxQuote{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var quotes []model.FxQuote
+	err = json.NewDecoder(file).Decode(&quotes)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// FindById implements FxQuoteRepo
+func (r *FxQuoteRepoImpl) FindById(id int64) (model.FxQuote, error) {
+	quotes, err := r.FindAll()
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+
+	for _, quote := range quotes {
+		if quote.ID == id {
+			return quote, nil
+		}
+	}
+
+	return model.FxQuote{}, fmt.Errorf("fx quote by id: %d not found", id)
+}
+
+// Save implements FxQuoteRepo
+func (r *FxQuoteRepoImpl) Save(newQuote model.FxQuote) (model.FxQuote, error) {
+	quotes, err := r.FindAll()
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+
+	newQuote.ID = generateUniqueIDFxQuote(quotes)
+	newQuote.CreatedAt = time.Now()
+
+	quotes = append(quotes, newQuote)
+
+	err = r.writeQuotesToFile(quotes)
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+
+	return newQuote, nil
+}
+
+// Update implements FxQuoteRepo
+func (r *FxQuoteRepoImpl) Update(updatedQuote model.FxQuote) (model.FxQuote, error) {
+	quotes, err := r.FindAll()
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+
+	var found bool
+	for i, quote := range quotes {
+		if quote.ID == updatedQuote.ID {
+			quotes[i] = updatedQuote
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.FxQuote{}, fmt.Errorf("fx quote by id: %d not found", updatedQuote.ID)
+	}
+
+	err = r.writeQuotesToFile(quotes)
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+
+	return updatedQuote, nil
+}
+
+func (r *FxQuoteRepoImpl) writeQuotesToFile(quotes []model.FxQuote) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(quotes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDFxQuote(quotes []model.FxQuote) int64 {
+	var maxID int64
+	for _, quote := range quotes {
+		if quote.ID > maxID {
+			maxID = quote.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewFxQuoteRepoImpl(filePath string) FxQuoteRepo {
+	return &FxQuoteRepoImpl{
+		filePath: filePath,
+	}
+}
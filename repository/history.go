@@ -7,5 +7,7 @@ type HistoryRepo interface {
 	Update(updatedHistory model.History) (model.History, error)
 	Delete(id int64) (model.History, error)
 	FindById(id int64) (model.History, error)
+	FindByAccountId(accountID int64) ([]model.History, error)
+	FindByTransferId(transferID int64) ([]model.History, error)
 	FindAll() ([]model.History, error)
 }
@@ -74,6 +74,40 @@ func (r *HistoryRepoImpl) FindById(id int64) (model.History, error) {
 	return model.History{}, fmt.Errorf("history by id: %d not found", id)
 }
 
+// FindByTransferId implements HistoryRepo
+func (r *HistoryRepoImpl) FindByTransferId(transferID int64) ([]model.History, error) {
+	Historys, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var transferHistorys []model.History
+	for _, History := range Historys {
+		if History.TransferID == transferID {
+			transferHistorys = append(transferHistorys, History)
+		}
+	}
+
+	return transferHistorys, nil
+}
+
+// FindByAccountId implements HistoryRepo
+func (r *HistoryRepoImpl) FindByAccountId(accountID int64) ([]model.History, error) {
+	Historys, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var accountHistorys []model.History
+	for _, History := range Historys {
+		if History.AccountID == accountID {
+			accountHistorys = append(accountHistorys, History)
+		}
+	}
+
+	return accountHistorys, nil
+}
+
 // Save implements HistoryRepo
 func (r *HistoryRepoImpl) Save(newHistory model.History) (model.History, error) {
 	Historys, err := r.FindAll()
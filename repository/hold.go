@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type HoldRepo interface {
+	Save(newHold model.Hold) (model.Hold, error)
+	Update(updatedHold model.Hold) (model.Hold, error)
+	Delete(id int64) (model.Hold, error)
+	FindById(id int64) (model.Hold, error)
+	FindByAccountId(accountID int64) ([]model.Hold, error)
+	FindExpired(now time.Time) ([]model.Hold, error)
+	FindAll() ([]model.Hold, error)
+}
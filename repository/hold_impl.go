@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type HoldRepoImpl struct {
+	filePath string
+}
+
+// Delete implements HoldRepo
+func (r *HoldRepoImpl) Delete(id int64) (model.Hold, error) {
+	holds, err := r.FindAll()
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	var deletedHold model.Hold
+	for i, hold := range holds {
+		if hold.ID == id {
+			deletedHold = hold
+			holds = append(holds[:i], holds[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeHoldsToFile(holds)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	return deletedHold, nil
+}
+
+// FindAll implements HoldRepo
+func (r *HoldRepoImpl) FindAll() ([]model.Hold, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.Hold{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var holds []model.Hold
+	err = json.NewDecoder(file).Decode(&holds)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+// FindById implements HoldRepo
+func (r *HoldRepoImpl) FindById(id int64) (model.Hold, error) {
+	holds, err := r.FindAll()
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	for _, hold := range holds {
+		if hold.ID == id {
+			return hold, nil
+		}
+	}
+
+	return model.Hold{}, fmt.Errorf("transfer hold by id: %d not found", id)
+}
+
+// FindByAccountId implements HoldRepo
+func (r *HoldRepoImpl) FindByAccountId(accountID int64) ([]model.Hold, error) {
+	holds, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var accountHolds []model.Hold
+	for _, hold := range holds {
+		if hold.AccountID == accountID {
+			accountHolds = append(accountHolds, hold)
+		}
+	}
+
+	return accountHolds, nil
+}
+
+// FindExpired implements HoldRepo
+func (r *HoldRepoImpl) FindExpired(now time.Time) ([]model.Hold, error) {
+	holds, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var expiredHolds []model.Hold
+	for _, hold := range holds {
+		if hold.Status == model.HoldStatusActive && !hold.ExpiresAt.After(now) {
+			expiredHolds = append(expiredHolds, hold)
+		}
+	}
+
+	return expiredHolds, nil
+}
+
+// Save implements HoldRepo
+func (r *HoldRepoImpl) Save(newHold model.Hold) (model.Hold, error) {
+	holds, err := r.FindAll()
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	newHold.ID = generateUniqueIDHold(holds)
+	newHold.CreatedAt = time.Now()
+
+	holds = append(holds, newHold)
+
+	err = r.writeHoldsToFile(holds)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	return newHold, nil
+}
+
+// Update implements HoldRepo
+func (r *HoldRepoImpl) Update(updatedHold model.Hold) (model.Hold, error) {
+	holds, err := r.FindAll()
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	var found bool
+	for i, hold := range holds {
+		if hold.ID == updatedHold.ID {
+			holds[i] = updatedHold
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.Hold{}, fmt.Errorf("transfer hold by id: %d not found", updatedHold.ID)
+	}
+
+	err = r.writeHoldsToFile(holds)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	return updatedHold, nil
+}
+
+func (r *HoldRepoImpl) writeHoldsToFile(holds []model.Hold) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(holds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDHold(holds []model.Hold) int64 {
+	var maxID int64
+	for _, hold := range holds {
+		if hold.ID > maxID {
+			maxID = hold.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewHoldRepoImpl(filePath string) HoldRepo {
+	return &HoldRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type InterestAccrualRepo interface {
+	Save(newAccrual model.InterestAccrual) (model.InterestAccrual, error)
+	Update(updatedAccrual model.InterestAccrual) (model.InterestAccrual, error)
+	Delete(id int64) (model.InterestAccrual, error)
+	FindById(id int64) (model.InterestAccrual, error)
+	FindAll() ([]model.InterestAccrual, error)
+	FindByAccountId(accountID int64) ([]model.InterestAccrual, error)
+	FindUnposted() ([]model.InterestAccrual, error)
+}
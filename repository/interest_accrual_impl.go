@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type InterestAccrualRepoImpl struct {
+	filePath string
+}
+
+// Delete implements InterestAccrualRepo
+func (r *InterestAccrualRepoImpl) Delete(id int64) (model.InterestAccrual, error) {
+	accruals, err := r.FindAll()
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+
+	var deletedAccrual model.InterestAccrual
+	for i, accrual := range accruals {
+		if accrual.ID == id {
+			deletedAccrual = accrual
+			accruals = append(accruals[:i], accruals[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeAccrualsToFile(accruals)
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+
+	return deletedAccrual, nil
+}
+
+// FindAll implements InterestAccrualRepo
+func (r *InterestAccrualRepoImpl) FindAll() ([]model.InterestAccrual, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.InterestAccrual{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var accruals []model.InterestAccrual
+	err = json.NewDecoder(file).Decode(&accruals)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return accruals, nil
+}
+
+// FindById implements InterestAccrualRepo
+func (r *InterestAccrualRepoImpl) FindById(id int64) (model.InterestAccrual, error) {
+	accruals, err := r.FindAll()
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+
+	for _, accrual := range accruals {
+		if accrual.ID == id {
+			return accrual, nil
+		}
+	}
+
+	return model.InterestAccrual{}, fmt.Errorf("interest accrual by id: %d not found", id)
+}
+
+// FindByAccountId implements InterestAccrualRepo
+func (r *InterestAccrualRepoImpl) FindByAccountId(accountID int64) ([]model.InterestAccrual, error) {
+	accruals, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var accountAccruals []model.InterestAccrual
+	for _, accrual := range accruals {
+		if accrual.AccountID == accountID {
+			accountAccruals = append(accountAccruals, accrual)
+		}
+	}
+
+	return accountAccruals, nil
+}
+
+// FindUnposted implements InterestAccrualRepo
+func (r *InterestAccrualRepoImpl) FindUnposted() ([]model.InterestAccrual, error) {
+	accruals, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var unposted []model.InterestAccrual
+	for _, accrual := range accruals {
+		if !accrual.Posted() {
+			unposted = append(unposted, accrual)
+		}
+	}
+
+	return unposted, nil
+}
+
+// Save implements InterestAccrualRepo
+func (r *InterestAccrualRepoImpl) Save(newAccrual model.InterestAccrual) (model.InterestAccrual, error) {
+	accruals, err := r.FindAll()
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+
+	newAccrual.ID = generateUniqueIDInterestAccrual(accruals)
+	newAccrual.CreatedAt = time.Now()
+
+	accruals = append(accruals, newAccrual)
+
+	err = r.writeAccrualsToFile(accruals)
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+
+	return newAccrual, nil
+}
+
+// Update implements InterestAccrualRepo
+func (r *InterestAccrualRepoImpl) Update(updatedAccrual model.InterestAccrual) (model.InterestAccrual, error) {
+	accruals, err := r.FindAll()
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+
+	var found bool
+	for i, accrual := range accruals {
+		if accrual.ID == updatedAccrual.ID {
+			accruals[i] = updatedAccrual
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.InterestAccrual{}, fmt.Errorf("interest accrual by id: %d not found", updatedAccrual.ID)
+	}
+
+	err = r.writeAccrualsToFile(accruals)
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+
+	return updatedAccrual, nil
+}
+
+func (r *InterestAccrualRepoImpl) writeAccrualsToFile(accruals []model.InterestAccrual) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(accruals)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDInterestAccrual(accruals []model.InterestAccrual) int64 {
+	var maxID int64
+	for _, accrual := range accruals {
+		if accrual.ID > maxID {
+			maxID = accrual.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewInterestAccrualRepoImpl(filePath string) InterestAccrualRepo {
+	return &InterestAccrualRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type MerchantRepo interface {
+	Save(newMerchant model.Merchant) (model.Merchant, error)
+	Update(updatedMerchant model.Merchant) (model.Merchant, error)
+	Delete(id int64) (model.Merchant, error)
+	FindById(id int64) (model.Merchant, error)
+	FindAll() ([]model.Merchant, error)
+	FindByUserId(userID int64) ([]model.Merchant, error)
+	FindByAccountId(accountID int64) (model.Merchant, error)
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type MerchantRepoImpl struct {
+	filePath string
+}
+
+// Delete implements MerchantRepo
+func (r *MerchantRepoImpl) Delete(id int64) (model.Merchant, error) {
+	merchants, err := r.FindAll()
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	var deletedMerchant model.Merchant
+	for i, merchant := range merchants {
+		if merchant.ID == id {
+			deletedMerchant = merchant
+			merchants = append(merchants[:i], merchants[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeMerchantsToFile(merchants)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	return deletedMerchant, nil
+}
+
+// FindAll implements MerchantRepo
+func (r *MerchantRepoImpl) FindAll() ([]model.Merchant, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.Merchant{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var merchants []model.Merchant
+	err = json.NewDecoder(file).Decode(&merchants)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return merchants, nil
+}
+
+// FindById implements MerchantRepo
+func (r *MerchantRepoImpl) FindById(id int64) (model.Merchant, error) {
+	merchants, err := r.FindAll()
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	for _, merchant := range merchants {
+		if merchant.ID == id {
+			return merchant, nil
+		}
+	}
+
+	return model.Merchant{}, fmt.Errorf("merchant by id: %d not found", id)
+}
+
+// FindByUserId implements MerchantRepo
+func (r *MerchantRepoImpl) FindByUserId(userID int64) ([]model.Merchant, error) {
+	merchants, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var userMerchants []model.Merchant
+	for _, merchant := range merchants {
+		if merchant.UserID == userID {
+			userMerchants = append(userMerchants, merchant)
+		}
+	}
+
+	return userMerchants, nil
+}
+
+// FindByAccountId implements MerchantRepo
+func (r *MerchantRepoImpl) FindByAccountId(accountID int64) (model.Merchant, error) {
+	merchants, err := r.FindAll()
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	for _, merchant := range merchants {
+		if merchant.AccountID == accountID {
+			return merchant, nil
+		}
+	}
+
+	return model.Merchant{}, fmt.Errorf("merchant by account id: %d not found", accountID)
+}
+
+// Save implements MerchantRepo
+func (r *MerchantRepoImpl) Save(newMerchant model.Merchant) (model.Merchant, error) {
+	merchants, err := r.FindAll()
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	newMerchant.ID = generateUniqueIDMerchant(merchants)
+	newMerchant.CreatedAt = time.Now()
+
+	merchants = append(merchants, newMerchant)
+
+	err = r.writeMerchantsToFile(merchants)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	return newMerchant, nil
+}
+
+// Update implements MerchantRepo
+func (r *MerchantRepoImpl) Update(updatedMerchant model.Merchant) (model.Merchant, error) {
+	merchants, err := r.FindAll()
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	var found bool
+	for i, merchant := range merchants {
+		if merchant.ID == updatedMerchant.ID {
+			merchants[i] = updatedMerchant
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.Merchant{}, fmt.Errorf("merchant by id: %d not found", updatedMerchant.ID)
+	}
+
+	err = r.writeMerchantsToFile(merchants)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	return updatedMerchant, nil
+}
+
+func (r *MerchantRepoImpl) writeMerchantsToFile(merchants []model.Merchant) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(merchants)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDMerchant(merchants []model.Merchant) int64 {
+	var maxID int64
+	for _, merchant := range merchants {
+		if merchant.ID > maxID {
+			maxID = merchant.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewMerchantRepoImpl(filePath string) MerchantRepo {
+	return &MerchantRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type OutboxEventRepo interface {
+	Save(newEvent model.OutboxEvent) (model.OutboxEvent, error)
+	Update(updatedEvent model.OutboxEvent) (model.OutboxEvent, error)
+	Delete(id int64) (model.OutboxEvent, error)
+	FindById(id int64) (model.OutboxEvent, error)
+	FindAll() ([]model.OutboxEvent, error)
+	FindByStatus(status string) ([]model.OutboxEvent, error)
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type OutboxEventRepoImpl struct {
+	filePath string
+}
+
+// Delete implements OutboxEventRepo
+func (r *OutboxEventRepoImpl) Delete(id int64) (model.OutboxEvent, error) {
+	events, err := r.FindAll()
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	var deletedEvent model.OutboxEvent
+	for i, event := range events {
+		if event.ID == id {
+			deletedEvent = event
+			events = append(events[:i], events[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeEventsToFile(events)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	return deletedEvent, nil
+}
+
+// FindAll implements OutboxEventRepo
+func (r *OutboxEventRepoImpl) FindAll() ([]model.OutboxEvent, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.OutboxEvent{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []model.OutboxEvent
+	err = json.NewDecoder(file).Decode(&events)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// FindById implements OutboxEventRepo
+func (r *OutboxEventRepoImpl) FindById(id int64) (model.OutboxEvent, error) {
+	events, err := r.FindAll()
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+
+	return model.OutboxEvent{}, fmt.Errorf("outbox event by id: %d not found", id)
+}
+
+// FindByStatus implements OutboxEventRepo
+func (r *OutboxEventRepoImpl) FindByStatus(status string) ([]model.OutboxEvent, error) {
+	events, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var statusEvents []model.OutboxEvent
+	for _, event := range events {
+		if event.Status == status {
+			statusEvents = append(statusEvents, event)
+		}
+	}
+
+	return statusEvents, nil
+}
+
+// Save implements OutboxEventRepo
+func (r *OutboxEventRepoImpl) Save(newEvent model.OutboxEvent) (model.OutboxEvent, error) {
+	events, err := r.FindAll()
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	newEvent.ID = generateUniqueIDOutboxEvent(events)
+	newEvent.CreatedAt = time.Now()
+
+	events = append(events, newEvent)
+
+	err = r.writeEventsToFile(events)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	return newEvent, nil
+}
+
+// Update implements OutboxEventRepo
+func (r *OutboxEventRepoImpl) Update(updatedEvent model.OutboxEvent) (model.OutboxEvent, error) {
+	events, err := r.FindAll()
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	var found bool
+	for i, event := range events {
+		if event.ID == updatedEvent.ID {
+			events[i] = updatedEvent
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.OutboxEvent{}, fmt.Errorf("outbox event by id: %d not found", updatedEvent.ID)
+	}
+
+	err = r.writeEventsToFile(events)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	return updatedEvent, nil
+}
+
+func (r *OutboxEventRepoImpl) writeEventsToFile(events []model.OutboxEvent) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(events)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDOutboxEvent(events []model.OutboxEvent) int64 {
+	var maxID int64
+	for _, event := range events {
+		if event.ID > maxID {
+			maxID = event.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewOutboxEventRepoImpl(filePath string) OutboxEventRepo {
+	return &OutboxEventRepoImpl{
+		filePath: filePath,
+	}
+}
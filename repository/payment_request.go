@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type PaymentRequestRepo interface {
+	Save(newRequest model.PaymentRequest) (model.PaymentRequest, error)
+	Update(updatedRequest model.PaymentRequest) (model.PaymentRequest, error)
+	Delete(id int64) (model.PaymentRequest, error)
+	FindById(id int64) (model.PaymentRequest, error)
+	FindAll() ([]model.PaymentRequest, error)
+	FindByPayerId(payerID int64) ([]model.PaymentRequest, error)
+	FindByRequesterId(requesterID int64) ([]model.PaymentRequest, error)
+}
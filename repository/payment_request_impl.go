@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type PaymentRequestRepoImpl struct {
+	filePath string
+}
+
+// Delete implements PaymentRequestRepo
+func (r *PaymentRequestRepoImpl) Delete(id int64) (model.PaymentRequest, error) {
+	requests, err := r.FindAll()
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	var deletedRequest model.PaymentRequest
+	for i, request := range requests {
+		if request.ID == id {
+			deletedRequest = request
+			requests = append(requests[:i], requests[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeRequestsToFile(requests)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	return deletedRequest, nil
+}
+
+// FindAll implements PaymentRequestRepo
+func (r *PaymentRequestRepoImpl) FindAll() ([]model.PaymentRequest, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.PaymentRequest{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var requests []model.PaymentRequest
+	err = json.NewDecoder(file).Decode(&requests)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// FindById implements PaymentRequestRepo
+func (r *PaymentRequestRepoImpl) FindById(id int64) (model.PaymentRequest, error) {
+	requests, err := r.FindAll()
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	for _, request := range requests {
+		if request.ID == id {
+			return request, nil
+		}
+	}
+
+	return model.PaymentRequest{}, fmt.Errorf("payment request by id: %d not found", id)
+}
+
+// FindByPayerId implements PaymentRequestRepo
+func (r *PaymentRequestRepoImpl) FindByPayerId(payerID int64) ([]model.PaymentRequest, error) {
+	requests, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var payerRequests []model.PaymentRequest
+	for _, request := range requests {
+		if request.PayerID == payerID {
+			payerRequests = append(payerRequests, request)
+		}
+	}
+
+	return payerRequests, nil
+}
+
+// FindByRequesterId implements PaymentRequestRepo
+func (r *PaymentRequestRepoImpl) FindByRequesterId(requesterID int64) ([]model.PaymentRequest, error) {
+	requests, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var requesterRequests []model.PaymentRequest
+	for _, request := range requests {
+		if request.RequesterID == requesterID {
+			requesterRequests = append(requesterRequests, request)
+		}
+	}
+
+	return requesterRequests, nil
+}
+
+// Save implements PaymentRequestRepo
+func (r *PaymentRequestRepoImpl) Save(newRequest model.PaymentRequest) (model.PaymentRequest, error) {
+	requests, err := r.FindAll()
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	newRequest.ID = generateUniqueIDPaymentRequest(requests)
+	newRequest.CreatedAt = time.Now()
+
+	requests = append(requests, newRequest)
+
+	err = r.writeRequestsToFile(requests)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	return newRequest, nil
+}
+
+// Update implements PaymentRequestRepo
+func (r *PaymentRequestRepoImpl) Update(updatedRequest model.PaymentRequest) (model.PaymentRequest, error) {
+	requests, err := r.FindAll()
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	var found bool
+	for i, request := range requests {
+		if request.ID == updatedRequest.ID {
+			requests[i] = updatedRequest
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.PaymentRequest{}, fmt.Errorf("payment request by id: %d not found", updatedRequest.ID)
+	}
+
+	err = r.writeRequestsToFile(requests)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	return updatedRequest, nil
+}
+
+func (r *PaymentRequestRepoImpl) writeRequestsToFile(requests []model.PaymentRequest) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(requests)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDPaymentRequest(requests []model.PaymentRequest) int64 {
+	var maxID int64
+	for _, request := range requests {
+		if request.ID > maxID {
+			maxID = request.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewPaymentRequestRepoImpl(filePath string) PaymentRequestRepo {
+	return &PaymentRequestRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type ScheduledTransferRepo interface {
+	Save(newScheduledTransfer model.ScheduledTransfer) (model.ScheduledTransfer, error)
+	Update(updatedScheduledTransfer model.ScheduledTransfer) (model.ScheduledTransfer, error)
+	Delete(id int64) (model.ScheduledTransfer, error)
+	FindById(id int64) (model.ScheduledTransfer, error)
+	FindByUserId(userID int64) ([]model.ScheduledTransfer, error)
+	FindDue(now time.Time) ([]model.ScheduledTransfer, error)
+	FindAll() ([]model.ScheduledTransfer, error)
+}
+
+type ScheduledTransferExecutionRepo interface {
+	Save(newExecution model.ScheduledTransferExecution) (model.ScheduledTransferExecution, error)
+	FindByScheduledTransferId(scheduledTransferID int64) ([]model.ScheduledTransferExecution, error)
+	FindAll() ([]model.ScheduledTransferExecution, error)
+}
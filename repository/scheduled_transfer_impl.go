@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type ScheduledTransferRepoImpl struct {
+	filePath string
+}
+
+// Delete implements ScheduledTransferRepo
+func (r *ScheduledTransferRepoImpl) Delete(id int64) (model.ScheduledTransfer, error) {
+	schedules, err := r.FindAll()
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	var deletedSchedule model.ScheduledTransfer
+	for i, schedule := range schedules {
+		if schedule.ID == id {
+			deletedSchedule = schedule
+			schedules = append(schedules[:i], schedules[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeSchedulesToFile(schedules)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	return deletedSchedule, nil
+}
+
+// FindAll implements ScheduledTransferRepo
+func (r *ScheduledTransferRepoImpl) FindAll() ([]model.ScheduledTransfer, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.ScheduledTransfer{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var schedules []model.ScheduledTransfer
+	err = json.NewDecoder(file).Decode(&schedules)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// FindById implements ScheduledTransferRepo
+func (r *ScheduledTransferRepoImpl) FindById(id int64) (model.ScheduledTransfer, error) {
+	schedules, err := r.FindAll()
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	for _, schedule := range schedules {
+		if schedule.ID == id {
+			return schedule, nil
+		}
+	}
+
+	return model.ScheduledTransfer{}, fmt.Errorf("scheduled transfer by id: %d not found", id)
+}
+
+// FindByUserId implements ScheduledTransferRepo
+func (r *ScheduledTransferRepoImpl) FindByUserId(userID int64) ([]model.ScheduledTransfer, error) {
+	schedules, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var userSchedules []model.ScheduledTransfer
+	for _, schedule := range schedules {
+		if schedule.UserID == userID {
+			userSchedules = append(userSchedules, schedule)
+		}
+	}
+
+	return userSchedules, nil
+}
+
+// FindDue implements ScheduledTransferRepo
+func (r *ScheduledTransferRepoImpl) FindDue(now time.Time) ([]model.ScheduledTransfer, error) {
+	schedules, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var dueSchedules []model.ScheduledTransfer
+	for _, schedule := range schedules {
+		if schedule.Status == model.ScheduleStatusActive && !schedule.DueAt().After(now) {
+			dueSchedules = append(dueSchedules, schedule)
+		}
+	}
+
+	return dueSchedules, nil
+}
+
+// Save implements ScheduledTransferRepo
+func (r *ScheduledTransferRepoImpl) Save(newSchedule model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	schedules, err := r.FindAll()
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	newSchedule.ID = generateUniqueIDScheduledTransfer(schedules)
+	newSchedule.CreatedAt = time.Now()
+
+	schedules = append(schedules, newSchedule)
+
+	err = r.writeSchedulesToFile(schedules)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	return newSchedule, nil
+}
+
+// Update implements ScheduledTransferRepo
+func (r *ScheduledTransferRepoImpl) Update(updatedSchedule model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	schedules, err := r.FindAll()
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	var found bool
+	for i, schedule := range schedules {
+		if schedule.ID == updatedSchedule.ID {
+			schedules[i] = updatedSchedule
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.ScheduledTransfer{}, fmt.Errorf("scheduled transfer by id: %d not found", updatedSchedule.ID)
+	}
+
+	err = r.writeSchedulesToFile(schedules)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	return updatedSchedule, nil
+}
+
+func (r *ScheduledTransferRepoImpl) writeSchedulesToFile(schedules []model.ScheduledTransfer) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(schedules)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDScheduledTransfer(schedules []model.ScheduledTransfer) int64 {
+	var maxID int64
+	for _, schedule := range schedules {
+		if schedule.ID > maxID {
+			maxID = schedule.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewScheduledTransferRepoImpl(filePath string) ScheduledTransferRepo {
+	return &ScheduledTransferRepoImpl{
+		filePath: filePath,
+	}
+}
+
+type ScheduledTransferExecutionRepoImpl struct {
+	filePath string
+}
+
+// FindAll implements ScheduledTransferExecutionRepo
+func (r *ScheduledTransferExecutionRepoImpl) FindAll() ([]model.ScheduledTransferExecution, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.ScheduledTransferExecution{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var executions []model.ScheduledTransferExecution
+	err = json.NewDecoder(file).Decode(&executions)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return executions, nil
+}
+
+// FindByScheduledTransferId implements ScheduledTransferExecutionRepo
+func (r *ScheduledTransferExecutionRepoImpl) FindByScheduledTransferId(scheduledTransferID int64) ([]model.ScheduledTransferExecution, error) {
+	executions, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var scheduleExecutions []model.ScheduledTransferExecution
+	for _, execution := range executions {
+		if execution.ScheduledTransferID == scheduledTransferID {
+			scheduleExecutions = append(scheduleExecutions, execution)
+		}
+	}
+
+	return scheduleExecutions, nil
+}
+
+// Save implements ScheduledTransferExecutionRepo
+func (r *ScheduledTransferExecutionRepoImpl) Save(newExecution model.ScheduledTransferExecution) (model.ScheduledTransferExecution, error) {
+	executions, err := r.FindAll()
+	if err != nil {
+		return model.ScheduledTransferExecution{}, err
+	}
+
+	var maxID int64
+	for _, execution := range executions {
+		if execution.ID > maxID {
+			maxID = execution.ID
+		}
+	}
+	newExecution.ID = maxID + 1
+	newExecution.CreatedAt = time.Now()
+
+	executions = append(executions, newExecution)
+
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return model.ScheduledTransferExecution{}, err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(executions)
+	if err != nil {
+		return model.ScheduledTransferExecution{}, err
+	}
+
+	return newExecution, nil
+}
+
+func NewScheduledTransferExecutionRepoImpl(filePath string) ScheduledTransferExecutionRepo {
+	return &ScheduledTransferExecutionRepoImpl{
+		filePath: filePath,
+	}
+}
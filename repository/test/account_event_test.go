@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathAccountEvent    = "account_event.jsonl"
+	testFilePathAccountSnapshot = "account_snapshot.json"
+)
+
+func TestAppendAccountEvent(t *testing.T) {
+	repo := repository.NewAccountEventRepoImpl(testFilePathAccountEvent)
+	defer os.Remove(testFilePathAccountEvent)
+
+	first, err := repo.Append([]model.AccountEvent{
+		{AccountID: 1, Type: model.AccountEventOpened},
+		{AccountID: 1, Type: model.AccountEventDeposited, Amount: 1000},
+		{AccountID: 2, Type: model.AccountEventOpened},
+	})
+	if err != nil {
+		t.Fatalf("failed to append events: %v", err)
+	}
+	second, err := repo.Append([]model.AccountEvent{
+		{AccountID: 1, Type: model.AccountEventTransferredOut, Amount: -100, TransferID: 1},
+		{AccountID: 2, Type: model.AccountEventTransferredIn, Amount: 100, TransferID: 1},
+	})
+	if err != nil {
+		t.Fatalf("failed to append events: %v", err)
+	}
+
+	// Versions count per account, IDs over the whole store
+	if first[2].Version != 1 || second[0].Version != 3 || second[1].Version != 2 || second[1].ID != 5 {
+		t.Errorf("incorrect versions: %+v %+v", first, second)
+	}
+
+	events, err := repo.FindByAccountId(1, 1)
+	if err != nil {
+		t.Fatalf("failed to retrieve events: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != model.AccountEventDeposited || events[1].Amount != -100 {
+		t.Errorf("incorrect events after version 1: %+v", events)
+	}
+}
+
+func TestFindLatestAccountSnapshot(t *testing.T) {
+	repo := repository.NewAccountSnapshotRepoImpl(testFilePathAccountSnapshot)
+	defer os.Remove(testFilePathAccountSnapshot)
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []model.AccountSnapshot{
+		{AccountID: 1, Version: 100, State: model.Account{ID: 1, Balance: 100}, AsOf: day},
+		{AccountID: 1, Version: 200, State: model.Account{ID: 1, Balance: 200}, AsOf: day.AddDate(0, 0, 1)},
+		{AccountID: 2, Version: 300, State: model.Account{ID: 2, Balance: 300}, AsOf: day.AddDate(0, 0, 2)},
+	}
+	for _, snapshot := range snapshots {
+		if _, err := repo.Save(snapshot); err != nil {
+			t.Fatalf("failed to save snapshot: %v", err)
+		}
+	}
+
+	latest, err := repo.FindLatest(1, time.Time{})
+	if err != nil || latest.Version != 200 {
+		t.Errorf("expected version 200, got %d (%v)", latest.Version, err)
+	}
+
+	asOf, err := repo.FindLatest(1, day.Add(12*time.Hour))
+	if err != nil || asOf.Version != 100 || asOf.State.Balance != 100 {
+		t.Errorf("expected version 100 as of the first day, got %+v (%v)", asOf, err)
+	}
+
+	if _, err := repo.FindLatest(1, day.Add(-time.Hour)); err == nil {
+		t.Error("expected no snapshot before the first one")
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathAccountProduct = "account_product.json"
+
+func TestFindByCodeAccountProduct(t *testing.T) {
+	repo := repository.NewAccountProductRepoImpl(testFilePathAccountProduct)
+	defer os.Remove(testFilePathAccountProduct)
+
+	products := []model.AccountProduct{
+		{Code: model.AccountTypeSavings, Name: "Tabungan", MinimumBalance: 50000, Active: true},
+		{Code: model.AccountTypeTimeDeposit, Name: "Deposito", MinimumBalance: 10000000, LockInDays: 90, Active: true},
+	}
+	for _, product := range products {
+		if _, err := repo.Save(product); err != nil {
+			t.Fatalf("failed to save account product: %v", err)
+		}
+	}
+
+	// Retrieve the product by code
+	product, err := repo.FindByCode(model.AccountTypeTimeDeposit)
+	if err != nil {
+		t.Fatalf("failed to retrieve account product: %v", err)
+	}
+	if product.ID != 2 || product.LockInDays != 90 {
+		t.Errorf("incorrect account product: got %+v", product)
+	}
+
+	// Unknown code returns an error
+	if _, err := repo.FindByCode("gold"); err == nil {
+		t.Error("expected error for unknown account product code")
+	}
+}
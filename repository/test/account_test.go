@@ -224,3 +224,30 @@ func TestDeleteAccount(t *testing.T) {
 		t.Error("deleted user still exists")
 	}
 }
+
+func TestFindByNumberAccount(t *testing.T) {
+	repo := repository.NewAccountRepoImpl(testFilePathAccount)
+	defer os.Remove(testFilePathAccount)
+
+	_, err := repo.Save(model.Account{UserID: 1, Number: "4850010000123433", Balance: 100})
+	if err != nil {
+		t.Fatalf("failed to save account: %v", err)
+	}
+
+	// Retrieve the account by number
+	account, err := repo.FindByNumber("4850010000123433")
+	if err != nil {
+		t.Fatalf("failed to retrieve account by number: %v", err)
+	}
+	if account.UserID != 1 {
+		t.Errorf("incorrect account: got %+v", account)
+	}
+
+	// Account numbers are unique
+	if _, err := repo.Save(model.Account{UserID: 2, Number: "4850010000123433"}); err == nil {
+		t.Error("expected error for duplicate account number")
+	}
+	if _, err := repo.FindByNumber("4850010000999999"); err == nil {
+		t.Error("expected error for unknown account number")
+	}
+}
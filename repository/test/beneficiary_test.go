@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathBeneficiary = "beneficiary.json"
+
+func TestFindByUserIdBeneficiary(t *testing.T) {
+	repo := repository.NewBeneficiaryRepoImpl(testFilePathBeneficiary)
+	defer os.Remove(testFilePathBeneficiary)
+
+	beneficiaries := []model.Beneficiary{
+		{UserID: 1, Nickname: "Mom", AccountID: 2},
+		{UserID: 1, Nickname: "Landlord", AccountID: 3},
+		{UserID: 2, Nickname: "Mom", AccountID: 1},
+	}
+	for _, beneficiary := range beneficiaries {
+		if _, err := repo.Save(beneficiary); err != nil {
+			t.Fatalf("failed to save beneficiary: %v", err)
+		}
+	}
+
+	// Retrieve the beneficiaries of one user
+	userBeneficiaries, err := repo.FindByUserId(1)
+	if err != nil {
+		t.Fatalf("failed to retrieve beneficiaries: %v", err)
+	}
+	if len(userBeneficiaries) != 2 {
+		t.Errorf("incorrect number of beneficiaries: got %d, want %d", len(userBeneficiaries), 2)
+	}
+	for _, beneficiary := range userBeneficiaries {
+		if beneficiary.UserID != 1 {
+			t.Errorf("incorrect beneficiary user: got %d, want %d", beneficiary.UserID, 1)
+		}
+	}
+}
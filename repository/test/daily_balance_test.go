@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathDailyBalance = "daily_balance.json"
+
+func TestFindByDateDailyBalance(t *testing.T) {
+	repo := repository.NewDailyBalanceRepoImpl(testFilePathDailyBalance)
+	defer os.Remove(testFilePathDailyBalance)
+
+	if _, err := repo.SaveAll([]model.DailyBalance{
+		{AccountID: 1, Date: "2024-03-01", Balance: 1000, Currency: "IDR"},
+		{AccountID: 2, Date: "2024-03-01", Balance: 2500.5, Currency: "IDR"},
+	}); err != nil {
+		t.Fatalf("failed to save daily balances: %v", err)
+	}
+	saved, err := repo.SaveAll([]model.DailyBalance{
+		{AccountID: 1, Date: "2024-03-02", Balance: 750, Currency: "IDR"},
+	})
+	if err != nil {
+		t.Fatalf("failed to save daily balances: %v", err)
+	}
+	if saved[0].ID != 3 || saved[0].CreatedAt.IsZero() {
+		t.Errorf("incorrect saved daily balance: got %+v", saved[0])
+	}
+
+	// Retrieve the closing balances of the first day
+	balances, err := repo.FindByDate("2024-03-01")
+	if err != nil {
+		t.Fatalf("failed to retrieve daily balances: %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("incorrect number of daily balances: got %d, want %d", len(balances), 2)
+	}
+	if balances[1].AccountID != 2 || balances[1].Balance != 2500.5 {
+		t.Errorf("incorrect daily balance: got %+v", balances[1])
+	}
+}
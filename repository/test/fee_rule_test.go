@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathFeeRule = "fee_rule.json"
+)
+
+func TestSaveFeeRule(t *testing.T) {
+	repo := repository.NewFeeRuleRepoImpl(testFilePathFeeRule)
+	defer os.Remove(testFilePathFeeRule)
+
+	// Save a tiered rule
+	newRule := model.FeeRule{
+		Name:             "Tiered savings",
+		AccountType:      model.AccountTypeSavings,
+		Type:             model.FeeTypeTiered,
+		Tiers:            []model.FeeTier{{UpTo: 1000000, Fee: 2500}, {Fee: 6500}},
+		FreeForSameOwner: true,
+	}
+	savedRule, err := repo.Save(newRule)
+	if err != nil {
+		t.Fatalf("failed to save fee rule: %v", err)
+	}
+	if savedRule.ID == 0 {
+		t.Error("saved fee rule ID should not be zero")
+	}
+
+	// Retrieve fee rule by ID
+	retrievedRule, err := repo.FindById(savedRule.ID)
+	if err != nil {
+		t.Fatalf("failed to retrieve fee rule: %v", err)
+	}
+	if len(retrievedRule.Tiers) != 2 || retrievedRule.Tiers[1].Fee != 6500 {
+		t.Errorf("retrieved fee rule tiers do not match: got %+v, want %+v", retrievedRule.Tiers, newRule.Tiers)
+	}
+	if !retrievedRule.FreeForSameOwner {
+		t.Error("retrieved fee rule should be free for same owner")
+	}
+}
@@ -195,3 +195,51 @@ func TestUpdateHistory(t *testing.T) {
 		t.Errorf("retrieved History created at does not match: got %s, want %s", retrievedHistory.CreatedAt, updatedHistory.CreatedAt)
 	}
 }
+
+func TestFindByAccountIdHistory(t *testing.T) {
+	repo, cleanup := setupHistory(t)
+	defer cleanup()
+
+	// Save a history for another account
+	_, err := repo.Save(model.History{AccountID: testHistory.AccountID + 1, Amount: 50})
+	if err != nil {
+		t.Fatalf("failed to save history: %v", err)
+	}
+
+	// Retrieve history by account ID
+	historys, err := repo.FindByAccountId(testHistory.AccountID)
+	if err != nil {
+		t.Fatalf("failed to retrieve history by account ID: %v", err)
+	}
+	if len(historys) != 1 || historys[0].ID != testHistory.ID {
+		t.Errorf("incorrect history for account %d: got %+v", testHistory.AccountID, historys)
+	}
+}
+
+func TestFindByTransferIdHistory(t *testing.T) {
+	repo, cleanup := setupHistory(t)
+	defer cleanup()
+
+	// Save both sides of a transfer
+	historys := []model.History{
+		{AccountID: 1, Type: model.HistoryTypeTransferOut, TransferID: 7, CounterpartyAccountID: 2, Amount: -50, BalanceAfter: 50},
+		{AccountID: 2, Type: model.HistoryTypeTransferIn, TransferID: 7, CounterpartyAccountID: 1, Amount: 50, BalanceAfter: 150},
+	}
+	for _, history := range historys {
+		if _, err := repo.Save(history); err != nil {
+			t.Fatalf("failed to save history: %v", err)
+		}
+	}
+
+	// Retrieve history by transfer ID
+	transferHistorys, err := repo.FindByTransferId(7)
+	if err != nil {
+		t.Fatalf("failed to retrieve history by transfer ID: %v", err)
+	}
+	if len(transferHistorys) != 2 {
+		t.Fatalf("incorrect number of history rows: got %d, want %d", len(transferHistorys), 2)
+	}
+	if transferHistorys[0].BalanceAfter != 50 || transferHistorys[1].CounterpartyAccountID != 1 {
+		t.Errorf("history fields were not stored: got %+v", transferHistorys)
+	}
+}
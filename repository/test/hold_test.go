@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathHold = "hold.json"
+)
+
+func TestFindExpiredHold(t *testing.T) {
+	repo := repository.NewHoldRepoImpl(testFilePathHold)
+	defer os.Remove(testFilePathHold)
+
+	// Save an expired, a live and an already released hold
+	holds := []model.Hold{
+		{AccountID: 1, Amount: 100, Status: model.HoldStatusActive, ExpiresAt: time.Now().Add(-time.Minute)},
+		{AccountID: 1, Amount: 200, Status: model.HoldStatusActive, ExpiresAt: time.Now().Add(time.Hour)},
+		{AccountID: 2, Amount: 300, Status: model.HoldStatusReleased, ExpiresAt: time.Now().Add(-time.Minute)},
+	}
+	for _, hold := range holds {
+		if _, err := repo.Save(hold); err != nil {
+			t.Fatalf("failed to save hold: %v", err)
+		}
+	}
+
+	// Only the active hold past its expiry is returned
+	expired, err := repo.FindExpired(time.Now())
+	if err != nil {
+		t.Fatalf("failed to retrieve expired holds: %v", err)
+	}
+	if len(expired) != 1 || expired[0].Amount != 100 {
+		t.Errorf("incorrect expired holds: got %+v", expired)
+	}
+
+	// Retrieve holds by account ID
+	accountHolds, err := repo.FindByAccountId(1)
+	if err != nil {
+		t.Fatalf("failed to retrieve holds by account ID: %v", err)
+	}
+	if len(accountHolds) != 2 {
+		t.Errorf("incorrect number of holds: got %d, want %d", len(accountHolds), 2)
+	}
+}
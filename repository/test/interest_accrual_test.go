@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathInterestAccrual = "interest_accrual.json"
+
+func TestFindUnpostedInterestAccrual(t *testing.T) {
+	repo := repository.NewInterestAccrualRepoImpl(testFilePathInterestAccrual)
+	defer os.Remove(testFilePathInterestAccrual)
+
+	accruals := []model.InterestAccrual{
+		{AccountID: 1, Date: "2023-06-01", Amount: 10, PostedAt: time.Now()},
+		{AccountID: 1, Date: "2023-07-01", Amount: 11},
+		{AccountID: 2, Date: "2023-07-01", Amount: 12},
+	}
+	for _, accrual := range accruals {
+		if _, err := repo.Save(accrual); err != nil {
+			t.Fatalf("failed to save interest accrual: %v", err)
+		}
+	}
+
+	// Only accruals without PostedAt are returned
+	unposted, err := repo.FindUnposted()
+	if err != nil {
+		t.Fatalf("failed to retrieve unposted interest accruals: %v", err)
+	}
+	if len(unposted) != 2 {
+		t.Errorf("incorrect number of unposted interest accruals: got %d, want %d", len(unposted), 2)
+	}
+
+	// Retrieve the accruals of one account
+	accountAccruals, err := repo.FindByAccountId(1)
+	if err != nil {
+		t.Fatalf("failed to retrieve interest accruals: %v", err)
+	}
+	if len(accountAccruals) != 2 {
+		t.Errorf("incorrect number of interest accruals: got %d, want %d", len(accountAccruals), 2)
+	}
+}
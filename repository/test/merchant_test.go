@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathMerchant = "merchant.json"
+
+func TestFindByAccountIdMerchant(t *testing.T) {
+	repo := repository.NewMerchantRepoImpl(testFilePathMerchant)
+	defer os.Remove(testFilePathMerchant)
+
+	merchants := []model.Merchant{
+		{UserID: 1, AccountID: 2, Name: "Warung Sedap", City: "Jakarta"},
+		{UserID: 1, AccountID: 3, Name: "Toko Jaya", City: "Bandung"},
+	}
+	for _, merchant := range merchants {
+		if _, err := repo.Save(merchant); err != nil {
+			t.Fatalf("failed to save merchant: %v", err)
+		}
+	}
+
+	// Retrieve the merchant of an account
+	merchant, err := repo.FindByAccountId(3)
+	if err != nil {
+		t.Fatalf("failed to retrieve merchant: %v", err)
+	}
+	if merchant.Name != "Toko Jaya" {
+		t.Errorf("incorrect merchant: got %s, want %s", merchant.Name, "Toko Jaya")
+	}
+
+	if _, err := repo.FindByAccountId(4); err == nil {
+		t.Errorf("expected an error for an account without a merchant")
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathOutbox = "outbox_event.json"
+
+func TestFindByStatusOutboxEvent(t *testing.T) {
+	repo := repository.NewOutboxEventRepoImpl(testFilePathOutbox)
+	defer os.Remove(testFilePathOutbox)
+
+	events := []model.OutboxEvent{
+		{EventID: "evt_1", Type: model.DomainEventUserRegistered, AggregateType: model.AggregateUser, AggregateID: 1, Payload: []byte(`{"id_user":1}`), Status: model.OutboxStatusPublished},
+		{EventID: "evt_2", Type: model.DomainEventAccountOpened, AggregateType: model.AggregateAccount, AggregateID: 1, Payload: []byte(`{"id_account":1}`), Status: model.OutboxStatusPending},
+		{EventID: "evt_3", Type: model.DomainEventTransferCreated, AggregateType: model.AggregateTransfer, AggregateID: 1, Payload: []byte(`{"id_transfer":1}`), Status: model.OutboxStatusPending},
+	}
+	for _, event := range events {
+		if _, err := repo.Save(event); err != nil {
+			t.Fatalf("failed to save outbox event: %v", err)
+		}
+	}
+
+	// Retrieve the events that still have to be published
+	pending, err := repo.FindByStatus(model.OutboxStatusPending)
+	if err != nil {
+		t.Fatalf("failed to retrieve outbox events: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("incorrect number of outbox events: got %d, want %d", len(pending), 2)
+	}
+	if pending[0].EventID != "evt_2" || string(pending[1].Payload) != `{"id_transfer":1}` {
+		t.Errorf("incorrect outbox events: got %+v", pending)
+	}
+}
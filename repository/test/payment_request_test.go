@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathPaymentRequest = "payment_request.json"
+
+func TestFindByPayerIdPaymentRequest(t *testing.T) {
+	repo := repository.NewPaymentRequestRepoImpl(testFilePathPaymentRequest)
+	defer os.Remove(testFilePathPaymentRequest)
+
+	requests := []model.PaymentRequest{
+		{RequesterID: 1, PayerID: 2, ToAccountID: 1, Amount: 100, Status: model.PaymentRequestStatusPending},
+		{RequesterID: 1, PayerID: 3, ToAccountID: 1, Amount: 100, Status: model.PaymentRequestStatusPending},
+		{RequesterID: 3, PayerID: 2, ToAccountID: 3, Amount: 50, Status: model.PaymentRequestStatusPending},
+	}
+	for _, request := range requests {
+		if _, err := repo.Save(request); err != nil {
+			t.Fatalf("failed to save payment request: %v", err)
+		}
+	}
+
+	// Incoming requests of a payer
+	incoming, err := repo.FindByPayerId(2)
+	if err != nil {
+		t.Fatalf("failed to retrieve payment requests: %v", err)
+	}
+	if len(incoming) != 2 {
+		t.Errorf("incorrect number of incoming payment requests: got %d, want %d", len(incoming), 2)
+	}
+
+	// Outgoing requests of a requester
+	outgoing, err := repo.FindByRequesterId(1)
+	if err != nil {
+		t.Fatalf("failed to retrieve payment requests: %v", err)
+	}
+	if len(outgoing) != 2 {
+		t.Errorf("incorrect number of outgoing payment requests: got %d, want %d", len(outgoing), 2)
+	}
+}
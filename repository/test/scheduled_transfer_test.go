@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathScheduledTransfer          = "scheduled_transfer.json"
+	testFilePathScheduledTransferExecution = "scheduled_transfer_execution.json"
+)
+
+var (
+	testScheduledTransfer = model.ScheduledTransfer{
+		ID:            1,
+		UserID:        1,
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        1000.0,
+		Frequency:     model.FrequencyDaily,
+		StartAt:       time.Now().Add(-time.Hour),
+		NextRunAt:     time.Now().Add(-time.Hour),
+		Status:        model.ScheduleStatusActive,
+		CreatedAt:     time.Now(),
+	}
+)
+
+func setupScheduledTransfer(t *testing.T) (repository.ScheduledTransferRepo, func()) {
+	// Create a temporary test data file
+	file, err := os.Create(testFilePathScheduledTransfer)
+	if err != nil {
+		t.Fatalf("failed to create test data file: %v", err)
+	}
+
+	// Write test scheduled transfer to the file
+	schedules := []model.ScheduledTransfer{testScheduledTransfer}
+	err = json.NewEncoder(file).Encode(schedules)
+	if err != nil {
+		t.Fatalf("failed to write test scheduled transfer to file: %v", err)
+	}
+
+	// Close the file
+	err = file.Close()
+	if err != nil {
+		t.Fatalf("failed to close test data file: %v", err)
+	}
+
+	// Create the repository with the test data file
+	repo := repository.NewScheduledTransferRepoImpl(testFilePathScheduledTransfer)
+
+	// Return the repository and a cleanup function
+	return repo, func() {
+		// Remove the test data file
+		err := os.Remove(testFilePathScheduledTransfer)
+		if err != nil {
+			t.Fatalf("failed to remove test data file: %v", err)
+		}
+	}
+}
+
+func TestSaveScheduledTransfer(t *testing.T) {
+	repo, cleanup := setupScheduledTransfer(t)
+	defer cleanup()
+
+	// Save a new scheduled transfer
+	newSchedule := model.ScheduledTransfer{
+		UserID:        1,
+		FromAccountID: 2,
+		ToAccountID:   1,
+		Amount:        250.0,
+		Frequency:     model.FrequencyMonthly,
+		StartAt:       time.Now().Add(24 * time.Hour),
+		NextRunAt:     time.Now().Add(24 * time.Hour),
+		Status:        model.ScheduleStatusActive,
+	}
+	savedSchedule, err := repo.Save(newSchedule)
+	if err != nil {
+		t.Fatalf("failed to save scheduled transfer: %v", err)
+	}
+
+	// Verify the saved scheduled transfer
+	if savedSchedule.ID != testScheduledTransfer.ID+1 {
+		t.Errorf("saved scheduled transfer ID does not match: got %d, want %d", savedSchedule.ID, testScheduledTransfer.ID+1)
+	}
+	if savedSchedule.Frequency != newSchedule.Frequency {
+		t.Errorf("saved scheduled transfer frequency does not match: got %s, want %s", savedSchedule.Frequency, newSchedule.Frequency)
+	}
+	if savedSchedule.CreatedAt.IsZero() {
+		t.Error("saved scheduled transfer created at should not be zero")
+	}
+}
+
+func TestFindDueScheduledTransfer(t *testing.T) {
+	repo, cleanup := setupScheduledTransfer(t)
+	defer cleanup()
+
+	// Add a schedule that is not due yet
+	_, err := repo.Save(model.ScheduledTransfer{
+		UserID:    1,
+		Frequency: model.FrequencyOnce,
+		NextRunAt: time.Now().Add(time.Hour),
+		Status:    model.ScheduleStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("failed to save scheduled transfer: %v", err)
+	}
+
+	// Only the test schedule should be due
+	due, err := repo.FindDue(time.Now())
+	if err != nil {
+		t.Fatalf("failed to retrieve due scheduled transfers: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != testScheduledTransfer.ID {
+		t.Fatalf("incorrect due scheduled transfers: got %+v", due)
+	}
+
+	// Cancelled schedules are never due
+	cancelled := due[0]
+	cancelled.Status = model.ScheduleStatusCancelled
+	_, err = repo.Update(cancelled)
+	if err != nil {
+		t.Fatalf("failed to update scheduled transfer: %v", err)
+	}
+
+	due, err = repo.FindDue(time.Now())
+	if err != nil {
+		t.Fatalf("failed to retrieve due scheduled transfers: %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("incorrect number of due scheduled transfers: got %d, want %d", len(due), 0)
+	}
+}
+
+func TestFindByUserIdScheduledTransfer(t *testing.T) {
+	repo, cleanup := setupScheduledTransfer(t)
+	defer cleanup()
+
+	schedules, err := repo.FindByUserId(testScheduledTransfer.UserID)
+	if err != nil {
+		t.Fatalf("failed to retrieve scheduled transfers: %v", err)
+	}
+	if len(schedules) != 1 {
+		t.Errorf("incorrect number of scheduled transfers: got %d, want %d", len(schedules), 1)
+	}
+
+	schedules, err = repo.FindByUserId(99)
+	if err != nil {
+		t.Fatalf("failed to retrieve scheduled transfers: %v", err)
+	}
+	if len(schedules) != 0 {
+		t.Errorf("incorrect number of scheduled transfers: got %d, want %d", len(schedules), 0)
+	}
+}
+
+func TestSaveScheduledTransferExecution(t *testing.T) {
+	repo := repository.NewScheduledTransferExecutionRepoImpl(testFilePathScheduledTransferExecution)
+	defer os.Remove(testFilePathScheduledTransferExecution)
+
+	for _, status := range []string{model.ExecutionStatusSuccess, model.ExecutionStatusSkipped} {
+		_, err := repo.Save(model.ScheduledTransferExecution{
+			ScheduledTransferID: testScheduledTransfer.ID,
+			Status:              status,
+			Attempts:            1,
+		})
+		if err != nil {
+			t.Fatalf("failed to save execution: %v", err)
+		}
+	}
+
+	executions, err := repo.FindByScheduledTransferId(testScheduledTransfer.ID)
+	if err != nil {
+		t.Fatalf("failed to retrieve executions: %v", err)
+	}
+	if len(executions) != 2 {
+		t.Fatalf("incorrect number of executions: got %d, want %d", len(executions), 2)
+	}
+	if executions[1].ID != 2 || executions[1].Status != model.ExecutionStatusSkipped {
+		t.Errorf("incorrect second execution: got %+v", executions[1])
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathTransferApproval = "transfer_approval.json"
+	testFilePathAuditLog         = "audit_log.json"
+)
+
+func TestFindByTransferIdTransferApproval(t *testing.T) {
+	repo := repository.NewTransferApprovalRepoImpl(testFilePathTransferApproval)
+	defer os.Remove(testFilePathTransferApproval)
+
+	approvals := []model.TransferApproval{
+		{TransferID: 1, RequestedBy: 1, Status: model.ApprovalStatusPending, ExpiresAt: time.Now().Add(time.Hour)},
+		{TransferID: 2, RequestedBy: 1, Status: model.ApprovalStatusPending, ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	for _, approval := range approvals {
+		if _, err := repo.Save(approval); err != nil {
+			t.Fatalf("failed to save transfer approval: %v", err)
+		}
+	}
+
+	// Retrieve the approval by transfer ID
+	approval, err := repo.FindByTransferId(2)
+	if err != nil {
+		t.Fatalf("failed to retrieve transfer approval: %v", err)
+	}
+	if approval.ID != 2 {
+		t.Errorf("incorrect transfer approval ID: got %d, want %d", approval.ID, 2)
+	}
+
+	// Update the approval and check the status is stored
+	approval.Status = model.ApprovalStatusApproved
+	if _, err := repo.Update(approval); err != nil {
+		t.Fatalf("failed to update transfer approval: %v", err)
+	}
+	updated, err := repo.FindById(approval.ID)
+	if err != nil {
+		t.Fatalf("failed to retrieve transfer approval: %v", err)
+	}
+	if updated.Status != model.ApprovalStatusApproved {
+		t.Errorf("incorrect status: got %s, want %s", updated.Status, model.ApprovalStatusApproved)
+	}
+
+	// Unknown transfer returns an error
+	if _, err := repo.FindByTransferId(99); err == nil {
+		t.Error("expected error for unknown transfer ID")
+	}
+}
+
+func TestFindByEntityAuditLog(t *testing.T) {
+	repo := repository.NewAuditLogRepoImpl(testFilePathAuditLog)
+	defer os.Remove(testFilePathAuditLog)
+
+	logs := []model.AuditLog{
+		{Action: "transfer.approved", ActorID: 2, Entity: "transfer_approval", EntityID: 1},
+		{Action: "transfer.rejected", ActorID: 2, Entity: "transfer_approval", EntityID: 2},
+		{Action: "user.role_changed", ActorID: 3, Entity: "user", EntityID: 1},
+	}
+	for _, log := range logs {
+		if _, err := repo.Save(log); err != nil {
+			t.Fatalf("failed to save audit log: %v", err)
+		}
+	}
+
+	// Only entries for the requested entity are returned
+	found, err := repo.FindByEntity("transfer_approval", 2)
+	if err != nil {
+		t.Fatalf("failed to retrieve audit logs: %v", err)
+	}
+	if len(found) != 1 || found[0].Action != "transfer.rejected" {
+		t.Errorf("incorrect audit logs: got %+v", found)
+	}
+
+	all, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("failed to retrieve audit logs: %v", err)
+	}
+	if len(all) != len(logs) {
+		t.Errorf("incorrect number of audit logs: got %d, want %d", len(all), len(logs))
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathTransferBatch = "transfer_batch.json"
+)
+
+func TestSaveAndUpdateTransferBatch(t *testing.T) {
+	repo := repository.NewTransferBatchRepoImpl(testFilePathTransferBatch)
+	defer os.Remove(testFilePathTransferBatch)
+
+	// Save a new batch with two items
+	newBatch := model.TransferBatch{
+		UserID: 1,
+		Mode:   model.BatchModeBestEffort,
+		Status: model.BatchStatusPending,
+		Items: []model.TransferBatchItem{
+			{Line: 1, FromAccountID: 1, ToAccountID: 2, Amount: 100, Status: model.BatchItemStatusPending},
+			{Line: 2, FromAccountID: 1, ToAccountID: 3, Amount: 200, Status: model.BatchItemStatusPending},
+		},
+	}
+	savedBatch, err := repo.Save(newBatch)
+	if err != nil {
+		t.Fatalf("failed to save batch: %v", err)
+	}
+
+	// Record the result of the first item
+	savedBatch.Items[0].Status = model.BatchItemStatusSuccess
+	savedBatch.Items[0].TransferID = 7
+	savedBatch.Status = model.BatchStatusProcessing
+	_, err = repo.Update(savedBatch)
+	if err != nil {
+		t.Fatalf("failed to update batch: %v", err)
+	}
+
+	// Retrieve batches by user ID
+	batches, err := repo.FindByUserId(1)
+	if err != nil {
+		t.Fatalf("failed to retrieve batches: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("incorrect number of batches: got %d, want %d", len(batches), 1)
+	}
+	if batches[0].Items[0].TransferID != 7 || batches[0].Items[1].Status != model.BatchItemStatusPending {
+		t.Errorf("retrieved batch items do not match: got %+v", batches[0].Items)
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathTransferLimit = "transfer_limit.json"
+)
+
+func TestSaveAndUpdateTransferLimit(t *testing.T) {
+	repo := repository.NewTransferLimitRepoImpl(testFilePathTransferLimit)
+	defer os.Remove(testFilePathTransferLimit)
+
+	// Save a type level limit and a user override
+	typeLimit, err := repo.Save(model.TransferLimit{AccountType: model.AccountTypeSavings, DailyAmount: 1000})
+	if err != nil {
+		t.Fatalf("failed to save transfer limit: %v", err)
+	}
+	userLimit, err := repo.Save(model.TransferLimit{AccountType: model.AccountTypeSavings, UserID: 1, DailyAmount: 5000})
+	if err != nil {
+		t.Fatalf("failed to save transfer limit: %v", err)
+	}
+	if typeLimit.ID != 1 || userLimit.ID != 2 {
+		t.Errorf("incorrect transfer limit IDs: got %d and %d, want 1 and 2", typeLimit.ID, userLimit.ID)
+	}
+
+	// Update the user override
+	userLimit.DailyCount = 3
+	_, err = repo.Update(userLimit)
+	if err != nil {
+		t.Fatalf("failed to update transfer limit: %v", err)
+	}
+
+	retrievedLimit, err := repo.FindById(userLimit.ID)
+	if err != nil {
+		t.Fatalf("failed to retrieve transfer limit: %v", err)
+	}
+	if retrievedLimit.DailyCount != 3 {
+		t.Errorf("retrieved transfer limit daily count does not match: got %d, want %d", retrievedLimit.DailyCount, 3)
+	}
+
+	// Delete the type level limit
+	_, err = repo.Delete(typeLimit.ID)
+	if err != nil {
+		t.Fatalf("failed to delete transfer limit: %v", err)
+	}
+	limits, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("failed to retrieve transfer limits: %v", err)
+	}
+	if len(limits) != 1 {
+		t.Errorf("incorrect number of transfer limits: got %d, want %d", len(limits), 1)
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	testFilePathVirtualAccount        = "virtual_account.json"
+	testFilePathVirtualAccountPayment = "virtual_account_payment.json"
+)
+
+func TestFindByNumberVirtualAccount(t *testing.T) {
+	repo := repository.NewVirtualAccountRepoImpl(testFilePathVirtualAccount)
+	defer os.Remove(testFilePathVirtualAccount)
+
+	virtualAccounts := []model.VirtualAccount{
+		{UserID: 1, AccountID: 1, Number: "48588000000000101", Name: "INV-1"},
+		{UserID: 1, AccountID: 1, Number: "48588000000000202", Name: "INV-2"},
+	}
+	for _, virtualAccount := range virtualAccounts {
+		if _, err := repo.Save(virtualAccount); err != nil {
+			t.Fatalf("failed to save virtual account: %v", err)
+		}
+	}
+
+	// Retrieve a virtual account by its number
+	virtualAccount, err := repo.FindByNumber("48588000000000202")
+	if err != nil {
+		t.Fatalf("failed to retrieve virtual account: %v", err)
+	}
+	if virtualAccount.Name != "INV-2" {
+		t.Errorf("incorrect virtual account: got %s, want %s", virtualAccount.Name, "INV-2")
+	}
+
+	if _, err := repo.FindByNumber("48588000000000303"); err == nil {
+		t.Errorf("expected an error for an unknown number")
+	}
+}
+
+func TestFindByReferenceVirtualAccountPayment(t *testing.T) {
+	repo := repository.NewVirtualAccountPaymentRepoImpl(testFilePathVirtualAccountPayment)
+	defer os.Remove(testFilePathVirtualAccountPayment)
+
+	payments := []model.VirtualAccountPayment{
+		{VirtualAccountID: 1, Amount: 10000, Reference: "NET-1"},
+		{VirtualAccountID: 1, Amount: 20000, Reference: "NET-2"},
+		{VirtualAccountID: 2, Amount: 30000, Reference: "NET-3"},
+	}
+	for _, payment := range payments {
+		if _, err := repo.Save(payment); err != nil {
+			t.Fatalf("failed to save payment: %v", err)
+		}
+	}
+
+	// Retrieve a payment by its clearing reference
+	payment, err := repo.FindByReference("NET-2")
+	if err != nil {
+		t.Fatalf("failed to retrieve payment: %v", err)
+	}
+	if payment.Amount != 20000 {
+		t.Errorf("incorrect payment amount: got %v, want %v", payment.Amount, 20000)
+	}
+
+	virtualAccountPayments, err := repo.FindByVirtualAccountId(1)
+	if err != nil {
+		t.Fatalf("failed to retrieve payments: %v", err)
+	}
+	if len(virtualAccountPayments) != 2 {
+		t.Errorf("incorrect number of payments: got %d, want %d", len(virtualAccountPayments), 2)
+	}
+}
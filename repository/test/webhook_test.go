@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const testFilePathWebhookDelivery = "webhook_delivery.json"
+
+func TestFindBySubscriptionIdWebhookDelivery(t *testing.T) {
+	repo := repository.NewWebhookDeliveryRepoImpl(testFilePathWebhookDelivery)
+	defer os.Remove(testFilePathWebhookDelivery)
+
+	deliveries := []model.WebhookDelivery{
+		{SubscriptionID: 1, EventID: "evt_1", EventType: model.EventTransferCompleted, Payload: []byte(`{"id":"evt_1"}`)},
+		{SubscriptionID: 2, EventID: "evt_1", EventType: model.EventTransferCompleted, Payload: []byte(`{"id":"evt_1"}`)},
+		{SubscriptionID: 1, EventID: "evt_2", EventType: model.EventAccountBalanceChanged, Payload: []byte(`{"id":"evt_2"}`)},
+	}
+	for _, delivery := range deliveries {
+		if _, err := repo.Save(delivery); err != nil {
+			t.Fatalf("failed to save delivery: %v", err)
+		}
+	}
+
+	// Retrieve the deliveries of one subscription
+	subscriptionDeliveries, err := repo.FindBySubscriptionId(1)
+	if err != nil {
+		t.Fatalf("failed to retrieve deliveries: %v", err)
+	}
+	if len(subscriptionDeliveries) != 2 {
+		t.Fatalf("incorrect number of deliveries: got %d, want %d", len(subscriptionDeliveries), 2)
+	}
+	if string(subscriptionDeliveries[1].Payload) != `{"id":"evt_2"}` {
+		t.Errorf("incorrect payload: got %s, want %s", subscriptionDeliveries[1].Payload, `{"id":"evt_2"}`)
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type TransferApprovalRepo interface {
+	Save(newTransferApproval model.TransferApproval) (model.TransferApproval, error)
+	Update(updatedTransferApproval model.TransferApproval) (model.TransferApproval, error)
+	Delete(id int64) (model.TransferApproval, error)
+	FindById(id int64) (model.TransferApproval, error)
+	FindByTransferId(transferID int64) (model.TransferApproval, error)
+	FindAll() ([]model.TransferApproval, error)
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type TransferApprovalRepoImpl struct {
+	filePath string
+}
+
+// Delete implements TransferApprovalRepo
+func (r *TransferApprovalRepoImpl) Delete(id int64) (model.TransferApproval, error) {
+	approvals, err := r.FindAll()
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	var deletedApproval model.TransferApproval
+	for i, approval := range approvals {
+		if approval.ID == id {
+			deletedApproval = approval
+			approvals = append(approvals[:i], approvals[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeApprovalsToFile(approvals)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	return deletedApproval, nil
+}
+
+// FindAll implements TransferApprovalRepo
+func (r *TransferApprovalRepoImpl) FindAll() ([]model.TransferApproval, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.TransferApproval{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var approvals []model.TransferApproval
+	err = json.NewDecoder(file).Decode(&approvals)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return approvals, nil
+}
+
+// FindById implements TransferApprovalRepo
+func (r *TransferApprovalRepoImpl) FindById(id int64) (model.TransferApproval, error) {
+	approvals, err := r.FindAll()
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	for _, approval := range approvals {
+		if approval.ID == id {
+			return approval, nil
+		}
+	}
+
+	return model.TransferApproval{}, fmt.Errorf("transfer approval by id: %d not found", id)
+}
+
+// FindByTransferId implements TransferApprovalRepo
+func (r *TransferApprovalRepoImpl) FindByTransferId(transferID int64) (model.TransferApproval, error) {
+	approvals, err := r.FindAll()
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	for _, approval := range approvals {
+		if approval.TransferID == transferID {
+			return approval, nil
+		}
+	}
+
+	return model.TransferApproval{}, fmt.Errorf("transfer approval by transfer id: %d not found", transferID)
+}
+
+// Save implements TransferApprovalRepo
+func (r *TransferApprovalRepoImpl) Save(newApproval model.TransferApproval) (model.TransferApproval, error) {
+	approvals, err := r.FindAll()
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	newApproval.ID = generateUniqueIDTransferApproval(approvals)
+	newApproval.CreatedAt = time.Now()
+
+	approvals = append(approvals, newApproval)
+
+	err = r.writeApprovalsToFile(approvals)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	return newApproval, nil
+}
+
+// Update implements TransferApprovalRepo
+func (r *TransferApprovalRepoImpl) Update(updatedApproval model.TransferApproval) (model.TransferApproval, error) {
+	approvals, err := r.FindAll()
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	var found bool
+	for i, approval := range approvals {
+		if approval.ID == updatedApproval.ID {
+			approvals[i] = updatedApproval
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.TransferApproval{}, fmt.Errorf("transfer approval by id: %d not found", updatedApproval.ID)
+	}
+
+	err = r.writeApprovalsToFile(approvals)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	return updatedApproval, nil
+}
+
+func (r *TransferApprovalRepoImpl) writeApprovalsToFile(approvals []model.TransferApproval) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(approvals)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDTransferApproval(approvals []model.TransferApproval) int64 {
+	var maxID int64
+	for _, approval := range approvals {
+		if approval.ID > maxID {
+			maxID = approval.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewTransferApprovalRepoImpl(filePath string) TransferApprovalRepo {
+	return &TransferApprovalRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type TransferBatchRepo interface {
+	Save(newTransferBatch model.TransferBatch) (model.TransferBatch, error)
+	Update(updatedTransferBatch model.TransferBatch) (model.TransferBatch, error)
+	Delete(id int64) (model.TransferBatch, error)
+	FindById(id int64) (model.TransferBatch, error)
+	FindByUserId(userID int64) ([]model.TransferBatch, error)
+	FindAll() ([]model.TransferBatch, error)
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type TransferBatchRepoImpl struct {
+	filePath string
+}
+
+// Delete implements TransferBatchRepo
+func (r *TransferBatchRepoImpl) Delete(id int64) (model.TransferBatch, error) {
+	batches, err := r.FindAll()
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	var deletedBatch model.TransferBatch
+	for i, batch := range batches {
+		if batch.ID == id {
+			deletedBatch = batch
+			batches = append(batches[:i], batches[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeBatchesToFile(batches)
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	return deletedBatch, nil
+}
+
+// FindAll implements TransferBatchRepo
+func (r *TransferBatchRepoImpl) FindAll() ([]model.TransferBatch, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.TransferBatch{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var batches []model.TransferBatch
+	err = json.NewDecoder(file).Decode(&batches)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+// FindById implements TransferBatchRepo
+func (r *TransferBatchRepoImpl) FindById(id int64) (model.TransferBatch, error) {
+	batches, err := r.FindAll()
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	for _, batch := range batches {
+		if batch.ID == id {
+			return batch, nil
+		}
+	}
+
+	return model.TransferBatch{}, fmt.Errorf("transfer batch by id: %d not found", id)
+}
+
+// FindByUserId implements TransferBatchRepo
+func (r *TransferBatchRepoImpl) FindByUserId(userID int64) ([]model.TransferBatch, error) {
+	batches, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var userBatches []model.TransferBatch
+	for _, batch := range batches {
+		if batch.UserID == userID {
+			userBatches = append(userBatches, batch)
+		}
+	}
+
+	return userBatches, nil
+}
+
+// Save implements TransferBatchRepo
+func (r *TransferBatchRepoImpl) Save(newBatch model.TransferBatch) (model.TransferBatch, error) {
+	batches, err := r.FindAll()
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	newBatch.ID = generateUniqueIDTransferBatch(batches)
+	newBatch.CreatedAt = time.Now()
+
+	batches = append(batches, newBatch)
+
+	err = r.writeBatchesToFile(batches)
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	return newBatch, nil
+}
+
+// Update implements TransferBatchRepo
+func (r *TransferBatchRepoImpl) Update(updatedBatch model.TransferBatch) (model.TransferBatch, error) {
+	batches, err := r.FindAll()
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	var found bool
+	for i, batch := range batches {
+		if batch.ID == updatedBatch.ID {
+			batches[i] = updatedBatch
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.TransferBatch{}, fmt.Errorf("transfer batch by id: %d not found", updatedBatch.ID)
+	}
+
+	err = r.writeBatchesToFile(batches)
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	return updatedBatch, nil
+}
+
+func (r *TransferBatchRepoImpl) writeBatchesToFile(batches []model.TransferBatch) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(batches)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDTransferBatch(batches []model.TransferBatch) int64 {
+	var maxID int64
+	for _, batch := range batches {
+		if batch.ID > maxID {
+			maxID = batch.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewTransferBatchRepoImpl(filePath string) TransferBatchRepo {
+	return &TransferBatchRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type TransferLimitRepo interface {
+	Save(newTransferLimit model.TransferLimit) (model.TransferLimit, error)
+	Update(updatedTransferLimit model.TransferLimit) (model.TransferLimit, error)
+	Delete(id int64) (model.TransferLimit, error)
+	FindById(id int64) (model.TransferLimit, error)
+	FindAll() ([]model.TransferLimit, error)
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type TransferLimitRepoImpl struct {
+	filePath string
+}
+
+// Delete implements TransferLimitRepo
+func (r *TransferLimitRepoImpl) Delete(id int64) (model.TransferLimit, error) {
+	limits, err := r.FindAll()
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	var deletedLimit model.TransferLimit
+	for i, limit := range limits {
+		if limit.ID == id {
+			deletedLimit = limit
+			limits = append(limits[:i], limits[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeLimitsToFile(limits)
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	return deletedLimit, nil
+}
+
+// FindAll implements TransferLimitRepo
+func (r *TransferLimitRepoImpl) FindAll() ([]model.TransferLimit, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.TransferLimit{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var limits []model.TransferLimit
+	err = json.NewDecoder(file).Decode(&limits)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// FindById implements TransferLimitRepo
+func (r *TransferLimitRepoImpl) FindById(id int64) (model.TransferLimit, error) {
+	limits, err := r.FindAll()
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	for _, limit := range limits {
+		if limit.ID == id {
+			return limit, nil
+		}
+	}
+
+	return model.TransferLimit{}, fmt.Errorf("transfer limit by id: %d not found", id)
+}
+
+// Save implements TransferLimitRepo
+func (r *TransferLimitRepoImpl) Save(newLimit model.TransferLimit) (model.TransferLimit, error) {
+	limits, err := r.FindAll()
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	newLimit.ID = generateUniqueIDTransferLimit(limits)
+	newLimit.CreatedAt = time.Now()
+
+	limits = append(limits, newLimit)
+
+	err = r.writeLimitsToFile(limits)
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	return newLimit, nil
+}
+
+// Update implements TransferLimitRepo
+func (r *TransferLimitRepoImpl) Update(updatedLimit model.TransferLimit) (model.TransferLimit, error) {
+	limits, err := r.FindAll()
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	var found bool
+	for i, limit := range limits {
+		if limit.ID == updatedLimit.ID {
+			limits[i] = updatedLimit
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.TransferLimit{}, fmt.Errorf("transfer limit by id: %d not found", updatedLimit.ID)
+	}
+
+	err = r.writeLimitsToFile(limits)
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	return updatedLimit, nil
+}
+
+func (r *TransferLimitRepoImpl) writeLimitsToFile(limits []model.TransferLimit) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(limits)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDTransferLimit(limits []model.TransferLimit) int64 {
+	var maxID int64
+	for _, limit := range limits {
+		if limit.ID > maxID {
+			maxID = limit.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewTransferLimitRepoImpl(filePath string) TransferLimitRepo {
+	return &TransferLimitRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -0,0 +1,23 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type VirtualAccountRepo interface {
+	Save(newVirtualAccount model.VirtualAccount) (model.VirtualAccount, error)
+	Update(updatedVirtualAccount model.VirtualAccount) (model.VirtualAccount, error)
+	Delete(id int64) (model.VirtualAccount, error)
+	FindById(id int64) (model.VirtualAccount, error)
+	FindAll() ([]model.VirtualAccount, error)
+	FindByUserId(userID int64) ([]model.VirtualAccount, error)
+	FindByNumber(number string) (model.VirtualAccount, error)
+}
+
+type VirtualAccountPaymentRepo interface {
+	Save(newPayment model.VirtualAccountPayment) (model.VirtualAccountPayment, error)
+	Update(updatedPayment model.VirtualAccountPayment) (model.VirtualAccountPayment, error)
+	Delete(id int64) (model.VirtualAccountPayment, error)
+	FindById(id int64) (model.VirtualAccountPayment, error)
+	FindAll() ([]model.VirtualAccountPayment, error)
+	FindByVirtualAccountId(virtualAccountID int64) ([]model.VirtualAccountPayment, error)
+	FindByReference(reference string) (model.VirtualAccountPayment, error)
+}
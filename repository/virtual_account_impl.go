@@ -0,0 +1,363 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type VirtualAccountRepoImpl struct {
+	filePath string
+}
+
+// Delete implements VirtualAccountRepo
+func (r *VirtualAccountRepoImpl) Delete(id int64) (model.VirtualAccount, error) {
+	virtualAccounts, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	var deletedVirtualAccount model.VirtualAccount
+	for i, virtualAccount := range virtualAccounts {
+		if virtualAccount.ID == id {
+			deletedVirtualAccount = virtualAccount
+			virtualAccounts = append(virtualAccounts[:i], virtualAccounts[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeVirtualAccountsToFile(virtualAccounts)
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	return deletedVirtualAccount, nil
+}
+
+// FindAll implements VirtualAccountRepo
+func (r *VirtualAccountRepoImpl) FindAll() ([]model.VirtualAccount, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.VirtualAccount{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var virtualAccounts []model.VirtualAccount
+	err = json.NewDecoder(file).Decode(&virtualAccounts)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return virtualAccounts, nil
+}
+
+// FindById implements VirtualAccountRepo
+func (r *VirtualAccountRepoImpl) FindById(id int64) (model.VirtualAccount, error) {
+	virtualAccounts, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	for _, virtualAccount := range virtualAccounts {
+		if virtualAccount.ID == id {
+			return virtualAccount, nil
+		}
+	}
+
+	return model.VirtualAccount{}, fmt.Errorf("virtual account by id: %d not found", id)
+}
+
+// FindByUserId implements VirtualAccountRepo
+func (r *VirtualAccountRepoImpl) FindByUserId(userID int64) ([]model.VirtualAccount, error) {
+	virtualAccounts, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var userVirtualAccounts []model.VirtualAccount
+	for _, virtualAccount := range virtualAccounts {
+		if virtualAccount.UserID == userID {
+			userVirtualAccounts = append(userVirtualAccounts, virtualAccount)
+		}
+	}
+
+	return userVirtualAccounts, nil
+}
+
+// FindByNumber implements VirtualAccountRepo
+func (r *VirtualAccountRepoImpl) FindByNumber(number string) (model.VirtualAccount, error) {
+	virtualAccounts, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	for _, virtualAccount := range virtualAccounts {
+		if virtualAccount.Number == number {
+			return virtualAccount, nil
+		}
+	}
+
+	return model.VirtualAccount{}, fmt.Errorf("virtual account by number: %s not found", number)
+}
+
+// Save implements VirtualAccountRepo
+func (r *VirtualAccountRepoImpl) Save(newVirtualAccount model.VirtualAccount) (model.VirtualAccount, error) {
+	virtualAccounts, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	newVirtualAccount.ID = generateUniqueIDVirtualAccount(virtualAccounts)
+	newVirtualAccount.CreatedAt = time.Now()
+
+	virtualAccounts = append(virtualAccounts, newVirtualAccount)
+
+	err = r.writeVirtualAccountsToFile(virtualAccounts)
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	return newVirtualAccount, nil
+}
+
+// Update implements VirtualAccountRepo
+func (r *VirtualAccountRepoImpl) Update(updatedVirtualAccount model.VirtualAccount) (model.VirtualAccount, error) {
+	virtualAccounts, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	var found bool
+	for i, virtualAccount := range virtualAccounts {
+		if virtualAccount.ID == updatedVirtualAccount.ID {
+			virtualAccounts[i] = updatedVirtualAccount
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.VirtualAccount{}, fmt.Errorf("virtual account by id: %d not found", updatedVirtualAccount.ID)
+	}
+
+	err = r.writeVirtualAccountsToFile(virtualAccounts)
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	return updatedVirtualAccount, nil
+}
+
+func (r *VirtualAccountRepoImpl) writeVirtualAccountsToFile(virtualAccounts []model.VirtualAccount) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(virtualAccounts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDVirtualAccount(virtualAccounts []model.VirtualAccount) int64 {
+	var maxID int64
+	for _, virtualAccount := range virtualAccounts {
+		if virtualAccount.ID > maxID {
+			maxID = virtualAccount.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewVirtualAccountRepoImpl(filePath string) VirtualAccountRepo {
+	return &VirtualAccountRepoImpl{
+		filePath: filePath,
+	}
+}
+
+type VirtualAccountPaymentRepoImpl struct {
+	filePath string
+}
+
+// Delete implements VirtualAccountPaymentRepo
+func (r *VirtualAccountPaymentRepoImpl) Delete(id int64) (model.VirtualAccountPayment, error) {
+	payments, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	var deletedPayment model.VirtualAccountPayment
+	for i, payment := range payments {
+		if payment.ID == id {
+			deletedPayment = payment
+			payments = append(payments[:i], payments[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writePaymentsToFile(payments)
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	return deletedPayment, nil
+}
+
+// FindAll implements VirtualAccountPaymentRepo
+func (r *VirtualAccountPaymentRepoImpl) FindAll() ([]model.VirtualAccountPayment, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.VirtualAccountPayment{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var payments []model.VirtualAccountPayment
+	err = json.NewDecoder(file).Decode(&payments)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// FindById implements VirtualAccountPaymentRepo
+func (r *VirtualAccountPaymentRepoImpl) FindById(id int64) (model.VirtualAccountPayment, error) {
+	payments, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	for _, payment := range payments {
+		if payment.ID == id {
+			return payment, nil
+		}
+	}
+
+	return model.VirtualAccountPayment{}, fmt.Errorf("virtual account payment by id: %d not found", id)
+}
+
+// FindByVirtualAccountId implements VirtualAccountPaymentRepo
+func (r *VirtualAccountPaymentRepoImpl) FindByVirtualAccountId(virtualAccountID int64) ([]model.VirtualAccountPayment, error) {
+	payments, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var virtualAccountPayments []model.VirtualAccountPayment
+	for _, payment := range payments {
+		if payment.VirtualAccountID == virtualAccountID {
+			virtualAccountPayments = append(virtualAccountPayments, payment)
+		}
+	}
+
+	return virtualAccountPayments, nil
+}
+
+// FindByReference implements VirtualAccountPaymentRepo
+func (r *VirtualAccountPaymentRepoImpl) FindByReference(reference string) (model.VirtualAccountPayment, error) {
+	payments, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	for _, payment := range payments {
+		if payment.Reference == reference {
+			return payment, nil
+		}
+	}
+
+	return model.VirtualAccountPayment{}, fmt.Errorf("virtual account payment by reference: %s not found", reference)
+}
+
+// Save implements VirtualAccountPaymentRepo
+func (r *VirtualAccountPaymentRepoImpl) Save(newPayment model.VirtualAccountPayment) (model.VirtualAccountPayment, error) {
+	payments, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	newPayment.ID = generateUniqueIDVirtualAccountPayment(payments)
+	newPayment.CreatedAt = time.Now()
+
+	payments = append(payments, newPayment)
+
+	err = r.writePaymentsToFile(payments)
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	return newPayment, nil
+}
+
+// Update implements VirtualAccountPaymentRepo
+func (r *VirtualAccountPaymentRepoImpl) Update(updatedPayment model.VirtualAccountPayment) (model.VirtualAccountPayment, error) {
+	payments, err := r.FindAll()
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	var found bool
+	for i, payment := range payments {
+		if payment.ID == updatedPayment.ID {
+			payments[i] = updatedPayment
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.VirtualAccountPayment{}, fmt.Errorf("virtual account payment by id: %d not found", updatedPayment.ID)
+	}
+
+	err = r.writePaymentsToFile(payments)
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	return updatedPayment, nil
+}
+
+func (r *VirtualAccountPaymentRepoImpl) writePaymentsToFile(payments []model.VirtualAccountPayment) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(payments)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDVirtualAccountPayment(payments []model.VirtualAccountPayment) int64 {
+	var maxID int64
+	for _, payment := range payments {
+		if payment.ID > maxID {
+			maxID = payment.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewVirtualAccountPaymentRepoImpl(filePath string) VirtualAccountPaymentRepo {
+	return &VirtualAccountPaymentRepoImpl{
+		filePath: filePath,
+	}
+}
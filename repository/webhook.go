@@ -0,0 +1,20 @@
+package repository
+
+import "github.com/sferawann/test_mnc/model"
+
+type WebhookSubscriptionRepo interface {
+	Save(newSubscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	Update(updatedSubscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	Delete(id int64) (model.WebhookSubscription, error)
+	FindById(id int64) (model.WebhookSubscription, error)
+	FindAll() ([]model.WebhookSubscription, error)
+}
+
+type WebhookDeliveryRepo interface {
+	Save(newDelivery model.WebhookDelivery) (model.WebhookDelivery, error)
+	Update(updatedDelivery model.WebhookDelivery) (model.WebhookDelivery, error)
+	Delete(id int64) (model.WebhookDelivery, error)
+	FindById(id int64) (model.WebhookDelivery, error)
+	FindAll() ([]model.WebhookDelivery, error)
+	FindBySubscriptionId(subscriptionID int64) ([]model.WebhookDelivery, error)
+}
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type WebhookSubscriptionRepoImpl struct {
+	filePath string
+}
+
+// Delete implements WebhookSubscriptionRepo
+func (r *WebhookSubscriptionRepoImpl) Delete(id int64) (model.WebhookSubscription, error) {
+	subscriptions, err := r.FindAll()
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	var deletedSubscription model.WebhookSubscription
+	for i, subscription := range subscriptions {
+		if subscription.ID == id {
+			deletedSubscription = subscription
+			subscriptions = append(subscriptions[:i], subscriptions[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeSubscriptionsToFile(subscriptions)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	return deletedSubscription, nil
+}
+
+// FindAll implements WebhookSubscriptionRepo
+func (r *WebhookSubscriptionRepoImpl) FindAll() ([]model.WebhookSubscription, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.WebhookSubscription{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var subscriptions []model.WebhookSubscription
+	err = json.NewDecoder(file).Decode(&subscriptions)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// FindById implements WebhookSubscriptionRepo
+func (r *WebhookSubscriptionRepoImpl) FindById(id int64) (model.WebhookSubscription, error) {
+	subscriptions, err := r.FindAll()
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.ID == id {
+			return subscription, nil
+		}
+	}
+
+	return model.WebhookSubscription{}, fmt.Errorf("webhook subscription by id: %d not found", id)
+}
+
+// Save implements WebhookSubscriptionRepo
+func (r *WebhookSubscriptionRepoImpl) Save(newSubscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	subscriptions, err := r.FindAll()
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	newSubscription.ID = generateUniqueIDWebhookSubscription(subscriptions)
+	newSubscription.CreatedAt = time.Now()
+
+	subscriptions = append(subscriptions, newSubscription)
+
+	err = r.writeSubscriptionsToFile(subscriptions)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	return newSubscription, nil
+}
+
+// Update implements WebhookSubscriptionRepo
+func (r *WebhookSubscriptionRepoImpl) Update(updatedSubscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	subscriptions, err := r.FindAll()
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	var found bool
+	for i, subscription := range subscriptions {
+		if subscription.ID == updatedSubscription.ID {
+			subscriptions[i] = updatedSubscription
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.WebhookSubscription{}, fmt.Errorf("webhook subscription by id: %d not found", updatedSubscription.ID)
+	}
+
+	err = r.writeSubscriptionsToFile(subscriptions)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	return updatedSubscription, nil
+}
+
+func (r *WebhookSubscriptionRepoImpl) writeSubscriptionsToFile(subscriptions []model.WebhookSubscription) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(subscriptions)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDWebhookSubscription(subscriptions []model.WebhookSubscription) int64 {
+	var maxID int64
+	for _, subscription := range subscriptions {
+		if subscription.ID > maxID {
+			maxID = subscription.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewWebhookSubscriptionRepoImpl(filePath string) WebhookSubscriptionRepo {
+	return &WebhookSubscriptionRepoImpl{
+		filePath: filePath,
+	}
+}
+
+type WebhookDeliveryRepoImpl struct {
+	filePath string
+}
+
+// Delete implements WebhookDeliveryRepo
+func (r *WebhookDeliveryRepoImpl) Delete(id int64) (model.WebhookDelivery, error) {
+	deliveries, err := r.FindAll()
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	var deletedDelivery model.WebhookDelivery
+	for i, delivery := range deliveries {
+		if delivery.ID == id {
+			deletedDelivery = delivery
+			deliveries = append(deliveries[:i], deliveries[i+1:]...)
+			break
+		}
+	}
+
+	err = r.writeDeliveriesToFile(deliveries)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return deletedDelivery, nil
+}
+
+// FindAll implements WebhookDeliveryRepo
+func (r *WebhookDeliveryRepoImpl) FindAll() ([]model.WebhookDelivery, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.WebhookDelivery{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var deliveries []model.WebhookDelivery
+	err = json.NewDecoder(file).Decode(&deliveries)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// FindById implements WebhookDeliveryRepo
+func (r *WebhookDeliveryRepoImpl) FindById(id int64) (model.WebhookDelivery, error) {
+	deliveries, err := r.FindAll()
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+
+	return model.WebhookDelivery{}, fmt.Errorf("webhook delivery by id: %d not found", id)
+}
+
+// FindBySubscriptionId implements WebhookDeliveryRepo
+func (r *WebhookDeliveryRepoImpl) FindBySubscriptionId(subscriptionID int64) ([]model.WebhookDelivery, error) {
+	deliveries, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var subscriptionDeliveries []model.WebhookDelivery
+	for _, delivery := range deliveries {
+		if delivery.SubscriptionID == subscriptionID {
+			subscriptionDeliveries = append(subscriptionDeliveries, delivery)
+		}
+	}
+
+	return subscriptionDeliveries, nil
+}
+
+// Save implements WebhookDeliveryRepo
+func (r *WebhookDeliveryRepoImpl) Save(newDelivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	deliveries, err := r.FindAll()
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	newDelivery.ID = generateUniqueIDWebhookDelivery(deliveries)
+	newDelivery.CreatedAt = time.Now()
+
+	deliveries = append(deliveries, newDelivery)
+
+	err = r.writeDeliveriesToFile(deliveries)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return newDelivery, nil
+}
+
+// Update implements WebhookDeliveryRepo
+func (r *WebhookDeliveryRepoImpl) Update(updatedDelivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	deliveries, err := r.FindAll()
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	var found bool
+	for i, delivery := range deliveries {
+		if delivery.ID == updatedDelivery.ID {
+			deliveries[i] = updatedDelivery
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return model.WebhookDelivery{}, fmt.Errorf("webhook delivery by id: %d not found", updatedDelivery.ID)
+	}
+
+	err = r.writeDeliveriesToFile(deliveries)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return updatedDelivery, nil
+}
+
+func (r *WebhookDeliveryRepoImpl) writeDeliveriesToFile(deliveries []model.WebhookDelivery) error {
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(deliveries)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func generateUniqueIDWebhookDelivery(deliveries []model.WebhookDelivery) int64 {
+	var maxID int64
+	for _, delivery := range deliveries {
+		if delivery.ID > maxID {
+			maxID = delivery.ID
+		}
+	}
+	return maxID + 1
+}
+
+func NewWebhookDeliveryRepoImpl(filePath string) WebhookDeliveryRepo {
+	return &WebhookDeliveryRepoImpl{
+		filePath: filePath,
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sferawann/test_mnc/controller"
 	"github.com/sferawann/test_mnc/middleware"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
 )
 
-func NewRouter(userCon *controller.UserCon, accCon *controller.AccountCon, hisCon *controller.HistoryCon, traCon *controller.TransferCon, sesCon *controller.SessionCon, authCon *controller.AuthCon) *gin.Engine {
+func NewRouter(userCon *controller.UserCon, accCon *controller.AccountCon, hisCon *controller.HistoryCon, traCon *controller.TransferCon, sesCon *controller.SessionCon, authCon *controller.AuthCon, schCon *controller.ScheduledTransferCon, limCon *controller.TransferLimitCon, feeCon *controller.FeeCon, holdCon *controller.HoldCon, batchCon *controller.TransferBatchCon, apprCon *controller.TransferApprovalCon, auditCon *controller.AuditCon, stmtCon *controller.StatementCon, recCon *controller.ReconciliationCon, prodCon *controller.AccountProductCon, intCon *controller.InterestCon, fxCon *controller.FxCon, benCon *controller.BeneficiaryCon, payReqCon *controller.PaymentRequestCon, merchantCon *controller.MerchantCon, vaCon *controller.VirtualAccountCon, webhookCon *controller.WebhookCon, streamCon *controller.StreamCon, outboxCon *controller.OutboxCon, accEventCon *controller.AccountEventCon, balanceCon *controller.BalanceCon, userRepo repository.UserRepo) *gin.Engine {
 	r := gin.Default()
 
 	r.GET("", func(context *gin.Context) {
@@ -47,13 +49,24 @@ func NewRouter(userCon *controller.UserCon, accCon *controller.AccountCon, hisCo
 	accRouter := router.Group("/account")
 	{
 		accRouter.GET("/", accCon.FindAll)
+		accRouter.GET("/products", prodCon.FindAll)
+		accRouter.GET("/products/:code", prodCon.FindByCode)
 		accRouter.GET("/:id", accCon.FindByID)
 		accRouter.PUT("/:id", accCon.Update)
 		accRouter.DELETE("/:id", accCon.Delete)
 		accRouter.Use(middleware.AuthMiddleware())
 		{
 			accRouter.GET("/get", accCon.GetByUserID)
+			accRouter.GET("/number/:number", accCon.FindByNumber)
 			accRouter.POST("/", accCon.Create)
+			accRouter.GET("/:id/limit", limCon.Remaining)
+			accRouter.GET("/:id/statement", stmtCon.Download)
+			accRouter.GET("/:id/interest", intCon.Accruals)
+			accRouter.GET("/:id/balance", balanceCon.BalanceAt)
+			accRouter.POST("/:id/deposit", accCon.Deposit)
+			accRouter.POST("/:id/withdraw", accCon.Withdraw)
+			accRouter.POST("/:id/freeze", accCon.Freeze)
+			accRouter.POST("/:id/close", accCon.Close)
 		}
 	}
 
@@ -78,6 +91,49 @@ func NewRouter(userCon *controller.UserCon, accCon *controller.AccountCon, hisCo
 			traRouter.GET("/:id", traCon.FindByID)
 			traRouter.PUT("/:id", traCon.Update)
 			traRouter.DELETE("/:id", traCon.Delete)
+			traRouter.GET("/limit/", limCon.FindAll)
+			traRouter.GET("/fee", feeCon.Quote)
+			traRouter.GET("/fee/schedule", feeCon.Schedule)
+		}
+
+		schRouter := traRouter.Group("/schedule")
+		{
+			schRouter.GET("/", schCon.FindAll)
+			schRouter.POST("/", schCon.Create)
+			schRouter.GET("/:id", schCon.FindByID)
+			schRouter.GET("/:id/executions", schCon.FindExecutions)
+			schRouter.PUT("/:id", schCon.Update)
+			schRouter.DELETE("/:id", schCon.Delete)
+		}
+
+		batchRouter := traRouter.Group("/batch")
+		{
+			batchRouter.GET("/", batchCon.FindAll)
+			batchRouter.POST("/", batchCon.Create)
+			batchRouter.GET("/:id", batchCon.FindByID)
+		}
+
+		apprRouter := traRouter.Group("/approvals")
+		{
+			apprRouter.Use(middleware.RequireRole(userRepo, model.RoleApprover, model.RoleAdmin))
+			{
+				apprRouter.GET("/", apprCon.FindAll)
+				apprRouter.GET("/:id", apprCon.FindByID)
+				apprRouter.POST("/:id/approve", apprCon.Approve)
+				apprRouter.POST("/:id/reject", apprCon.Reject)
+			}
+		}
+	}
+
+	holdRouter := router.Group("/hold")
+	{
+		holdRouter.Use(middleware.AuthMiddleware())
+		{
+			holdRouter.GET("/", holdCon.FindByAccountID)
+			holdRouter.POST("/", holdCon.Create)
+			holdRouter.GET("/:id", holdCon.FindByID)
+			holdRouter.POST("/:id/capture", holdCon.Capture)
+			holdRouter.POST("/:id/release", holdCon.Release)
 		}
 	}
 
@@ -93,5 +149,117 @@ func NewRouter(userCon *controller.UserCon, accCon *controller.AccountCon, hisCo
 		}
 	}
 
+	benRouter := router.Group("/beneficiary")
+	{
+		benRouter.Use(middleware.AuthMiddleware())
+		{
+			benRouter.GET("/", benCon.FindAll)
+			benRouter.POST("/", benCon.Create)
+			benRouter.GET("/:id", benCon.FindByID)
+			benRouter.PUT("/:id", benCon.Update)
+			benRouter.DELETE("/:id", benCon.Delete)
+		}
+	}
+
+	payReqRouter := router.Group("/payment-request")
+	{
+		payReqRouter.Use(middleware.AuthMiddleware())
+		{
+			payReqRouter.POST("/", payReqCon.Create)
+			payReqRouter.POST("/split", payReqCon.Split)
+			payReqRouter.GET("/incoming", payReqCon.FindIncoming)
+			payReqRouter.GET("/outgoing", payReqCon.FindOutgoing)
+			payReqRouter.GET("/:id", payReqCon.FindByID)
+			payReqRouter.POST("/:id/accept", payReqCon.Accept)
+			payReqRouter.POST("/:id/decline", payReqCon.Decline)
+			payReqRouter.POST("/:id/cancel", payReqCon.Cancel)
+		}
+	}
+
+	merchantRouter := router.Group("/merchant")
+	{
+		merchantRouter.Use(middleware.AuthMiddleware())
+		{
+			merchantRouter.GET("/", merchantCon.FindAll)
+			merchantRouter.POST("/", merchantCon.Create)
+			merchantRouter.GET("/:id", merchantCon.FindByID)
+			merchantRouter.POST("/:id/qr", merchantCon.GenerateQR)
+			merchantRouter.GET("/:id/payments", merchantCon.FindPayments)
+		}
+	}
+
+	qrRouter := router.Group("/qr")
+	{
+		qrRouter.Use(middleware.AuthMiddleware())
+		{
+			qrRouter.POST("/decode", merchantCon.DecodeQR)
+			qrRouter.POST("/pay", merchantCon.PayQR)
+		}
+	}
+
+	vaRouter := router.Group("/virtual-account")
+	{
+		vaRouter.Use(middleware.AuthMiddleware())
+		{
+			vaRouter.GET("/", vaCon.FindAll)
+			vaRouter.POST("/", vaCon.Create)
+			vaRouter.GET("/:id", vaCon.FindByID)
+			vaRouter.GET("/:id/payments", vaCon.FindPayments)
+			vaRouter.POST("/:id/close", vaCon.Close)
+		}
+	}
+
+	streamRouter := router.Group("/stream")
+	{
+		streamRouter.Use(middleware.AuthMiddleware())
+		{
+			streamRouter.GET("/", streamCon.SSE)
+			streamRouter.GET("/ws", streamCon.WebSocket)
+		}
+	}
+
+	fxRouter := router.Group("/fx")
+	{
+		fxRouter.GET("/rate", fxCon.Rate)
+		fxRouter.Use(middleware.AuthMiddleware())
+		{
+			fxRouter.POST("/quote", fxCon.Quote)
+			fxRouter.GET("/quote/:id", fxCon.FindQuoteByID)
+		}
+	}
+
+	adminRouter := router.Group("/admin")
+	{
+		adminRouter.Use(middleware.AuthMiddleware(), middleware.RequireRole(userRepo, model.RoleAdmin))
+		{
+			adminRouter.PUT("/user/:id/role", userCon.UpdateRole)
+			adminRouter.GET("/audit", auditCon.FindAll)
+			adminRouter.POST("/account/:id/freeze", accCon.AdminFreeze)
+			adminRouter.POST("/account/:id/unfreeze", accCon.AdminUnfreeze)
+			adminRouter.GET("/account/:id/events", accEventCon.FindEvents)
+			adminRouter.GET("/account/:id/state", accEventCon.State)
+			adminRouter.POST("/account/:id/snapshot", accEventCon.Snapshot)
+			adminRouter.POST("/account/:id/project", accEventCon.Project)
+			adminRouter.POST("/account-events/import", accEventCon.Import)
+			adminRouter.GET("/account/:id/balance", balanceCon.AdminBalanceAt)
+			adminRouter.GET("/balance/eod", balanceCon.EndOfDay)
+			adminRouter.GET("/reconciliation", recCon.Report)
+			adminRouter.POST("/reconciliation/adjust", recCon.Adjust)
+			adminRouter.POST("/interest/accrue", intCon.Accrue)
+			adminRouter.POST("/interest/post", intCon.Post)
+			adminRouter.POST("/virtual-account/inbound", vaCon.Inbound)
+			adminRouter.GET("/webhook", webhookCon.FindAll)
+			adminRouter.POST("/webhook", webhookCon.Create)
+			adminRouter.GET("/webhook/deliveries", webhookCon.FindDeliveries)
+			adminRouter.POST("/webhook/deliveries/:id/retry", webhookCon.RetryDelivery)
+			adminRouter.GET("/webhook/:id", webhookCon.FindByID)
+			adminRouter.PUT("/webhook/:id", webhookCon.Update)
+			adminRouter.DELETE("/webhook/:id", webhookCon.Delete)
+			adminRouter.GET("/webhook/:id/deliveries", webhookCon.FindDeliveries)
+			adminRouter.GET("/outbox", outboxCon.FindAll)
+			adminRouter.POST("/outbox/relay", outboxCon.Relay)
+		}
+	}
+
 	return r
 }
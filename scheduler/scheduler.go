@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work run on every scheduler tick.
+type Job func(now time.Time) error
+
+type namedJob struct {
+	name    string
+	run     Job
+	every   time.Duration
+	lastRun time.Time
+}
+
+// Scheduler runs registered jobs in-process on a fixed interval.
+type Scheduler struct {
+	interval time.Duration
+	jobs     []*namedJob
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewScheduler(interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Scheduler{
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register adds a job, it must be called before Start.
+func (s *Scheduler) Register(name string, job Job) {
+	s.RegisterEvery(name, 0, job)
+}
+
+// RegisterEvery adds a job that runs at most once per every, on the first
+// tick after it is due. It must be called before Start.
+func (s *Scheduler) RegisterEvery(name string, every time.Duration, job Job) {
+	s.jobs = append(s.jobs, &namedJob{name: name, run: job, every: every})
+}
+
+// Start runs the jobs in a background goroutine until Stop is called.
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.runJobs(time.Now())
+		for {
+			select {
+			case now := <-ticker.C:
+				s.runJobs(now)
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the scheduler to exit and waits for the running tick to finish.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) runJobs(now time.Time) {
+	for _, job := range s.jobs {
+		if job.every > 0 && !job.lastRun.IsZero() && now.Sub(job.lastRun) < job.every {
+			continue
+		}
+		job.lastRun = now
+
+		if err := job.run(now); err != nil {
+			log.Printf("scheduler job %s: %v", job.name, err)
+		}
+	}
+}
@@ -1,12 +1,24 @@
 package usecase
 
-import "github.com/sferawann/test_mnc/model"
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
 
 type AccountUsecase interface {
 	Save(newAccount model.Account) (model.Account, error)
 	Update(updatedAccount model.Account) (model.Account, error)
 	Delete(id int64) (model.Account, error)
 	FindById(id int64) (model.Account, error)
+	FindByNumber(number string) (model.Account, error)
 	FindByUserId(userID int64) ([]model.Account, error)
 	FindAll() ([]model.Account, error)
+	Deposit(accountID int64, amount float64, channel string, reference string, actorID int64) (model.History, error)
+	Withdraw(accountID int64, amount float64, channel string, reference string, actorID int64) (model.History, error)
+	Freeze(id int64, reason string, actorID int64) (model.Account, error)
+	Unfreeze(id int64, reason string, actorID int64) (model.Account, error)
+	Close(id int64, payoutAccountID int64, reason string, actorID int64) (model.Account, error)
+	MarkDormant(now time.Time) error
+	AssignNumbers() (int, error)
 }
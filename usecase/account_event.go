@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/eventsource"
+	"github.com/sferawann/test_mnc/model"
+)
+
+// AccountEventRecorder appends account events in the same unit of work as
+// the change they describe, it is nil when event sourcing is turned off.
+type AccountEventRecorder interface {
+	Record(events []model.AccountEvent) error
+}
+
+// AccountEventUsecase keeps accounts as event streams and rebuilds their
+// state from them.
+type AccountEventUsecase interface {
+	AccountEventRecorder
+	FindEvents(accountID int64, afterVersion int64) ([]model.AccountEvent, error)
+	Load(accountID int64) (eventsource.Account, error)
+	LoadAsOf(accountID int64, asOf time.Time) (eventsource.Account, error)
+	Snapshot(accountID int64) (model.AccountSnapshot, error)
+	Project(accountID int64) (model.Account, error)
+	Import() (int, error)
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/eventsource"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type AccountEventUsecaseImpl struct {
+	EventRepo     repository.AccountEventRepo
+	SnapshotRepo  repository.AccountSnapshotRepo
+	AccRepo       repository.AccountRepo
+	HisRepo       repository.HistoryRepo
+	SnapshotEvery int64
+
+	// mu guards the event store and the snapshots, callers may already hold
+	// balanceMu so it is always taken after it
+	mu sync.Mutex
+}
+
+// Record implements AccountEventRecorder. Accounts whose stream passes a
+// multiple of SnapshotEvery get a new snapshot.
+func (u *AccountEventUsecaseImpl) Record(events []model.AccountEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	appended, err := u.EventRepo.Append(events)
+	if err != nil {
+		return err
+	}
+
+	if u.SnapshotEvery <= 0 {
+		return nil
+	}
+	for _, event := range appended {
+		if event.Version%u.SnapshotEvery != 0 {
+			continue
+		}
+		// Event sudah tersimpan, snapshot yang gagal hanya membuat rehydrate lebih lambat
+		if _, err := u.snapshot(event.AccountID, event.Version); err != nil {
+			log.Printf("snapshot account %d: %v", event.AccountID, err)
+		}
+	}
+	return nil
+}
+
+// FindEvents implements AccountEventUsecase
+func (u *AccountEventUsecaseImpl) FindEvents(accountID int64, afterVersion int64) ([]model.AccountEvent, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.EventRepo.FindByAccountId(accountID, afterVersion)
+}
+
+// Load implements AccountEventUsecase
+func (u *AccountEventUsecaseImpl) Load(accountID int64) (eventsource.Account, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.load(accountID, time.Time{})
+}
+
+// LoadAsOf implements AccountEventUsecase
+func (u *AccountEventUsecaseImpl) LoadAsOf(accountID int64, asOf time.Time) (eventsource.Account, error) {
+	if asOf.IsZero() {
+		return eventsource.Account{}, fmt.Errorf("as_of is required")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.load(accountID, asOf)
+}
+
+// Snapshot implements AccountEventUsecase
+func (u *AccountEventUsecaseImpl) Snapshot(accountID int64) (model.AccountSnapshot, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.snapshot(accountID, 0)
+}
+
+// Project implements AccountEventUsecase. The balance and status of the
+// stored account are replaced by the ones rebuilt from its stream.
+func (u *AccountEventUsecaseImpl) Project(accountID int64) (model.Account, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	rebuilt, err := u.load(accountID, time.Time{})
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	acc, err := u.AccRepo.FindById(accountID)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	acc.Balance = rebuilt.State.Balance
+	acc.Status = rebuilt.State.Status
+	acc.StatusReason = rebuilt.State.StatusReason
+	acc.ClosedAt = rebuilt.State.ClosedAt
+	return u.AccRepo.Update(acc)
+}
+
+// Import implements AccountEventUsecase. Accounts without a stream get one
+// built from their history, so event sourcing can be turned on for accounts
+// that already exist. It returns the number of streams started.
+func (u *AccountEventUsecaseImpl) Import() (int, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	accounts, err := u.AccRepo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	existing, err := u.EventRepo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+	streams := map[int64]bool{}
+	for _, event := range existing {
+		streams[event.AccountID] = true
+	}
+
+	historys, err := u.HisRepo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+	sort.SliceStable(historys, func(i, j int) bool { return historys[i].ID < historys[j].ID })
+
+	now := time.Now()
+	var events []model.AccountEvent
+	imported := 0
+	for _, acc := range accounts {
+		if streams[acc.ID] {
+			continue
+		}
+
+		events = append(events, eventsource.Opened(acc))
+		for _, history := range historys {
+			if history.AccountID != acc.ID {
+				continue
+			}
+			if event, ok := eventsource.FromHistory(history); ok {
+				events = append(events, event)
+			}
+		}
+
+		if acc.CurrentStatus() != model.AccountStatusActive {
+			at := now
+			if acc.CurrentStatus() == model.AccountStatusClosed && !acc.ClosedAt.IsZero() {
+				at = acc.ClosedAt
+			}
+			events = append(events, eventsource.StatusChanged(acc, at))
+		}
+		imported++
+	}
+
+	if _, err := u.EventRepo.Append(events); err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
+
+// load rehydrates an account from its latest snapshot taken as of asOf and
+// the events after it, a zero asOf loads the current state.
+func (u *AccountEventUsecaseImpl) load(accountID int64, asOf time.Time) (eventsource.Account, error) {
+	var snapshot *model.AccountSnapshot
+	if latest, err := u.SnapshotRepo.FindLatest(accountID, asOf); err == nil {
+		snapshot = &latest
+	}
+
+	var afterVersion int64
+	if snapshot != nil {
+		afterVersion = snapshot.Version
+	}
+	events, err := u.EventRepo.FindByAccountId(accountID, afterVersion)
+	if err != nil {
+		return eventsource.Account{}, err
+	}
+
+	if !asOf.IsZero() {
+		for i, event := range events {
+			if event.OccurredAt.After(asOf) {
+				events = events[:i]
+				break
+			}
+		}
+	}
+
+	account, err := eventsource.Rehydrate(snapshot, events)
+	if err != nil {
+		return eventsource.Account{}, err
+	}
+	if account.Version == 0 {
+		if asOf.IsZero() {
+			return eventsource.Account{}, fmt.Errorf("account %d has no events", accountID)
+		}
+		return eventsource.Account{}, fmt.Errorf("account %d was not open yet at %s", accountID, asOf.Format(time.RFC3339))
+	}
+	return account, nil
+}
+
+// snapshot saves the state of an account, at version when it is not 0.
+func (u *AccountEventUsecaseImpl) snapshot(accountID int64, version int64) (model.AccountSnapshot, error) {
+	account, err := u.load(accountID, time.Time{})
+	if err != nil {
+		return model.AccountSnapshot{}, err
+	}
+	if version != 0 && account.Version != version {
+		return model.AccountSnapshot{}, fmt.Errorf("account %d is at version %d, not %d", accountID, account.Version, version)
+	}
+	return u.SnapshotRepo.Save(account.Snapshot())
+}
+
+// recordEvents records events when recorder is set.
+func recordEvents(recorder AccountEventRecorder, events ...model.AccountEvent) error {
+	if recorder == nil {
+		return nil
+	}
+	return recorder.Record(events)
+}
+
+func NewAccountEventUsecaseImpl(EventRepo repository.AccountEventRepo, SnapshotRepo repository.AccountSnapshotRepo, AccRepo repository.AccountRepo, HisRepo repository.HistoryRepo, SnapshotEvery int64) AccountEventUsecase {
+	return &AccountEventUsecaseImpl{
+		EventRepo:     EventRepo,
+		SnapshotRepo:  SnapshotRepo,
+		AccRepo:       AccRepo,
+		HisRepo:       HisRepo,
+		SnapshotEvery: SnapshotEvery,
+	}
+}
@@ -2,20 +2,59 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/sferawann/test_mnc/eventsource"
 	"github.com/sferawann/test_mnc/model"
 	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/utils"
 )
 
-type AccountUsecaseImpl struct {
+// ErrDirectBalanceEdit is returned when an update tries to change the balance,
+// money only moves through deposits, withdrawals and transfers.
+var ErrDirectBalanceEdit = errors.New("balance cannot be changed directly, use deposit or withdraw")
+
+var cashDescriptions = map[string]string{
+	model.HistoryTypeDeposit:    "Deposit",
+	model.HistoryTypeWithdrawal: "Withdrawal",
+}
+
+// AccountUsecaseConfig holds the collaborators and settings of an account
+// usecase. Events, OutboxRepo and AccountEvents may be left nil.
+type AccountUsecaseConfig struct {
 	AccountRepo repository.AccountRepo
 	UserRepo    repository.UserRepo
+	HisRepo     repository.HistoryRepo
+	Audit       AuditUsecase
+
+	TransferUsecase     TransferUsecase
+	ProductUsecase      AccountProductUsecase
+	FrozenBlocksCredits bool
+	DormantAfter        time.Duration
+
+	BankCode   string
+	BranchCode string
+
+	Events        EventPublisher
+	OutboxRepo    repository.OutboxEventRepo
+	AccountEvents AccountEventRecorder
 }
 
+type AccountUsecaseImpl struct {
+	AccountUsecaseConfig
+}
+
+// accountNumberAttempts bounds the retries when a random account number is
+// already taken.
+const accountNumberAttempts = 10
+
 // Delete implements AccountUsecase
 func (u *AccountUsecaseImpl) Delete(id int64) (model.Account, error) {
-	return u.AccountRepo.Delete(id)
+	// Akun tidak dihapus dari repo supaya history dan transfer tetap bisa merujuk ke akun ini
+	return u.Close(id, 0, "deleted", 0)
 }
 
 // FindAll implements AccountUsecase
@@ -39,13 +78,73 @@ func (u *AccountUsecaseImpl) Save(newAccount model.Account) (model.Account, erro
 		return model.Account{}, errors.New("balance must be greater than 0")
 	}
 
+	if newAccount.Type == "" {
+		newAccount.Type = model.AccountTypeSavings
+	}
+	newAccount.Currency = strings.ToUpper(newAccount.CurrencyCode())
+	if err := u.ProductUsecase.CheckOpening(newAccount); err != nil {
+		return model.Account{}, err
+	}
+
 	user, err := u.UserRepo.FindById(newAccount.UserID)
 	if err != nil {
 		return model.Account{}, err
 	}
 
+	number, err := u.newAccountNumber()
+	if err != nil {
+		return model.Account{}, err
+	}
+
 	newAccount.User = user
-	return u.AccountRepo.Save(newAccount)
+	newAccount.Number = number
+	newAccount.Status = model.AccountStatusActive
+	newAccount.StatusReason = ""
+	newAccount.ClosedAt = time.Time{}
+	savedAccount, err := u.AccountRepo.Save(newAccount)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	// Saldo awal dicatat di history supaya saldo selalu bisa dihitung ulang dari history
+	openingHistory, err := u.HisRepo.Save(model.History{
+		AccountID:    savedAccount.ID,
+		Account:      savedAccount,
+		Type:         model.HistoryTypeDeposit,
+		Description:  "Opening balance",
+		Amount:       savedAccount.Balance,
+		BalanceAfter: savedAccount.Balance,
+	})
+	if err != nil {
+		u.AccountRepo.Delete(savedAccount.ID)
+		return model.Account{}, err
+	}
+
+	undoOutbox, err := saveOutbox(u.OutboxRepo, model.DomainEventAccountOpened, model.AggregateAccount, savedAccount.ID, model.AccountOpened{
+		AccountID: savedAccount.ID,
+		UserID:    savedAccount.UserID,
+		Number:    savedAccount.Number,
+		Type:      savedAccount.Type,
+		Currency:  savedAccount.Currency,
+		Balance:   savedAccount.Balance,
+		CreatedAt: savedAccount.CreatedAt,
+	})
+	if err != nil {
+		u.HisRepo.Delete(openingHistory.ID)
+		u.AccountRepo.Delete(savedAccount.ID)
+		return model.Account{}, err
+	}
+
+	opening, _ := eventsource.FromHistory(openingHistory)
+	if err := recordEvents(u.AccountEvents, eventsource.Opened(savedAccount), opening); err != nil {
+		undoOutbox()
+		u.HisRepo.Delete(openingHistory.ID)
+		u.AccountRepo.Delete(savedAccount.ID)
+		return model.Account{}, err
+	}
+	publishBalanceChanged(u.Events, openingHistory)
+
+	return savedAccount, nil
 }
 
 // Update implements AccountUsecase
@@ -59,6 +158,7 @@ func (u *AccountUsecaseImpl) Update(updatedAccount model.Account) (model.Account
 
 	// Mengambil nilai-nilai field dari entitas sebelumnya
 	previousUserID := previousAccount.UserID
+	previousType := previousAccount.Type
 	previousBalance := previousAccount.Balance
 	previousCreatedAt := previousAccount.CreatedAt
 
@@ -66,19 +166,192 @@ func (u *AccountUsecaseImpl) Update(updatedAccount model.Account) (model.Account
 	if updatedAccount.UserID == 0 {
 		updatedAccount.UserID = previousUserID
 	}
+	if updatedAccount.Type == "" {
+		updatedAccount.Type = previousType
+	}
 	if updatedAccount.Balance == 0 {
 		updatedAccount.Balance = previousBalance
+	} else if updatedAccount.Balance != previousBalance {
+		return model.Account{}, ErrDirectBalanceEdit
 	}
 	if updatedAccount.CreatedAt == (time.Time{}) {
 		updatedAccount.CreatedAt = previousCreatedAt
 	}
 
+	// Ganti tipe akun harus memenuhi aturan produk yang baru
+	if updatedAccount.Type != previousType {
+		if err := u.ProductUsecase.CheckOpening(model.Account{Type: updatedAccount.Type, Currency: previousAccount.Currency, Balance: updatedAccount.Balance}); err != nil {
+			return model.Account{}, err
+		}
+	}
+
+	// Saldo yang ditahan hanya boleh diubah melalui hold, status melalui freeze/unfreeze/close
+	updatedAccount.Number = previousAccount.Number
+	updatedAccount.Currency = previousAccount.Currency
+	updatedAccount.HeldBalance = previousAccount.HeldBalance
+	updatedAccount.Status = previousAccount.Status
+	updatedAccount.StatusReason = previousAccount.StatusReason
+	updatedAccount.ClosedAt = previousAccount.ClosedAt
+
 	return u.AccountRepo.Update(updatedAccount)
 }
 
-func NewAccountUsecaseImpl(AccountRepo repository.AccountRepo, UserRepo repository.UserRepo) AccountUsecase {
+// FindByNumber implements AccountUsecase
+func (u *AccountUsecaseImpl) FindByNumber(number string) (model.Account, error) {
+	if err := utils.ValidateAccountNumber(number); err != nil {
+		return model.Account{}, err
+	}
+	return u.AccountRepo.FindByNumber(number)
+}
+
+// AssignNumbers implements AccountUsecase
+func (u *AccountUsecaseImpl) AssignNumbers() (int, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	accounts, err := u.AccountRepo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	assigned := 0
+	for _, acc := range accounts {
+		if acc.Number != "" {
+			continue
+		}
+
+		acc.Number, err = u.newAccountNumber()
+		if err != nil {
+			return assigned, err
+		}
+		if _, err := u.AccountRepo.Update(acc); err != nil {
+			return assigned, err
+		}
+		assigned++
+	}
+
+	return assigned, nil
+}
+
+// newAccountNumber generates an account number that is not in use yet.
+func (u *AccountUsecaseImpl) newAccountNumber() (string, error) {
+	for i := 0; i < accountNumberAttempts; i++ {
+		number, err := utils.GenerateAccountNumber(u.BankCode, u.BranchCode)
+		if err != nil {
+			return "", err
+		}
+		if _, err := u.AccountRepo.FindByNumber(number); err != nil {
+			return number, nil
+		}
+	}
+	return "", errors.New("could not generate a unique account number")
+}
+
+// Deposit implements AccountUsecase
+func (u *AccountUsecaseImpl) Deposit(accountID int64, amount float64, channel string, reference string, actorID int64) (model.History, error) {
+	return u.post(accountID, amount, model.HistoryTypeDeposit, channel, reference, actorID)
+}
+
+// Withdraw implements AccountUsecase
+func (u *AccountUsecaseImpl) Withdraw(accountID int64, amount float64, channel string, reference string, actorID int64) (model.History, error) {
+	return u.post(accountID, -amount, model.HistoryTypeWithdrawal, channel, reference, actorID)
+}
+
+// post moves money into (delta > 0) or out of an account from outside the
+// ledger and writes the matching history row.
+func (u *AccountUsecaseImpl) post(accountID int64, delta float64, historyType string, channel string, reference string, actorID int64) (model.History, error) {
+	amount := math.Abs(delta)
+	if err := validateCashAmount(amount); err != nil {
+		return model.History{}, err
+	}
+	if err := validateChannel(channel); err != nil {
+		return model.History{}, err
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	acc, err := u.AccountRepo.FindById(accountID)
+	if err != nil {
+		return model.History{}, err
+	}
+
+	if delta < 0 {
+		if err := checkDebit(acc); err != nil {
+			return model.History{}, err
+		}
+		if err := u.ProductUsecase.CheckDebit(acc, amount); err != nil {
+			return model.History{}, err
+		}
+	} else if err := checkCredit(acc, u.FrozenBlocksCredits); err != nil {
+		return model.History{}, err
+	}
+
+	if reference != "" {
+		historys, err := u.HisRepo.FindByAccountId(acc.ID)
+		if err != nil {
+			return model.History{}, err
+		}
+		for _, history := range historys {
+			if history.Type == historyType && history.Channel == channel && history.Reference == reference {
+				return model.History{}, fmt.Errorf("%s with reference %s was already posted", historyType, reference)
+			}
+		}
+	}
+
+	tx := newLedgerTx(u.AccountRepo, u.HisRepo)
+	acc, err = tx.adjust(acc.ID, delta)
+	if err != nil {
+		tx.rollback()
+		return model.History{}, err
+	}
+
+	history, err := tx.saveHistory(model.History{
+		AccountID:    acc.ID,
+		Account:      acc,
+		Type:         historyType,
+		Description:  fmt.Sprintf("%s via %s", cashDescriptions[historyType], channel),
+		Channel:      channel,
+		Reference:    reference,
+		Amount:       delta,
+		BalanceAfter: acc.Balance,
+	})
+	if err != nil {
+		tx.rollback()
+		return model.History{}, err
+	}
+	if err := tx.record(u.AccountEvents); err != nil {
+		tx.rollback()
+		return model.History{}, err
+	}
+	tx.commit(u.Events)
+
+	recordAudit(u.Audit, "account."+historyType, actorID, "account", acc.ID,
+		fmt.Sprintf("history %d of %.2f via %s", history.ID, delta, channel))
+
+	return history, nil
+}
+
+func validateCashAmount(amount float64) error {
+	if amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if math.Abs(math.Round(amount*100)-amount*100) > 1e-6 {
+		return errors.New("amount must not have more than 2 decimals")
+	}
+	return nil
+}
+
+func validateChannel(channel string) error {
+	switch channel {
+	case model.ChannelTeller, model.ChannelATM, model.ChannelBankTransfer, model.ChannelMobile:
+		return nil
+	}
+	return fmt.Errorf("invalid channel: %s", channel)
+}
+
+func NewAccountUsecaseImpl(config AccountUsecaseConfig) AccountUsecase {
 	return &AccountUsecaseImpl{
-		AccountRepo: AccountRepo,
-		UserRepo:    UserRepo,
+		AccountUsecaseConfig: config,
 	}
 }
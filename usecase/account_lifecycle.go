@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/sferawann/test_mnc/eventsource"
+	"github.com/sferawann/test_mnc/model"
+)
+
+// Freeze implements AccountUsecase
+func (u *AccountUsecaseImpl) Freeze(id int64, reason string, actorID int64) (model.Account, error) {
+	return u.transition(id, model.AccountStatusFrozen, "account.frozen", reason, actorID,
+		model.AccountStatusActive, model.AccountStatusDormant)
+}
+
+// Unfreeze implements AccountUsecase, it also reactivates dormant accounts.
+func (u *AccountUsecaseImpl) Unfreeze(id int64, reason string, actorID int64) (model.Account, error) {
+	return u.transition(id, model.AccountStatusActive, "account.unfrozen", reason, actorID,
+		model.AccountStatusFrozen, model.AccountStatusDormant)
+}
+
+// Close implements AccountUsecase. The payout of the remaining balance and
+// the status change are one ledgerTx, so nothing can be credited in between.
+func (u *AccountUsecaseImpl) Close(id int64, payoutAccountID int64, reason string, actorID int64) (model.Account, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	acc, err := u.AccountRepo.FindById(id)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	if acc.CurrentStatus() == model.AccountStatusClosed {
+		return model.Account{}, fmt.Errorf("account %d is already closed", id)
+	}
+	if acc.HeldBalance > 0 {
+		return model.Account{}, errors.New("account has active holds, release or capture them first")
+	}
+
+	tx := newLedgerTx(u.AccountRepo, u.HisRepo)
+
+	// Sisa saldo dipindahkan dulu ke akun payout sebelum akun ditutup
+	if acc.Balance > 0 {
+		if payoutAccountID == 0 {
+			return model.Account{}, errors.New("account balance is not zero, payout_account_id is required")
+		}
+
+		_, err := u.TransferUsecase.executeIn(tx, model.Transfer{
+			FromAccountID: acc.ID,
+			ToAccountID:   payoutAccountID,
+			Amount:        acc.Balance,
+			InitiatedBy:   actorID,
+			Purpose:       model.TransferPurposeClosingPayout,
+		})
+		if err != nil {
+			tx.rollback()
+			return model.Account{}, fmt.Errorf("final payout: %w", err)
+		}
+
+		acc, err = u.AccountRepo.FindById(id)
+		if err != nil {
+			tx.rollback()
+			return model.Account{}, err
+		}
+	}
+	if math.Abs(acc.Balance) >= 0.005 {
+		tx.rollback()
+		return model.Account{}, fmt.Errorf("account balance must be zero to close, it is %.2f", acc.Balance)
+	}
+
+	previousAccount := acc
+	previous := acc.CurrentStatus()
+	acc.Status = model.AccountStatusClosed
+	acc.StatusReason = reason
+	acc.ClosedAt = time.Now()
+	tx.undo(func() { u.AccountRepo.Update(previousAccount) })
+	closedAccount, err := u.AccountRepo.Update(acc)
+	if err != nil {
+		tx.rollback()
+		return model.Account{}, err
+	}
+
+	if err := tx.record(u.AccountEvents, eventsource.StatusChanged(closedAccount, closedAccount.ClosedAt)); err != nil {
+		tx.rollback()
+		return model.Account{}, err
+	}
+	tx.commit(u.Events)
+
+	recordAudit(u.Audit, "account.closed", actorID, "account", acc.ID,
+		fmt.Sprintf("%s -> %s: %s", previous, model.AccountStatusClosed, reason))
+
+	return closedAccount, nil
+}
+
+// MarkDormant implements AccountUsecase
+func (u *AccountUsecaseImpl) MarkDormant(now time.Time) error {
+	if u.DormantAfter <= 0 {
+		return nil
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	accounts, err := u.AccountRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	historys, err := u.HisRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	lastActivity := map[int64]time.Time{}
+	for _, history := range historys {
+		if history.CreatedAt.After(lastActivity[history.AccountID]) {
+			lastActivity[history.AccountID] = history.CreatedAt
+		}
+	}
+
+	for _, acc := range accounts {
+		if acc.CurrentStatus() != model.AccountStatusActive {
+			continue
+		}
+
+		last := lastActivity[acc.ID]
+		if acc.CreatedAt.After(last) {
+			last = acc.CreatedAt
+		}
+		if now.Sub(last) < u.DormantAfter {
+			continue
+		}
+
+		previousAccount := acc
+		acc.Status = model.AccountStatusDormant
+		acc.StatusReason = fmt.Sprintf("no activity since %s", last.Format("2006-01-02"))
+		if _, err := u.AccountRepo.Update(acc); err != nil {
+			return err
+		}
+		if err := u.recordStatus(previousAccount, acc, now); err != nil {
+			return err
+		}
+
+		recordAudit(u.Audit, "account.dormant", 0, "account", acc.ID, acc.StatusReason)
+	}
+
+	return nil
+}
+
+// transition moves an account to status when its current status is one of
+// from.
+func (u *AccountUsecaseImpl) transition(id int64, status string, action string, reason string, actorID int64, from ...string) (model.Account, error) {
+	if reason == "" {
+		return model.Account{}, errors.New("reason is required")
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	acc, err := u.AccountRepo.FindById(id)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	previousAccount := acc
+	previous := acc.CurrentStatus()
+	allowed := false
+	for _, s := range from {
+		if previous == s {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return model.Account{}, fmt.Errorf("account %d is %s and cannot become %s", id, previous, status)
+	}
+
+	acc.Status = status
+	acc.StatusReason = reason
+	updatedAccount, err := u.AccountRepo.Update(acc)
+	if err != nil {
+		return model.Account{}, err
+	}
+	if err := u.recordStatus(previousAccount, updatedAccount, time.Now()); err != nil {
+		return model.Account{}, err
+	}
+
+	recordAudit(u.Audit, action, actorID, "account", acc.ID,
+		fmt.Sprintf("%s -> %s: %s", previous, status, reason))
+
+	return updatedAccount, nil
+}
+
+// recordStatus records the status change from previous to updated, the
+// account is restored when the event cannot be recorded.
+func (u *AccountUsecaseImpl) recordStatus(previous model.Account, updated model.Account, at time.Time) error {
+	if err := recordEvents(u.AccountEvents, eventsource.StatusChanged(updated, at)); err != nil {
+		if _, restoreErr := u.AccountRepo.Update(previous); restoreErr != nil {
+			log.Printf("restore account %d: %v", previous.ID, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type AccountProductUsecase interface {
+	FindAll() ([]model.AccountProduct, error)
+	FindByCode(code string) (model.AccountProduct, error)
+	CheckOpening(account model.Account) error
+	CheckDebit(account model.Account, amount float64) error
+	CheckClosing(account model.Account) error
+}
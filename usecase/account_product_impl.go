@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	ProductErrLockedIn        = "PRODUCT_LOCKED_IN"
+	ProductErrMinimumBalance  = "PRODUCT_MINIMUM_BALANCE"
+	ProductErrWithdrawalCount = "PRODUCT_WITHDRAWAL_COUNT_EXCEEDED"
+)
+
+// ProductRuleError is returned when a debit breaks a rule of the account's
+// product.
+type ProductRuleError struct {
+	Code    string
+	Message string
+}
+
+func (e *ProductRuleError) Error() string {
+	return e.Message
+}
+
+type AccountProductUsecaseImpl struct {
+	ProductRepo repository.AccountProductRepo
+	HisRepo     repository.HistoryRepo
+}
+
+// FindAll implements AccountProductUsecase
+func (u *AccountProductUsecaseImpl) FindAll() ([]model.AccountProduct, error) {
+	products, err := u.ProductRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var active []model.AccountProduct
+	for _, product := range products {
+		if product.Active {
+			active = append(active, product)
+		}
+	}
+
+	return active, nil
+}
+
+// FindByCode implements AccountProductUsecase
+func (u *AccountProductUsecaseImpl) FindByCode(code string) (model.AccountProduct, error) {
+	product, err := u.ProductRepo.FindByCode(code)
+	if err != nil {
+		return model.AccountProduct{}, fmt.Errorf("invalid account type: %s", code)
+	}
+	return product, nil
+}
+
+// CheckOpening implements AccountProductUsecase
+func (u *AccountProductUsecaseImpl) CheckOpening(account model.Account) error {
+	product, err := u.FindByCode(account.Type)
+	if err != nil {
+		return err
+	}
+
+	if !product.Active {
+		return fmt.Errorf("account type %s is no longer offered", product.Code)
+	}
+	if !productOffersCurrency(product, account.CurrencyCode()) {
+		return fmt.Errorf("%s is not offered in %s", product.Name, account.CurrencyCode())
+	}
+	if account.Balance < product.MinimumBalance {
+		return &ProductRuleError{
+			Code:    ProductErrMinimumBalance,
+			Message: fmt.Sprintf("%s requires an opening balance of at least %.2f", product.Name, product.MinimumBalance),
+		}
+	}
+
+	return nil
+}
+
+// CheckDebit implements AccountProductUsecase
+func (u *AccountProductUsecaseImpl) CheckDebit(account model.Account, amount float64) error {
+	product := u.productOf(account)
+	if err := checkLockIn(account, product); err != nil {
+		return err
+	}
+
+	remaining := account.AvailableBalance() - amount
+	if remaining < -product.OverdraftLimit {
+		return ErrInsufficientBalance
+	}
+	if product.OverdraftLimit == 0 && remaining < product.MinimumBalance {
+		return &ProductRuleError{
+			Code:    ProductErrMinimumBalance,
+			Message: fmt.Sprintf("%s must keep a minimum balance of %.2f", product.Name, product.MinimumBalance),
+		}
+	}
+
+	if product.MonthlyWithdrawalLimit > 0 {
+		count, err := u.debitsThisMonth(account.ID)
+		if err != nil {
+			return err
+		}
+		if count >= product.MonthlyWithdrawalLimit {
+			return &ProductRuleError{
+				Code:    ProductErrWithdrawalCount,
+				Message: fmt.Sprintf("%s allows %d withdrawals per month", product.Name, product.MonthlyWithdrawalLimit),
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckClosing implements AccountProductUsecase, a closing payout may empty
+// the account but not before the lock-in period ends.
+func (u *AccountProductUsecaseImpl) CheckClosing(account model.Account) error {
+	return checkLockIn(account, u.productOf(account))
+}
+
+func (u *AccountProductUsecaseImpl) productOf(account model.Account) model.AccountProduct {
+	accountType := account.Type
+	if accountType == "" {
+		accountType = model.AccountTypeSavings
+	}
+
+	product, err := u.ProductRepo.FindByCode(accountType)
+	if err != nil {
+		// Tanpa produk hanya saldo yang diperiksa
+		return model.AccountProduct{Code: accountType, Name: accountType}
+	}
+	return product
+}
+
+func productOffersCurrency(product model.AccountProduct, currency string) bool {
+	if len(product.Currencies) == 0 {
+		return currency == model.DefaultCurrency
+	}
+	for _, offered := range product.Currencies {
+		if offered == currency {
+			return true
+		}
+	}
+	return false
+}
+
+func checkLockIn(account model.Account, product model.AccountProduct) error {
+	if product.LockInDays <= 0 {
+		return nil
+	}
+
+	unlockAt := account.CreatedAt.AddDate(0, 0, product.LockInDays)
+	if time.Now().Before(unlockAt) {
+		return &ProductRuleError{
+			Code:    ProductErrLockedIn,
+			Message: fmt.Sprintf("%s is locked until %s", product.Name, unlockAt.Format("2006-01-02")),
+		}
+	}
+	return nil
+}
+
+// debitsThisMonth counts withdrawals and outgoing transfers since the start
+// of the month.
+func (u *AccountProductUsecaseImpl) debitsThisMonth(accountID int64) (int, error) {
+	historys, err := u.HisRepo.FindByAccountId(accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	count := 0
+	for _, history := range historys {
+		if history.CreatedAt.Before(startOfMonth) {
+			continue
+		}
+		if history.Type == model.HistoryTypeWithdrawal || history.Type == model.HistoryTypeTransferOut {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func NewAccountProductUsecaseImpl(ProductRepo repository.AccountProductRepo, HisRepo repository.HistoryRepo) AccountProductUsecase {
+	return &AccountProductUsecaseImpl{
+		ProductRepo: ProductRepo,
+		HisRepo:     HisRepo,
+	}
+}
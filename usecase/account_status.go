@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// AccountStatusError is returned when an account's status does not allow the
+// requested money movement.
+type AccountStatusError struct {
+	AccountID int64
+	Status    string
+}
+
+func (e *AccountStatusError) Error() string {
+	return fmt.Sprintf("account %d is %s", e.AccountID, e.Status)
+}
+
+// checkDebit allows money to leave active accounts only.
+func checkDebit(acc model.Account) error {
+	if acc.CurrentStatus() != model.AccountStatusActive {
+		return &AccountStatusError{AccountID: acc.ID, Status: acc.CurrentStatus()}
+	}
+	return nil
+}
+
+// checkCredit rejects money into closed accounts, and into frozen accounts
+// when blockFrozen is set.
+func checkCredit(acc model.Account, blockFrozen bool) error {
+	switch acc.CurrentStatus() {
+	case model.AccountStatusClosed:
+		return &AccountStatusError{AccountID: acc.ID, Status: model.AccountStatusClosed}
+	case model.AccountStatusFrozen:
+		if blockFrozen {
+			return &AccountStatusError{AccountID: acc.ID, Status: model.AccountStatusFrozen}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type AuditUsecase interface {
+	Record(action string, actorID int64, entity string, entityID int64, detail string) error
+	FindByEntity(entity string, entityID int64) ([]model.AuditLog, error)
+	FindAll() ([]model.AuditLog, error)
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"log"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type AuditUsecaseImpl struct {
+	AuditLogRepo repository.AuditLogRepo
+}
+
+// FindAll implements AuditUsecase
+func (u *AuditUsecaseImpl) FindAll() ([]model.AuditLog, error) {
+	return u.AuditLogRepo.FindAll()
+}
+
+// FindByEntity implements AuditUsecase
+func (u *AuditUsecaseImpl) FindByEntity(entity string, entityID int64) ([]model.AuditLog, error) {
+	return u.AuditLogRepo.FindByEntity(entity, entityID)
+}
+
+// Record implements AuditUsecase
+func (u *AuditUsecaseImpl) Record(action string, actorID int64, entity string, entityID int64, detail string) error {
+	_, err := u.AuditLogRepo.Save(model.AuditLog{
+		Action:   action,
+		ActorID:  actorID,
+		Entity:   entity,
+		EntityID: entityID,
+		Detail:   detail,
+	})
+	return err
+}
+
+// recordAudit is used where the audited change has already been written and
+// a failing audit write can only be reported.
+func recordAudit(audit AuditUsecase, action string, actorID int64, entity string, entityID int64, detail string) {
+	if err := audit.Record(action, actorID, entity, entityID, detail); err != nil {
+		log.Printf("audit %s %s %d: %v", action, entity, entityID, err)
+	}
+}
+
+func NewAuditUsecaseImpl(AuditLogRepo repository.AuditLogRepo) AuditUsecase {
+	return &AuditUsecaseImpl{
+		AuditLogRepo: AuditLogRepo,
+	}
+}
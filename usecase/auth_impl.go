@@ -9,8 +9,9 @@ import (
 )
 
 type AuthUsecaseImpl struct {
-	userRepo repository.UserRepo
-	sesRepo  repository.SessionRepo
+	userRepo   repository.UserRepo
+	sesRepo    repository.SessionRepo
+	outboxRepo repository.OutboxEventRepo
 }
 
 // Login implements AuthUsecase
@@ -44,11 +45,21 @@ func (u *AuthUsecaseImpl) Login(username, password string) (string, error) {
 		User:   userid,
 		Token:  tokenStr,
 	}
-	_, err = u.sesRepo.Save(session)
+	savedSession, err := u.sesRepo.Save(session)
 	if err != nil {
 		return "", err
 	}
 
+	_, err = saveOutbox(u.outboxRepo, model.DomainEventSessionStarted, model.AggregateSession, savedSession.ID, model.SessionStarted{
+		SessionID: savedSession.ID,
+		UserID:    savedSession.UserID,
+		CreatedAt: savedSession.CreatedAt,
+	})
+	if err != nil {
+		u.sesRepo.Delete(savedSession.ID)
+		return "", err
+	}
+
 	return tokenStr, nil
 }
 
@@ -57,9 +68,10 @@ func (u *AuthUsecaseImpl) Logout(token string) (model.Session, error) {
 	return u.sesRepo.DeleteByToken(token)
 }
 
-func NewAuthUsecaseImpl(userRepo repository.UserRepo, sesRepo repository.SessionRepo) AuthUsecase {
+func NewAuthUsecaseImpl(userRepo repository.UserRepo, sesRepo repository.SessionRepo, outboxRepo repository.OutboxEventRepo) AuthUsecase {
 	return &AuthUsecaseImpl{
-		userRepo: userRepo,
-		sesRepo:  sesRepo,
+		userRepo:   userRepo,
+		sesRepo:    sesRepo,
+		outboxRepo: outboxRepo,
 	}
 }
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type BalanceUsecase interface {
+	BalanceAt(accountID int64, at time.Time) (model.HistoricalBalance, error)
+	EndOfDay(day time.Time) ([]model.HistoricalBalance, error)
+	SnapshotDaily(now time.Time) error
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+// BalanceUsecaseImpl computes past balances from the history. A balance at t
+// is the sum of the rows written before t, starting from the latest daily
+// balance that ended by t so only the rows after it are summed.
+type BalanceUsecaseImpl struct {
+	AccRepo          repository.AccountRepo
+	HisRepo          repository.HistoryRepo
+	DailyBalanceRepo repository.DailyBalanceRepo
+}
+
+// BalanceAt implements BalanceUsecase
+func (u *BalanceUsecaseImpl) BalanceAt(accountID int64, at time.Time) (model.HistoricalBalance, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	acc, err := u.AccRepo.FindById(accountID)
+	if err != nil {
+		return model.HistoricalBalance{}, err
+	}
+	if !acc.CreatedAt.IsZero() && at.Before(acc.CreatedAt) {
+		return model.HistoricalBalance{}, fmt.Errorf("account %d was opened after %s", acc.ID, at.Format(time.RFC3339))
+	}
+
+	balances, err := u.balancesAt([]model.Account{acc}, at)
+	if err != nil {
+		return model.HistoricalBalance{}, err
+	}
+	return balances[0], nil
+}
+
+// EndOfDay implements BalanceUsecase, it returns the closing balance of day for
+// every account that was open by then.
+func (u *BalanceUsecaseImpl) EndOfDay(day time.Time) ([]model.HistoricalBalance, error) {
+	day = startOfDay(day)
+	end := day.AddDate(0, 0, 1)
+	if end.After(time.Now()) {
+		return nil, errors.New("end of day balances are only available for days that have ended")
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	accounts, err := u.AccRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var open []model.Account
+	for _, acc := range accounts {
+		if acc.CreatedAt.Before(end) {
+			open = append(open, acc)
+		}
+	}
+	return u.balancesAt(open, end)
+}
+
+// SnapshotDaily implements BalanceUsecase. It stores the closing balances of
+// every day that ended since the latest snapshot, the first run only stores
+// yesterday.
+func (u *BalanceUsecaseImpl) SnapshotDaily(now time.Time) error {
+	today := startOfDay(now)
+	day := today.AddDate(0, 0, -1)
+
+	balances, err := u.DailyBalanceRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	var latest string
+	for _, balance := range balances {
+		if balance.Date > latest {
+			latest = balance.Date
+		}
+	}
+	if latest != "" {
+		latestDay, err := time.ParseInLocation(model.DailyBalanceDateLayout, latest, time.Local)
+		if err != nil {
+			return err
+		}
+		day = latestDay.AddDate(0, 0, 1)
+	}
+
+	for ; day.Before(today); day = day.AddDate(0, 0, 1) {
+		closing, err := u.EndOfDay(day)
+		if err != nil {
+			return err
+		}
+
+		date := day.Format(model.DailyBalanceDateLayout)
+		snapshots := make([]model.DailyBalance, 0, len(closing))
+		for _, balance := range closing {
+			snapshots = append(snapshots, model.DailyBalance{
+				AccountID: balance.AccountID,
+				Date:      date,
+				Balance:   balance.Balance,
+				Currency:  balance.Currency,
+			})
+		}
+		if _, err := u.DailyBalanceRepo.SaveAll(snapshots); err != nil {
+			return err
+		}
+		log.Printf("balances: stored closing balances of %s for %d accounts", date, len(snapshots))
+	}
+	return nil
+}
+
+// balancesAt returns the balances of accounts at at. The caller holds
+// balanceMu.
+func (u *BalanceUsecaseImpl) balancesAt(accounts []model.Account, at time.Time) ([]model.HistoricalBalance, error) {
+	wanted := map[int64]bool{}
+	for _, acc := range accounts {
+		wanted[acc.ID] = true
+	}
+
+	dailyBalances, err := u.DailyBalanceRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	snapshots := map[int64]model.DailyBalance{}
+	snapshotEnds := map[int64]time.Time{}
+	for _, balance := range dailyBalances {
+		if !wanted[balance.AccountID] {
+			continue
+		}
+		day, err := time.ParseInLocation(model.DailyBalanceDateLayout, balance.Date, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		end := day.AddDate(0, 0, 1)
+		if end.After(at) || !end.After(snapshotEnds[balance.AccountID]) {
+			continue
+		}
+		snapshots[balance.AccountID] = balance
+		snapshotEnds[balance.AccountID] = end
+	}
+
+	historys, err := u.HisRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	sums := map[int64]float64{}
+	counts := map[int64]int{}
+	for _, history := range historys {
+		if !wanted[history.AccountID] || !history.CreatedAt.Before(at) {
+			continue
+		}
+		// baris sebelum snapshot sudah termasuk di saldo harian
+		if history.CreatedAt.Before(snapshotEnds[history.AccountID]) {
+			continue
+		}
+		sums[history.AccountID] += history.Amount
+		counts[history.AccountID]++
+	}
+
+	balances := make([]model.HistoricalBalance, 0, len(accounts))
+	for _, acc := range accounts {
+		snapshot := snapshots[acc.ID]
+		balances = append(balances, model.HistoricalBalance{
+			AccountID:    acc.ID,
+			At:           at,
+			Balance:      math.Round((snapshot.Balance+sums[acc.ID])*100) / 100,
+			Currency:     acc.CurrencyCode(),
+			SnapshotDate: snapshot.Date,
+			Transactions: counts[acc.ID],
+		})
+	}
+	return balances, nil
+}
+
+func NewBalanceUsecaseImpl(AccountRepo repository.AccountRepo, HisRepo repository.HistoryRepo, DailyBalanceRepo repository.DailyBalanceRepo) BalanceUsecase {
+	return &BalanceUsecaseImpl{
+		AccRepo:          AccountRepo,
+		HisRepo:          HisRepo,
+		DailyBalanceRepo: DailyBalanceRepo,
+	}
+}
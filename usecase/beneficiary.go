@@ -0,0 +1,13 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type BeneficiaryUsecase interface {
+	Save(newBeneficiary model.Beneficiary) (model.Beneficiary, error)
+	Update(updatedBeneficiary model.Beneficiary) (model.Beneficiary, error)
+	Delete(id int64) (model.Beneficiary, error)
+	FindById(id int64) (model.Beneficiary, error)
+	FindByUserId(userID int64) ([]model.Beneficiary, error)
+	CheckTransfer(beneficiary model.Beneficiary, amount float64) error
+	MarkUsed(id int64) (model.Beneficiary, error)
+}
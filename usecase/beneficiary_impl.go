@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/utils"
+)
+
+// CoolingOffError is returned for a large transfer to a beneficiary that was
+// added too recently.
+type CoolingOffError struct {
+	BeneficiaryID int64
+	Until         time.Time
+}
+
+func (e *CoolingOffError) Error() string {
+	return fmt.Sprintf("beneficiary %d was added recently, large transfers are allowed from %s",
+		e.BeneficiaryID, e.Until.Format("2006-01-02 15:04"))
+}
+
+type BeneficiaryUsecaseImpl struct {
+	BeneficiaryRepo repository.BeneficiaryRepo
+	AccRepo         repository.AccountRepo
+
+	// Transfers above CoolingOffAmount to a beneficiary added less than
+	// CoolingOff ago are rejected, a zero CoolingOff disables the check
+	CoolingOff       time.Duration
+	CoolingOffAmount float64
+}
+
+// Save implements BeneficiaryUsecase
+func (u *BeneficiaryUsecaseImpl) Save(newBeneficiary model.Beneficiary) (model.Beneficiary, error) {
+	newBeneficiary.Nickname = strings.TrimSpace(newBeneficiary.Nickname)
+	if newBeneficiary.Nickname == "" {
+		return model.Beneficiary{}, errors.New("nickname is required")
+	}
+
+	acc, err := u.findAccount(newBeneficiary)
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+	if acc.UserID == newBeneficiary.UserID {
+		return model.Beneficiary{}, errors.New("own accounts cannot be saved as a beneficiary")
+	}
+	if acc.CurrentStatus() == model.AccountStatusClosed {
+		return model.Beneficiary{}, &AccountStatusError{AccountID: acc.ID, Status: model.AccountStatusClosed}
+	}
+
+	beneficiaries, err := u.BeneficiaryRepo.FindByUserId(newBeneficiary.UserID)
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+	for _, beneficiary := range beneficiaries {
+		if beneficiary.AccountID == acc.ID {
+			return model.Beneficiary{}, fmt.Errorf("account %s is already saved as %s", acc.Number, beneficiary.Nickname)
+		}
+	}
+
+	newBeneficiary.AccountID = acc.ID
+	newBeneficiary.AccountNumber = acc.Number
+	newBeneficiary.Currency = acc.CurrencyCode()
+	newBeneficiary.Verified = false
+	newBeneficiary.VerifiedAt = time.Time{}
+	newBeneficiary.LastUsedAt = time.Time{}
+	return u.BeneficiaryRepo.Save(newBeneficiary)
+}
+
+// findAccount resolves the account of a beneficiary by number or by ID.
+func (u *BeneficiaryUsecaseImpl) findAccount(beneficiary model.Beneficiary) (model.Account, error) {
+	if beneficiary.AccountNumber != "" {
+		if err := utils.ValidateAccountNumber(beneficiary.AccountNumber); err != nil {
+			return model.Account{}, err
+		}
+		return u.AccRepo.FindByNumber(beneficiary.AccountNumber)
+	}
+	if beneficiary.AccountID == 0 {
+		return model.Account{}, errors.New("account_number or id_account is required")
+	}
+	return u.AccRepo.FindById(beneficiary.AccountID)
+}
+
+// Update implements BeneficiaryUsecase, only the nickname can be changed.
+func (u *BeneficiaryUsecaseImpl) Update(updatedBeneficiary model.Beneficiary) (model.Beneficiary, error) {
+	beneficiary, err := u.BeneficiaryRepo.FindById(updatedBeneficiary.ID)
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	nickname := strings.TrimSpace(updatedBeneficiary.Nickname)
+	if nickname == "" {
+		return model.Beneficiary{}, errors.New("nickname is required")
+	}
+
+	beneficiary.Nickname = nickname
+	return u.BeneficiaryRepo.Update(beneficiary)
+}
+
+// Delete implements BeneficiaryUsecase
+func (u *BeneficiaryUsecaseImpl) Delete(id int64) (model.Beneficiary, error) {
+	return u.BeneficiaryRepo.Delete(id)
+}
+
+// FindById implements BeneficiaryUsecase
+func (u *BeneficiaryUsecaseImpl) FindById(id int64) (model.Beneficiary, error) {
+	return u.BeneficiaryRepo.FindById(id)
+}
+
+// FindByUserId implements BeneficiaryUsecase
+func (u *BeneficiaryUsecaseImpl) FindByUserId(userID int64) ([]model.Beneficiary, error) {
+	return u.BeneficiaryRepo.FindByUserId(userID)
+}
+
+// CheckTransfer implements BeneficiaryUsecase
+func (u *BeneficiaryUsecaseImpl) CheckTransfer(beneficiary model.Beneficiary, amount float64) error {
+	if u.CoolingOff <= 0 || amount <= u.CoolingOffAmount {
+		return nil
+	}
+
+	until := beneficiary.CreatedAt.Add(u.CoolingOff)
+	if time.Now().Before(until) {
+		return &CoolingOffError{BeneficiaryID: beneficiary.ID, Until: until}
+	}
+	return nil
+}
+
+// MarkUsed implements BeneficiaryUsecase, it is called after a transfer to
+// the beneficiary has completed.
+func (u *BeneficiaryUsecaseImpl) MarkUsed(id int64) (model.Beneficiary, error) {
+	beneficiary, err := u.BeneficiaryRepo.FindById(id)
+	if err != nil {
+		return model.Beneficiary{}, err
+	}
+
+	now := time.Now()
+	if !beneficiary.Verified {
+		beneficiary.Verified = true
+		beneficiary.VerifiedAt = now
+	}
+	beneficiary.LastUsedAt = now
+	return u.BeneficiaryRepo.Update(beneficiary)
+}
+
+func NewBeneficiaryUsecaseImpl(BeneficiaryRepo repository.BeneficiaryRepo, AccRepo repository.AccountRepo, CoolingOff time.Duration, CoolingOffAmount float64) BeneficiaryUsecase {
+	return &BeneficiaryUsecaseImpl{
+		BeneficiaryRepo:  BeneficiaryRepo,
+		AccRepo:          AccRepo,
+		CoolingOff:       CoolingOff,
+		CoolingOffAmount: CoolingOffAmount,
+	}
+}
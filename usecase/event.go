@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// EventPublisher is told about events once the change they describe has been
+// written. Publishing must not block on the subscribers.
+type EventPublisher interface {
+	Publish(event model.Event) error
+}
+
+// publishEvent publishes an event when events is set, a failure does not undo
+// the change that was already committed so it is only logged.
+func publishEvent(events EventPublisher, eventType string, accountIDs []int64, data interface{}) {
+	if events == nil {
+		return
+	}
+
+	event := model.Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		AccountIDs: accountIDs,
+		Data:       data,
+		OccurredAt: time.Now(),
+	}
+	if err := events.Publish(event); err != nil {
+		log.Printf("publish %s: %v", eventType, err)
+	}
+}
+
+// publishBalanceChanged publishes an account.balance_changed event for a
+// written history row.
+func publishBalanceChanged(events EventPublisher, history model.History) {
+	publishEvent(events, model.EventAccountBalanceChanged, []int64{history.AccountID}, model.BalanceChange{
+		AccountID:    history.AccountID,
+		HistoryID:    history.ID,
+		Type:         history.Type,
+		TransferID:   history.TransferID,
+		Amount:       history.Amount,
+		BalanceAfter: history.BalanceAfter,
+		Currency:     history.Account.CurrencyCode(),
+	})
+}
+
+// publishTransfer publishes a transfer event without the embedded accounts.
+func publishTransfer(events EventPublisher, eventType string, transfer model.Transfer) {
+	transfer.FromAccount = model.Account{}
+	transfer.ToAccount = model.Account{}
+	publishEvent(events, eventType, []int64{transfer.FromAccountID, transfer.ToAccountID}, transfer)
+}
+
+func newEventID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "evt_" + time.Now().Format("20060102150405.000000000")
+	}
+	return "evt_" + hex.EncodeToString(b)
+}
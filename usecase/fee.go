@@ -0,0 +1,8 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type FeeUsecase interface {
+	FindAll() ([]model.FeeRule, error)
+	Quote(fromAccount model.Account, toAccount model.Account, amount float64) (model.FeeQuote, error)
+}
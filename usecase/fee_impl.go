@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"math"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type FeeUsecaseImpl struct {
+	FeeRuleRepo repository.FeeRuleRepo
+}
+
+// FindAll implements FeeUsecase
+func (u *FeeUsecaseImpl) FindAll() ([]model.FeeRule, error) {
+	return u.FeeRuleRepo.FindAll()
+}
+
+// Quote implements FeeUsecase
+func (u *FeeUsecaseImpl) Quote(fromAccount model.Account, toAccount model.Account, amount float64) (model.FeeQuote, error) {
+	quote := model.FeeQuote{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        amount,
+		Total:         amount,
+	}
+
+	// Aturan biaya dalam mata uang default, akun valas tidak dikenakan biaya
+	if fromAccount.CurrencyCode() != model.DefaultCurrency {
+		return quote, nil
+	}
+
+	rules, err := u.FeeRuleRepo.FindAll()
+	if err != nil {
+		return model.FeeQuote{}, err
+	}
+
+	accountType := fromAccount.Type
+	if accountType == "" {
+		accountType = model.AccountTypeSavings
+	}
+
+	for _, rule := range rules {
+		if rule.AccountType != "" && rule.AccountType != accountType {
+			continue
+		}
+		if amount < rule.MinAmount || (rule.MaxAmount > 0 && amount > rule.MaxAmount) {
+			continue
+		}
+
+		quote.FeeRuleID = rule.ID
+		quote.FeeRuleName = rule.Name
+		if !(rule.FreeForSameOwner && fromAccount.UserID == toAccount.UserID) {
+			quote.Fee = calculateFee(rule, amount)
+		}
+		break
+	}
+
+	quote.Total = amount + quote.Fee
+	return quote, nil
+}
+
+func calculateFee(rule model.FeeRule, amount float64) float64 {
+	var fee float64
+	switch rule.Type {
+	case model.FeeTypeFlat:
+		fee = rule.FlatFee
+	case model.FeeTypePercentage:
+		fee = amount * rule.Percentage / 100
+		if rule.MinFee > 0 && fee < rule.MinFee {
+			fee = rule.MinFee
+		}
+		if rule.MaxFee > 0 && fee > rule.MaxFee {
+			fee = rule.MaxFee
+		}
+	case model.FeeTypeTiered:
+		for _, tier := range rule.Tiers {
+			if tier.UpTo == 0 || amount <= tier.UpTo {
+				fee = tier.Fee
+				break
+			}
+		}
+	}
+
+	return math.Round(fee*100) / 100
+}
+
+func NewFeeUsecaseImpl(FeeRuleRepo repository.FeeRuleRepo) FeeUsecase {
+	return &FeeUsecaseImpl{
+		FeeRuleRepo: FeeRuleRepo,
+	}
+}
@@ -0,0 +1,10 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type FxQuoteUsecase interface {
+	Rate(from string, to string) (float64, error)
+	Currencies() ([]string, error)
+	Quote(userID int64, fromAccountID int64, toCurrency string, amount float64) (model.FxQuote, error)
+	FindById(id int64) (model.FxQuote, error)
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sferawann/test_mnc/fx"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type FxQuoteUsecaseImpl struct {
+	QuoteRepo repository.FxQuoteRepo
+	AccRepo   repository.AccountRepo
+	Provider  fx.RateProvider
+
+	// Spread is the percentage taken off the mid rate for the customer
+	Spread   float64
+	QuoteTTL time.Duration
+}
+
+// Rate implements FxQuoteUsecase
+func (u *FxQuoteUsecaseImpl) Rate(from string, to string) (float64, error) {
+	return u.Provider.Rate(strings.ToUpper(from), strings.ToUpper(to))
+}
+
+// Currencies implements FxQuoteUsecase
+func (u *FxQuoteUsecaseImpl) Currencies() ([]string, error) {
+	return u.Provider.Currencies()
+}
+
+// Quote implements FxQuoteUsecase, the rate stays locked until the quote
+// expires or a transfer uses it.
+func (u *FxQuoteUsecaseImpl) Quote(userID int64, fromAccountID int64, toCurrency string, amount float64) (model.FxQuote, error) {
+	if err := validateCashAmount(amount); err != nil {
+		return model.FxQuote{}, err
+	}
+
+	fromacc, err := u.AccRepo.FindById(fromAccountID)
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+	if fromacc.UserID != userID {
+		return model.FxQuote{}, errors.New("account does not belong to the current user")
+	}
+
+	fromCurrency := fromacc.CurrencyCode()
+	toCurrency = strings.ToUpper(toCurrency)
+	if fromCurrency == toCurrency {
+		return model.FxQuote{}, fmt.Errorf("account %d is already in %s", fromacc.ID, toCurrency)
+	}
+
+	midRate, err := u.Provider.Rate(fromCurrency, toCurrency)
+	if err != nil {
+		return model.FxQuote{}, err
+	}
+	rate := midRate * (1 - u.Spread/100)
+
+	return u.QuoteRepo.Save(model.FxQuote{
+		UserID:        userID,
+		FromAccountID: fromacc.ID,
+		FromCurrency:  fromCurrency,
+		ToCurrency:    toCurrency,
+		SourceAmount:  amount,
+		TargetAmount:  math.Round(amount*rate*100) / 100,
+		MidRate:       midRate,
+		Rate:          rate,
+		ExpiresAt:     time.Now().Add(u.QuoteTTL),
+	})
+}
+
+// FindById implements FxQuoteUsecase
+func (u *FxQuoteUsecaseImpl) FindById(id int64) (model.FxQuote, error) {
+	return u.QuoteRepo.FindById(id)
+}
+
+func NewFxQuoteUsecaseImpl(QuoteRepo repository.FxQuoteRepo, AccRepo repository.AccountRepo, Provider fx.RateProvider, Spread float64, QuoteTTL time.Duration) FxQuoteUsecase {
+	return &FxQuoteUsecaseImpl{
+		QuoteRepo: QuoteRepo,
+		AccRepo:   AccRepo,
+		Provider:  Provider,
+		Spread:    Spread,
+		QuoteTTL:  QuoteTTL,
+	}
+}
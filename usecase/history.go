@@ -7,5 +7,6 @@ type HistoryUsecase interface {
 	Update(updatedHistory model.History) (model.History, error)
 	Delete(id int64) (model.History, error)
 	FindById(id int64) (model.History, error)
+	FindByTransferId(transferID int64) ([]model.History, error)
 	FindAll() ([]model.History, error)
 }
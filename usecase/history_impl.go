@@ -28,6 +28,11 @@ func (u *HistoryUsecaseImpl) FindById(id int64) (model.History, error) {
 	return u.HistoryRepo.FindById(id)
 }
 
+// FindByTransferId implements HistoryUsecase
+func (u *HistoryUsecaseImpl) FindByTransferId(transferID int64) ([]model.History, error) {
+	return u.HistoryRepo.FindByTransferId(transferID)
+}
+
 // Save implements HistoryUsecase
 func (u *HistoryUsecaseImpl) Save(newHistory model.History) (model.History, error) {
 
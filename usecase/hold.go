@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type HoldUsecase interface {
+	Place(newHold model.Hold) (model.Hold, error)
+	Capture(id int64, amount float64, toAccountID int64) (model.Hold, error)
+	Release(id int64) (model.Hold, error)
+	FindById(id int64) (model.Hold, error)
+	FindByAccountId(accountID int64) ([]model.Hold, error)
+	ExpireDue(now time.Time) error
+}
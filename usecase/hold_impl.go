@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type HoldUsecaseImpl struct {
+	HoldRepo        repository.HoldRepo
+	AccRepo         repository.AccountRepo
+	TransferUsecase TransferUsecase
+	DefaultTTL      time.Duration
+}
+
+// FindById implements HoldUsecase
+func (u *HoldUsecaseImpl) FindById(id int64) (model.Hold, error) {
+	return u.HoldRepo.FindById(id)
+}
+
+// FindByAccountId implements HoldUsecase
+func (u *HoldUsecaseImpl) FindByAccountId(accountID int64) ([]model.Hold, error) {
+	return u.HoldRepo.FindByAccountId(accountID)
+}
+
+// Place implements HoldUsecase
+func (u *HoldUsecaseImpl) Place(newHold model.Hold) (model.Hold, error) {
+	if newHold.Amount <= 0 {
+		return model.Hold{}, errors.New("amount must be greater than 0")
+	}
+	if newHold.ExpiresAt.IsZero() {
+		newHold.ExpiresAt = time.Now().Add(u.DefaultTTL)
+	}
+	if !newHold.ExpiresAt.After(time.Now()) {
+		return model.Hold{}, errors.New("expires_at must be in the future")
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	acc, err := u.AccRepo.FindById(newHold.AccountID)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	if err := checkDebit(acc); err != nil {
+		return model.Hold{}, err
+	}
+	if acc.AvailableBalance() < newHold.Amount {
+		return model.Hold{}, ErrInsufficientBalance
+	}
+
+	acc.HeldBalance += newHold.Amount
+	_, err = u.AccRepo.Update(acc)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	newHold.Status = model.HoldStatusActive
+	newHold.CapturedAmount = 0
+	newHold.TransferID = 0
+
+	hold, err := u.HoldRepo.Save(newHold)
+	if err != nil {
+		acc.HeldBalance -= newHold.Amount
+		u.AccRepo.Update(acc)
+		return model.Hold{}, err
+	}
+
+	return hold, nil
+}
+
+// Capture implements HoldUsecase, the hold is captured by the transfer in
+// the same ledger transaction that debits the account. A transfer that waits
+// for approval keeps the hold active until it is decided.
+func (u *HoldUsecaseImpl) Capture(id int64, amount float64, toAccountID int64) (model.Hold, error) {
+	hold, err := u.activeHold(id)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if hold.TransferID != 0 {
+		return model.Hold{}, fmt.Errorf("hold is being captured by transfer %d", hold.TransferID)
+	}
+
+	// Tanpa amount berarti capture penuh, sisa hold dilepas setelah partial capture
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if amount < 0 || amount > hold.Amount {
+		return model.Hold{}, fmt.Errorf("capture amount must be between 0 and %.2f", hold.Amount)
+	}
+	if toAccountID == 0 {
+		toAccountID = hold.ToAccountID
+	}
+	if toAccountID == 0 {
+		return model.Hold{}, errors.New("to_account_id is required")
+	}
+
+	// Hold diperiksa ulang di dalam transfer, di bawah lock yang sama dengan debit
+	if _, err := u.TransferUsecase.Save(model.Transfer{
+		FromAccountID: hold.AccountID,
+		ToAccountID:   toAccountID,
+		Amount:        amount,
+		HoldID:        hold.ID,
+	}); err != nil {
+		return model.Hold{}, err
+	}
+
+	return u.HoldRepo.FindById(id)
+}
+
+// Release implements HoldUsecase
+func (u *HoldUsecaseImpl) Release(id int64) (model.Hold, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	hold, err := u.activeHold(id)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if hold.TransferID != 0 {
+		return model.Hold{}, fmt.Errorf("hold is being captured by transfer %d", hold.TransferID)
+	}
+
+	return u.finish(hold, model.HoldStatusReleased)
+}
+
+// ExpireDue implements HoldUsecase, a hold whose capture waits for approval
+// does not expire.
+func (u *HoldUsecaseImpl) ExpireDue(now time.Time) error {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	holds, err := u.HoldRepo.FindExpired(now)
+	if err != nil {
+		return err
+	}
+
+	for _, hold := range holds {
+		if hold.TransferID != 0 {
+			continue
+		}
+		if _, err := u.finish(hold, model.HoldStatusExpired); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *HoldUsecaseImpl) activeHold(id int64) (model.Hold, error) {
+	hold, err := u.HoldRepo.FindById(id)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	if hold.Status != model.HoldStatusActive {
+		return model.Hold{}, fmt.Errorf("hold is already %s", hold.Status)
+	}
+	if !hold.ExpiresAt.After(time.Now()) {
+		return model.Hold{}, errors.New("hold has expired")
+	}
+
+	return hold, nil
+}
+
+// finish returns the held funds to the available balance and closes the hold
+// with status. The caller holds balanceMu.
+func (u *HoldUsecaseImpl) finish(hold model.Hold, status string) (model.Hold, error) {
+	tx := newLedgerTx(u.AccRepo, nil)
+	if _, err := tx.adjustHeld(hold.AccountID, -hold.Amount); err != nil {
+		tx.rollback()
+		return model.Hold{}, err
+	}
+
+	hold.Status = status
+	finishedHold, err := u.HoldRepo.Update(hold)
+	if err != nil {
+		tx.rollback()
+		return model.Hold{}, err
+	}
+
+	return finishedHold, nil
+}
+
+func NewHoldUsecaseImpl(HoldRepo repository.HoldRepo, AccountRepo repository.AccountRepo, TransferUsecase TransferUsecase, DefaultTTL time.Duration) HoldUsecase {
+	return &HoldUsecaseImpl{
+		HoldRepo:        HoldRepo,
+		AccRepo:         AccountRepo,
+		TransferUsecase: TransferUsecase,
+		DefaultTTL:      DefaultTTL,
+	}
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type InterestUsecase interface {
+	Accrue(from time.Time, to time.Time) ([]model.InterestAccrual, error)
+	Post(month time.Time, actorID int64) ([]model.History, error)
+	FindByAccountId(accountID int64) ([]model.InterestAccrual, error)
+	RunScheduled(now time.Time) error
+}
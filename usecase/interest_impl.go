@@ -0,0 +1,456 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type InterestUsecaseImpl struct {
+	AccRepo      repository.AccountRepo
+	HisRepo      repository.HistoryRepo
+	AccrualRepo  repository.InterestAccrualRepo
+	ProductRepo  repository.AccountProductRepo
+	AuditUsecase AuditUsecase
+	Events       EventPublisher
+
+	AccountEvents AccountEventRecorder
+
+	ExpenseAccountID int64
+	TaxRate          float64
+}
+
+const accrualDateLayout = "2006-01-02"
+
+// Accrue implements InterestUsecase. Every day in [from, to) that has ended
+// is accrued once per account, days that were already accrued are skipped so
+// a period can be replayed safely.
+func (u *InterestUsecaseImpl) Accrue(from time.Time, to time.Time) ([]model.InterestAccrual, error) {
+	from = startOfDay(from)
+	if today := startOfDay(time.Now()); to.After(today) {
+		to = today
+	}
+	if !from.Before(to) {
+		return nil, errors.New("interest can only be accrued for days that have ended")
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	accounts, err := u.AccRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := u.ProductRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	productByCode := map[string]model.AccountProduct{}
+	for _, product := range products {
+		productByCode[product.Code] = product
+	}
+
+	historys, err := u.HisRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	historysByAccount := map[int64][]model.History{}
+	for _, history := range historys {
+		historysByAccount[history.AccountID] = append(historysByAccount[history.AccountID], history)
+	}
+
+	accruals, err := u.AccrualRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	accrualsByAccount := map[int64][]model.InterestAccrual{}
+	for _, accrual := range accruals {
+		accrualsByAccount[accrual.AccountID] = append(accrualsByAccount[accrual.AccountID], accrual)
+	}
+
+	var created []model.InterestAccrual
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, acc := range accounts {
+			accountType := acc.Type
+			if accountType == "" {
+				accountType = model.AccountTypeSavings
+			}
+			product, ok := productByCode[accountType]
+			// Bunga hanya untuk akun dalam mata uang default, akun beban bunga juga dalam mata uang itu
+			if !ok || product.InterestRate <= 0 || acc.ID == u.ExpenseAccountID || acc.CurrencyCode() != model.DefaultCurrency {
+				continue
+			}
+
+			accrual, ok := u.accrueDay(acc, product, day, historysByAccount[acc.ID], accrualsByAccount[acc.ID])
+			if !ok {
+				continue
+			}
+
+			accrual, err = u.AccrualRepo.Save(accrual)
+			if err != nil {
+				return created, err
+			}
+			accrualsByAccount[acc.ID] = append(accrualsByAccount[acc.ID], accrual)
+			created = append(created, accrual)
+		}
+	}
+
+	return created, nil
+}
+
+// accrueDay computes the interest of acc for day, ok is false when the
+// account earns nothing that day or the day was already accrued.
+func (u *InterestUsecaseImpl) accrueDay(acc model.Account, product model.AccountProduct, day time.Time, historys []model.History, accruals []model.InterestAccrual) (model.InterestAccrual, bool) {
+	end := day.AddDate(0, 0, 1)
+	date := day.Format(accrualDateLayout)
+
+	if !acc.CreatedAt.Before(end) {
+		return model.InterestAccrual{}, false
+	}
+	if acc.CurrentStatus() == model.AccountStatusClosed && acc.ClosedAt.Before(end) {
+		return model.InterestAccrual{}, false
+	}
+	for _, accrual := range accruals {
+		if accrual.Date == date {
+			return model.InterestAccrual{}, false
+		}
+	}
+
+	// Saldo akhir hari dihitung mundur dari saldo sekarang supaya hari yang
+	// sudah lewat bisa dihitung ulang
+	balance := acc.Balance
+	for _, history := range historys {
+		if !history.CreatedAt.Before(end) {
+			balance -= history.Amount
+		}
+	}
+
+	// Bunga majemuk juga menghitung bunga bulan berjalan yang belum dibayar
+	if product.InterestCompounding == model.InterestCompound {
+		month := day.Format("2006-01")
+		for _, accrual := range accruals {
+			if accrual.Date < date && accrual.Date[:7] == month {
+				balance += accrual.Amount
+			}
+		}
+	}
+
+	if balance <= 0 {
+		return model.InterestAccrual{}, false
+	}
+
+	dayCount := product.InterestDayCount
+	if dayCount == "" {
+		dayCount = model.DayCountACT365
+	}
+	compounding := product.InterestCompounding
+	if compounding == "" {
+		compounding = model.InterestSimple
+	}
+
+	return model.InterestAccrual{
+		AccountID:   acc.ID,
+		Date:        date,
+		Balance:     balance,
+		Rate:        product.InterestRate,
+		DayCount:    dayCount,
+		Compounding: compounding,
+		Amount:      balance * product.InterestRate / 100 / daysInYear(dayCount, day),
+	}, true
+}
+
+// daysInYear is the denominator of the day count convention.
+func daysInYear(dayCount string, day time.Time) float64 {
+	switch dayCount {
+	case model.DayCountACT360:
+		return 360
+	case model.DayCountACTACT:
+		start := time.Date(day.Year(), 1, 1, 0, 0, 0, 0, day.Location())
+		return start.AddDate(1, 0, 0).Sub(start).Hours() / 24
+	}
+	return 365
+}
+
+// Post implements InterestUsecase. The unposted accruals of the month are
+// paid from the interest expense account, less withholding tax. Accounts
+// that were closed in the meantime forfeit their accrued interest.
+func (u *InterestUsecaseImpl) Post(month time.Time, actorID int64) ([]model.History, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	end := start.AddDate(0, 1, 0)
+	if end.After(time.Now()) {
+		return nil, errors.New("interest can only be posted after the month has ended")
+	}
+	if u.ExpenseAccountID == 0 {
+		return nil, errors.New("interest expense account is not configured")
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	unposted, err := u.AccrualRepo.FindUnposted()
+	if err != nil {
+		return nil, err
+	}
+
+	byAccount := map[int64][]model.InterestAccrual{}
+	var accountIDs []int64
+	for _, accrual := range unposted {
+		if accrual.Date < start.Format(accrualDateLayout) || accrual.Date >= end.Format(accrualDateLayout) {
+			continue
+		}
+		if _, ok := byAccount[accrual.AccountID]; !ok {
+			accountIDs = append(accountIDs, accrual.AccountID)
+		}
+		byAccount[accrual.AccountID] = append(byAccount[accrual.AccountID], accrual)
+	}
+	sort.Slice(accountIDs, func(i, j int) bool { return accountIDs[i] < accountIDs[j] })
+
+	var posted []model.History
+	for _, accountID := range accountIDs {
+		history, err := u.postAccount(accountID, byAccount[accountID], start, actorID)
+		if err != nil {
+			return posted, fmt.Errorf("post interest for account %d: %w", accountID, err)
+		}
+		if history.ID != 0 {
+			posted = append(posted, history)
+		}
+	}
+
+	return posted, nil
+}
+
+// postAccount pays out the accruals of one account and marks them posted.
+func (u *InterestUsecaseImpl) postAccount(accountID int64, accruals []model.InterestAccrual, month time.Time, actorID int64) (model.History, error) {
+	var gross float64
+	for _, accrual := range accruals {
+		gross += accrual.Amount
+	}
+	gross = math.Round(gross*100) / 100
+	tax := math.Round(gross*u.TaxRate) / 100
+	net := math.Round((gross-tax)*100) / 100
+
+	acc, err := u.AccRepo.FindById(accountID)
+	if err != nil {
+		return model.History{}, err
+	}
+
+	tx := newLedgerTx(u.AccRepo, u.HisRepo)
+	var history model.History
+	if gross > 0 && acc.CurrentStatus() != model.AccountStatusClosed {
+		history, err = u.writePosting(tx, acc, gross, tax, net, month)
+		if err != nil {
+			tx.rollback()
+			return model.History{}, err
+		}
+	}
+
+	postedAt := time.Now()
+	for _, accrual := range accruals {
+		previous := accrual
+		accrual.PostedAt = postedAt
+		accrual.HistoryID = history.ID
+		if _, err := u.AccrualRepo.Update(accrual); err != nil {
+			tx.rollback()
+			return model.History{}, err
+		}
+		tx.undo(func() {
+			if _, err := u.AccrualRepo.Update(previous); err != nil {
+				log.Printf("rollback interest accrual %d: %v", previous.ID, err)
+			}
+		})
+	}
+
+	if err := tx.record(u.AccountEvents); err != nil {
+		tx.rollback()
+		return model.History{}, err
+	}
+	tx.commit(u.Events)
+
+	if history.ID != 0 {
+		recordAudit(u.AuditUsecase, "interest.posted", actorID, "account", acc.ID,
+			fmt.Sprintf("interest %s of %.2f, tax %.2f, history %d", month.Format("2006-01"), gross, tax, history.ID))
+	}
+
+	return history, nil
+}
+
+// writePosting credits the gross interest, deducts the withholding tax and
+// debits the net amount from the interest expense account.
+func (u *InterestUsecaseImpl) writePosting(tx *ledgerTx, acc model.Account, gross float64, tax float64, net float64, month time.Time) (model.History, error) {
+	period := month.Format("2006-01")
+
+	acc, err := tx.adjust(acc.ID, gross)
+	if err != nil {
+		return model.History{}, err
+	}
+	history, err := tx.saveHistory(model.History{
+		AccountID:             acc.ID,
+		Account:               acc,
+		Type:                  model.HistoryTypeInterest,
+		CounterpartyAccountID: u.ExpenseAccountID,
+		Description:           "Interest " + period,
+		Amount:                gross,
+		BalanceAfter:          acc.Balance,
+	})
+	if err != nil {
+		return model.History{}, err
+	}
+
+	if tax > 0 {
+		acc, err = tx.adjust(acc.ID, -tax)
+		if err != nil {
+			return model.History{}, err
+		}
+		_, err = tx.saveHistory(model.History{
+			AccountID:    acc.ID,
+			Account:      acc,
+			Type:         model.HistoryTypeTax,
+			Description:  fmt.Sprintf("Withholding tax %g%% on interest %s", u.TaxRate, period),
+			Amount:       -tax,
+			BalanceAfter: acc.Balance,
+		})
+		if err != nil {
+			return model.History{}, err
+		}
+	}
+
+	expense, err := tx.adjust(u.ExpenseAccountID, -net)
+	if err != nil {
+		return model.History{}, err
+	}
+	_, err = tx.saveHistory(model.History{
+		AccountID:             expense.ID,
+		Account:               expense,
+		Type:                  model.HistoryTypeInterest,
+		CounterpartyAccountID: acc.ID,
+		Description:           fmt.Sprintf("Interest %s for account %d", period, acc.ID),
+		Amount:                -net,
+		BalanceAfter:          expense.Balance,
+	})
+	if err != nil {
+		return model.History{}, err
+	}
+
+	return history, nil
+}
+
+// FindByAccountId implements InterestUsecase
+func (u *InterestUsecaseImpl) FindByAccountId(accountID int64) ([]model.InterestAccrual, error) {
+	return u.AccrualRepo.FindByAccountId(accountID)
+}
+
+// RunScheduled implements InterestUsecase. It accrues every day since the
+// last accrued day, so days missed while the service was down are caught up,
+// and posts every month that has ended and still has unposted accruals.
+func (u *InterestUsecaseImpl) RunScheduled(now time.Time) error {
+	today := startOfDay(now)
+	from, err := u.lastAccrualDay(today)
+	if err != nil {
+		return err
+	}
+	accruals, err := u.Accrue(from, today)
+	if err != nil {
+		return err
+	}
+	if len(accruals) > 0 {
+		log.Printf("interest: accrued %d account days from %s", len(accruals), accruals[0].Date)
+	}
+
+	if u.ExpenseAccountID == 0 {
+		return nil
+	}
+
+	months, err := u.unpostedMonths(today)
+	if err != nil {
+		return err
+	}
+	for _, month := range months {
+		historys, err := u.Post(month, 0)
+		if err != nil {
+			return err
+		}
+		if len(historys) > 0 {
+			log.Printf("interest: posted %s to %d accounts", month.Format("2006-01"), len(historys))
+		}
+	}
+	return nil
+}
+
+// lastAccrualDay is the day the scheduled accrual starts from, the last day
+// that was accrued or yesterday when nothing was accrued yet. The last day
+// is accrued again because Accrue skips the accounts that already have it.
+func (u *InterestUsecaseImpl) lastAccrualDay(today time.Time) (time.Time, error) {
+	accruals, err := u.AccrualRepo.FindAll()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	last := ""
+	for _, accrual := range accruals {
+		if accrual.Date > last {
+			last = accrual.Date
+		}
+	}
+	if last == "" {
+		return today.AddDate(0, 0, -1), nil
+	}
+	return time.ParseInLocation(accrualDateLayout, last, today.Location())
+}
+
+// unpostedMonths returns the months before today's month that still have
+// unposted accruals, oldest first.
+func (u *InterestUsecaseImpl) unpostedMonths(today time.Time) ([]time.Time, error) {
+	unposted, err := u.AccrualRepo.FindUnposted()
+	if err != nil {
+		return nil, err
+	}
+
+	current := today.Format("2006-01")
+	seen := map[string]bool{}
+	var periods []string
+	for _, accrual := range unposted {
+		period := accrual.Date[:7]
+		if period >= current || seen[period] {
+			continue
+		}
+		seen[period] = true
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+
+	months := make([]time.Time, 0, len(periods))
+	for _, period := range periods {
+		month, err := time.ParseInLocation("2006-01", period, today.Location())
+		if err != nil {
+			return nil, err
+		}
+		months = append(months, month)
+	}
+	return months, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func NewInterestUsecaseImpl(AccountRepo repository.AccountRepo, HisRepo repository.HistoryRepo, AccrualRepo repository.InterestAccrualRepo, ProductRepo repository.AccountProductRepo, AuditUsecase AuditUsecase, ExpenseAccountID int64, TaxRate float64, Events EventPublisher, AccountEvents AccountEventRecorder) InterestUsecase {
+	return &InterestUsecaseImpl{
+		AccRepo:      AccountRepo,
+		HisRepo:      HisRepo,
+		AccrualRepo:  AccrualRepo,
+		ProductRepo:  ProductRepo,
+		AuditUsecase: AuditUsecase,
+
+		ExpenseAccountID: ExpenseAccountID,
+		TaxRate:          TaxRate,
+		Events:           Events,
+		AccountEvents:    AccountEvents,
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/sferawann/test_mnc/eventsource"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+// balanceMu serializes every read-modify-write of account balances, they can
+// be changed by requests and background jobs at the same time.
+var balanceMu sync.Mutex
+
+// heldTolerance absorbs float rounding when held funds are released.
+const heldTolerance = 0.005
+
+// ledgerTx groups the balance and history writes of one money movement. The
+// JSON repositories have no transactions, so a failed step is undone by
+// restoring the touched accounts and deleting the written history rows.
+type ledgerTx struct {
+	accRepo   repository.AccountRepo
+	hisRepo   repository.HistoryRepo
+	originals map[int64]model.Account
+	order     []int64
+	historys  []int64
+	saved     []model.History
+	transfers []model.Transfer
+	undos     []func()
+}
+
+func newLedgerTx(accRepo repository.AccountRepo, hisRepo repository.HistoryRepo) *ledgerTx {
+	return &ledgerTx{
+		accRepo:   accRepo,
+		hisRepo:   hisRepo,
+		originals: map[int64]model.Account{},
+	}
+}
+
+// adjust adds delta to the balance of the account, always starting from the
+// stored state so several adjustments to the same account add up.
+func (tx *ledgerTx) adjust(accountID int64, delta float64) (model.Account, error) {
+	acc, err := tx.accRepo.FindById(accountID)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	if _, ok := tx.originals[accountID]; !ok {
+		tx.originals[accountID] = acc
+		tx.order = append(tx.order, accountID)
+	}
+
+	acc.Balance += delta
+	return tx.accRepo.Update(acc)
+}
+
+// adjustHeld adds delta to the held balance of the account, releasing more
+// than is held is an error.
+func (tx *ledgerTx) adjustHeld(accountID int64, delta float64) (model.Account, error) {
+	acc, err := tx.accRepo.FindById(accountID)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	if _, ok := tx.originals[accountID]; !ok {
+		tx.originals[accountID] = acc
+		tx.order = append(tx.order, accountID)
+	}
+
+	acc.HeldBalance += delta
+	if acc.HeldBalance < -heldTolerance {
+		return model.Account{}, fmt.Errorf("held balance of account %d would become %.2f", accountID, acc.HeldBalance)
+	}
+	if acc.HeldBalance < 0 {
+		acc.HeldBalance = 0
+	}
+	return tx.accRepo.Update(acc)
+}
+
+func (tx *ledgerTx) saveHistory(newHistory model.History) (model.History, error) {
+	history, err := tx.hisRepo.Save(newHistory)
+	if err != nil {
+		return model.History{}, err
+	}
+
+	tx.historys = append(tx.historys, history.ID)
+	tx.saved = append(tx.saved, history)
+	return history, nil
+}
+
+// completed registers a transfer posted through tx, it is published as
+// completed on commit.
+func (tx *ledgerTx) completed(transfer model.Transfer) {
+	tx.transfers = append(tx.transfers, transfer)
+}
+
+// record appends an account event for every history row written through tx,
+// followed by extra. The event store cannot be rolled back, so this is the
+// last step before commit and the caller rolls back the other writes when it
+// fails.
+func (tx *ledgerTx) record(recorder AccountEventRecorder, extra ...model.AccountEvent) error {
+	var events []model.AccountEvent
+	for _, history := range tx.saved {
+		if event, ok := eventsource.FromHistory(history); ok {
+			events = append(events, event)
+		}
+	}
+	return recordEvents(recorder, append(events, extra...)...)
+}
+
+// commit publishes a balance change for every history row and every transfer
+// written through tx, it is called once every step has succeeded.
+func (tx *ledgerTx) commit(events EventPublisher) {
+	for _, history := range tx.saved {
+		publishBalanceChanged(events, history)
+	}
+	for _, transfer := range tx.transfers {
+		publishTransfer(events, model.EventTransferCompleted, transfer)
+	}
+}
+
+// undo registers fn to run on rollback, for writes to other repositories
+// that belong to the same money movement.
+func (tx *ledgerTx) undo(fn func()) {
+	tx.undos = append(tx.undos, fn)
+}
+
+// rollback undoes every write in reverse order. Errors are only logged since
+// the caller is already returning the error that caused the rollback.
+func (tx *ledgerTx) rollback() {
+	for i := len(tx.undos) - 1; i >= 0; i-- {
+		tx.undos[i]()
+	}
+
+	for i := len(tx.historys) - 1; i >= 0; i-- {
+		if _, err := tx.hisRepo.Delete(tx.historys[i]); err != nil {
+			log.Printf("rollback history %d: %v", tx.historys[i], err)
+		}
+	}
+
+	for i := len(tx.order) - 1; i >= 0; i-- {
+		if _, err := tx.accRepo.Update(tx.originals[tx.order[i]]); err != nil {
+			log.Printf("rollback account %d: %v", tx.order[i], err)
+		}
+	}
+}
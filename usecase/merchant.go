@@ -0,0 +1,13 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type MerchantUsecase interface {
+	Save(newMerchant model.Merchant) (model.Merchant, error)
+	FindById(id int64) (model.Merchant, error)
+	FindByUserId(userID int64) ([]model.Merchant, error)
+	GenerateQR(merchantID int64, amount float64, billNumber string) (model.MerchantQR, error)
+	Decode(payload string) (model.MerchantQR, model.Merchant, error)
+	Pay(payerID int64, payment model.QRPayment) (model.Transfer, error)
+	FindPayments(merchantID int64) ([]model.Transfer, error)
+}
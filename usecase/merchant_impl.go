@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/qris"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const defaultMerchantCategoryCode = "5999"
+
+type MerchantUsecaseImpl struct {
+	MerchantRepo    repository.MerchantRepo
+	AccRepo         repository.AccountRepo
+	TraRepo         repository.TransferRepo
+	TransferUsecase TransferUsecase
+	AuditUsecase    AuditUsecase
+
+	FrozenBlocksCredits bool
+
+	// mu keeps a dynamic QR from being paid twice
+	mu sync.Mutex
+}
+
+// Save implements MerchantUsecase
+func (u *MerchantUsecaseImpl) Save(newMerchant model.Merchant) (model.Merchant, error) {
+	newMerchant.Name = strings.TrimSpace(newMerchant.Name)
+	newMerchant.City = strings.TrimSpace(newMerchant.City)
+	if newMerchant.Name == "" || len(newMerchant.Name) > 25 {
+		return model.Merchant{}, errors.New("name must have 1 to 25 characters")
+	}
+	if newMerchant.City == "" || len(newMerchant.City) > 15 {
+		return model.Merchant{}, errors.New("city must have 1 to 15 characters")
+	}
+	if newMerchant.CategoryCode == "" {
+		newMerchant.CategoryCode = defaultMerchantCategoryCode
+	}
+	if _, err := strconv.Atoi(newMerchant.CategoryCode); err != nil || len(newMerchant.CategoryCode) != 4 {
+		return model.Merchant{}, errors.New("category_code must have 4 digits")
+	}
+
+	acc, err := u.AccRepo.FindById(newMerchant.AccountID)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+	if acc.UserID != newMerchant.UserID {
+		return model.Merchant{}, errors.New("account does not belong to the user")
+	}
+	if acc.CurrentStatus() == model.AccountStatusClosed {
+		return model.Merchant{}, &AccountStatusError{AccountID: acc.ID, Status: model.AccountStatusClosed}
+	}
+	if acc.CurrencyCode() != model.DefaultCurrency {
+		return model.Merchant{}, fmt.Errorf("QR payments are only available for %s accounts", model.DefaultCurrency)
+	}
+	if _, err := u.MerchantRepo.FindByAccountId(acc.ID); err == nil {
+		return model.Merchant{}, fmt.Errorf("account %d is already a merchant", acc.ID)
+	}
+
+	newMerchant.ID = 0
+	newMerchant.CreatedAt = time.Now()
+	merchant, err := u.MerchantRepo.Save(newMerchant)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	recordAudit(u.AuditUsecase, "merchant.create", merchant.UserID, "merchant", merchant.ID,
+		fmt.Sprintf("account %d", merchant.AccountID))
+
+	return merchant, nil
+}
+
+// FindById implements MerchantUsecase
+func (u *MerchantUsecaseImpl) FindById(id int64) (model.Merchant, error) {
+	return u.MerchantRepo.FindById(id)
+}
+
+// FindByUserId implements MerchantUsecase
+func (u *MerchantUsecaseImpl) FindByUserId(userID int64) ([]model.Merchant, error) {
+	return u.MerchantRepo.FindByUserId(userID)
+}
+
+// GenerateQR implements MerchantUsecase, a positive amount makes a dynamic
+// QR with a bill number, generated when billNumber is empty.
+func (u *MerchantUsecaseImpl) GenerateQR(merchantID int64, amount float64, billNumber string) (model.MerchantQR, error) {
+	if amount < 0 {
+		return model.MerchantQR{}, errors.New("amount cannot be negative")
+	}
+
+	merchant, err := u.MerchantRepo.FindById(merchantID)
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+	acc, err := u.AccRepo.FindById(merchant.AccountID)
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+	// QR untuk akun yang tidak bisa menerima dana hanya akan gagal saat dibayar
+	if err := checkCredit(acc, u.FrozenBlocksCredits); err != nil {
+		return model.MerchantQR{}, err
+	}
+
+	billNumber = strings.TrimSpace(billNumber)
+	if amount > 0 && billNumber == "" {
+		billNumber = fmt.Sprintf("%d%s", merchant.ID, time.Now().Format("060102150405.000"))
+		billNumber = strings.Replace(billNumber, ".", "", 1)
+	}
+	if len(billNumber) > 25 {
+		return model.MerchantQR{}, errors.New("bill_number must have at most 25 characters")
+	}
+
+	payload, err := qris.Encode(qris.Payload{
+		AccountNumber: acc.Number,
+		MerchantID:    strconv.FormatInt(merchant.ID, 10),
+		CategoryCode:  merchant.CategoryCode,
+		Currency:      acc.CurrencyCode(),
+		Amount:        amount,
+		MerchantName:  merchant.Name,
+		MerchantCity:  merchant.City,
+		PostalCode:    merchant.PostalCode,
+		BillNumber:    billNumber,
+	})
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+
+	return model.MerchantQR{
+		MerchantID: merchant.ID,
+		Payload:    payload,
+		Dynamic:    amount > 0,
+		Amount:     amount,
+		BillNumber: billNumber,
+	}, nil
+}
+
+// Decode implements MerchantUsecase, it resolves the merchant of a payload
+// so the payer can confirm it before paying.
+func (u *MerchantUsecaseImpl) Decode(payload string) (model.MerchantQR, model.Merchant, error) {
+	decoded, err := qris.Decode(payload)
+	if err != nil {
+		return model.MerchantQR{}, model.Merchant{}, err
+	}
+
+	merchantID, err := strconv.ParseInt(decoded.MerchantID, 10, 64)
+	if err != nil {
+		return model.MerchantQR{}, model.Merchant{}, errors.New("QR code has no valid merchant id")
+	}
+	merchant, err := u.MerchantRepo.FindById(merchantID)
+	if err != nil {
+		return model.MerchantQR{}, model.Merchant{}, err
+	}
+
+	// QR lama tidak berlaku kalau rekening merchant sudah berubah
+	acc, err := u.AccRepo.FindById(merchant.AccountID)
+	if err != nil {
+		return model.MerchantQR{}, model.Merchant{}, err
+	}
+	if acc.Number != decoded.AccountNumber || acc.CurrencyCode() != decoded.Currency {
+		return model.MerchantQR{}, model.Merchant{}, errors.New("QR code does not match the merchant account")
+	}
+
+	return model.MerchantQR{
+		MerchantID: merchant.ID,
+		Payload:    strings.TrimSpace(payload),
+		Dynamic:    decoded.Dynamic(),
+		Amount:     decoded.Amount,
+		BillNumber: decoded.BillNumber,
+	}, merchant, nil
+}
+
+// Pay implements MerchantUsecase
+func (u *MerchantUsecaseImpl) Pay(payerID int64, payment model.QRPayment) (model.Transfer, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	qr, merchant, err := u.Decode(payment.Payload)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	amount := payment.Amount
+	if qr.Dynamic {
+		if amount != 0 && amount != qr.Amount {
+			return model.Transfer{}, fmt.Errorf("QR code is for an amount of %.2f", qr.Amount)
+		}
+		amount = qr.Amount
+
+		paid, err := u.billPaid(merchant.ID, qr.BillNumber)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+		if paid {
+			return model.Transfer{}, fmt.Errorf("bill %s has already been paid", qr.BillNumber)
+		}
+	}
+	if amount <= 0 {
+		return model.Transfer{}, errors.New("amount is required for a static QR code")
+	}
+
+	fromacc, err := u.AccRepo.FindById(payment.FromAccountID)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if fromacc.UserID != payerID {
+		return model.Transfer{}, errors.New("from_account_id does not belong to the payer")
+	}
+
+	transfer, err := u.TransferUsecase.Save(model.Transfer{
+		FromAccountID: fromacc.ID,
+		ToAccountID:   merchant.AccountID,
+		Amount:        amount,
+		InitiatedBy:   payerID,
+		MerchantID:    merchant.ID,
+		Channel:       model.ChannelQRIS,
+		Reference:     qr.BillNumber,
+	})
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	recordAudit(u.AuditUsecase, "merchant.payment", payerID, "merchant", merchant.ID,
+		fmt.Sprintf("transfer %d of %.2f", transfer.ID, transfer.Amount))
+
+	return transfer, nil
+}
+
+// FindPayments implements MerchantUsecase
+func (u *MerchantUsecaseImpl) FindPayments(merchantID int64) ([]model.Transfer, error) {
+	transfers, err := u.TraRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []model.Transfer
+	for _, transfer := range transfers {
+		if transfer.MerchantID == merchantID {
+			payments = append(payments, transfer)
+		}
+	}
+
+	return payments, nil
+}
+
+// billPaid reports whether a payment of the bill completed or still waits
+// for approval.
+func (u *MerchantUsecaseImpl) billPaid(merchantID int64, billNumber string) (bool, error) {
+	payments, err := u.FindPayments(merchantID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, payment := range payments {
+		if payment.Reference != billNumber || payment.Reversed {
+			continue
+		}
+		if payment.Status == model.TransferStatusCompleted || payment.Status == model.TransferStatusPendingApproval {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func NewMerchantUsecaseImpl(MerchantRepo repository.MerchantRepo, AccRepo repository.AccountRepo, TraRepo repository.TransferRepo, TransferUsecase TransferUsecase, AuditUsecase AuditUsecase, FrozenBlocksCredits bool) MerchantUsecase {
+	return &MerchantUsecaseImpl{
+		MerchantRepo:        MerchantRepo,
+		AccRepo:             AccRepo,
+		TraRepo:             TraRepo,
+		TransferUsecase:     TransferUsecase,
+		AuditUsecase:        AuditUsecase,
+		FrozenBlocksCredits: FrozenBlocksCredits,
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+// OutboxUsecase relays the domain events in the outbox to the broker.
+type OutboxUsecase interface {
+	FindAll(status string) ([]model.OutboxEvent, error)
+	Relay(now time.Time) error
+}
+
+// outboxMu serializes writes to the outbox, events are written by several
+// usecases while the relay marks them published.
+var outboxMu sync.Mutex
+
+// saveOutbox writes a domain event in the same unit of work as the change it
+// describes. The returned func removes the event again when a later step of
+// that change fails. Without an outbox nothing is written.
+func saveOutbox(repo repository.OutboxEventRepo, eventType string, aggregateType string, aggregateID int64, payload interface{}) (func(), error) {
+	if repo == nil {
+		return func() {}, nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxMu.Lock()
+	defer outboxMu.Unlock()
+
+	event, err := repo.Save(model.OutboxEvent{
+		EventID:       newEventID(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       body,
+		Status:        model.OutboxStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		outboxMu.Lock()
+		defer outboxMu.Unlock()
+		if _, err := repo.Delete(event.ID); err != nil {
+			log.Printf("rollback outbox event %d: %v", event.ID, err)
+		}
+	}, nil
+}
+
+func saveTransferCreated(repo repository.OutboxEventRepo, transfer model.Transfer) (func(), error) {
+	return saveOutbox(repo, model.DomainEventTransferCreated, model.AggregateTransfer, transfer.ID, model.TransferCreated{
+		TransferID:     transfer.ID,
+		FromAccountID:  transfer.FromAccountID,
+		ToAccountID:    transfer.ToAccountID,
+		Amount:         transfer.Amount,
+		Currency:       transfer.Currency,
+		TargetAmount:   transfer.TargetAmount,
+		TargetCurrency: transfer.TargetCurrency,
+		Fee:            transfer.Fee,
+		Status:         transfer.Status,
+		Channel:        transfer.Channel,
+		Reference:      transfer.Reference,
+		ReversalOf:     transfer.ReversalOf,
+		InitiatedBy:    transfer.InitiatedBy,
+		CreatedAt:      transfer.CreatedAt,
+	})
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/broker"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/webhook"
+)
+
+type OutboxUsecaseImpl struct {
+	OutboxRepo  repository.OutboxEventRepo
+	Broker      broker.Broker
+	TopicPrefix string
+	BatchSize   int
+	RetryBase   time.Duration
+	RetryMax    time.Duration
+
+	// relayMu keeps a slow relay run from overlapping the next one
+	relayMu sync.Mutex
+}
+
+// outboxMessage is what consumers receive, ID is the same for every retry of
+// an event.
+type outboxMessage struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int64           `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// FindAll implements OutboxUsecase
+func (u *OutboxUsecaseImpl) FindAll(status string) ([]model.OutboxEvent, error) {
+	if status != "" {
+		return u.OutboxRepo.FindByStatus(status)
+	}
+	return u.OutboxRepo.FindAll()
+}
+
+// Relay implements OutboxUsecase. Events are published in the order they were
+// written, a failed event stops the run so later events never overtake it.
+func (u *OutboxUsecaseImpl) Relay(now time.Time) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	outboxMu.Lock()
+	pending, err := u.OutboxRepo.FindByStatus(model.OutboxStatusPending)
+	outboxMu.Unlock()
+	if err != nil {
+		return err
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+
+	for i, event := range pending {
+		if u.BatchSize > 0 && i >= u.BatchSize {
+			break
+		}
+		if event.NextAttemptAt.After(now) {
+			break
+		}
+
+		// Broker dipanggil tanpa outboxMu supaya penulisan event baru tidak ikut menunggu
+		event.Attempts++
+		if err := u.publish(event); err != nil {
+			event.LastError = err.Error()
+			event.NextAttemptAt = now.Add(webhook.Backoff(event.Attempts, u.RetryBase, u.RetryMax))
+			if _, updateErr := u.update(event); updateErr != nil {
+				return updateErr
+			}
+			return fmt.Errorf("publish outbox event %d: %w", event.ID, err)
+		}
+
+		event.Status = model.OutboxStatusPublished
+		event.LastError = ""
+		event.PublishedAt = now
+		if _, err := u.update(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *OutboxUsecaseImpl) publish(event model.OutboxEvent) error {
+	body, err := json.Marshal(outboxMessage{
+		ID:            event.EventID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Payload:       event.Payload,
+		OccurredAt:    event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	topic := event.AggregateType
+	if u.TopicPrefix != "" {
+		topic = u.TopicPrefix + "." + topic
+	}
+	return u.Broker.Publish(topic, strconv.FormatInt(event.AggregateID, 10), body)
+}
+
+func (u *OutboxUsecaseImpl) update(event model.OutboxEvent) (model.OutboxEvent, error) {
+	outboxMu.Lock()
+	defer outboxMu.Unlock()
+	return u.OutboxRepo.Update(event)
+}
+
+func NewOutboxUsecaseImpl(OutboxRepo repository.OutboxEventRepo, Broker broker.Broker, TopicPrefix string, BatchSize int, RetryBase time.Duration, RetryMax time.Duration) OutboxUsecase {
+	return &OutboxUsecaseImpl{
+		OutboxRepo:  OutboxRepo,
+		Broker:      Broker,
+		TopicPrefix: TopicPrefix,
+		BatchSize:   BatchSize,
+		RetryBase:   RetryBase,
+		RetryMax:    RetryMax,
+	}
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type PaymentRequestUsecase interface {
+	Save(newRequest model.PaymentRequest) (model.PaymentRequest, error)
+	Split(requesterID int64, bill model.SplitBill) ([]model.PaymentRequest, error)
+	FindById(id int64) (model.PaymentRequest, error)
+	FindIncoming(payerID int64, status string) ([]model.PaymentRequest, error)
+	FindOutgoing(requesterID int64, status string) ([]model.PaymentRequest, error)
+	Accept(id int64, payerID int64, fromAccountID int64) (model.PaymentRequest, error)
+	Decline(id int64, payerID int64, reason string) (model.PaymentRequest, error)
+	Cancel(id int64, requesterID int64) (model.PaymentRequest, error)
+	ExpireDue(now time.Time) error
+}
@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+// maxNoteLength keeps payment request notes short enough for a notification.
+const maxNoteLength = 140
+
+type PaymentRequestUsecaseImpl struct {
+	RequestRepo     repository.PaymentRequestRepo
+	AccRepo         repository.AccountRepo
+	UserRepo        repository.UserRepo
+	TransferUsecase TransferUsecase
+	AuditUsecase    AuditUsecase
+	TTL             time.Duration
+
+	// mu makes answering a request and expiring it mutually exclusive, so a
+	// request is never paid twice
+	mu sync.Mutex
+}
+
+// Save implements PaymentRequestUsecase
+func (u *PaymentRequestUsecaseImpl) Save(newRequest model.PaymentRequest) (model.PaymentRequest, error) {
+	if err := validateCashAmount(newRequest.Amount); err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if len(newRequest.Note) > maxNoteLength {
+		return model.PaymentRequest{}, fmt.Errorf("note must not be longer than %d characters", maxNoteLength)
+	}
+	if newRequest.PayerID == newRequest.RequesterID {
+		return model.PaymentRequest{}, errors.New("payment cannot be requested from yourself")
+	}
+	if _, err := u.UserRepo.FindById(newRequest.PayerID); err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	toacc, err := u.AccRepo.FindById(newRequest.ToAccountID)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if toacc.UserID != newRequest.RequesterID {
+		return model.PaymentRequest{}, errors.New("to_account_id does not belong to the requester")
+	}
+	if toacc.CurrentStatus() == model.AccountStatusClosed {
+		return model.PaymentRequest{}, &AccountStatusError{AccountID: toacc.ID, Status: model.AccountStatusClosed}
+	}
+
+	newRequest.Currency = toacc.CurrencyCode()
+	newRequest.Status = model.PaymentRequestStatusPending
+	newRequest.FromAccountID = 0
+	newRequest.TransferID = 0
+	newRequest.Reason = ""
+	newRequest.RespondedAt = time.Time{}
+	newRequest.ExpiresAt = time.Now().Add(u.TTL)
+
+	return u.RequestRepo.Save(newRequest)
+}
+
+// Split implements PaymentRequestUsecase, the cents that cannot be divided
+// equally are added to the first payers.
+func (u *PaymentRequestUsecaseImpl) Split(requesterID int64, bill model.SplitBill) ([]model.PaymentRequest, error) {
+	if len(bill.PayerIDs) == 0 {
+		return nil, errors.New("payer_ids is required")
+	}
+	if err := validateCashAmount(bill.Total); err != nil {
+		return nil, err
+	}
+
+	seen := map[int64]bool{}
+	for _, payerID := range bill.PayerIDs {
+		if payerID == requesterID {
+			return nil, errors.New("use include_self to take a share of the bill yourself")
+		}
+		if seen[payerID] {
+			return nil, fmt.Errorf("payer %d is listed twice", payerID)
+		}
+		seen[payerID] = true
+	}
+
+	parts := len(bill.PayerIDs)
+	if bill.IncludeSelf {
+		parts++
+	}
+	totalCents := int64(math.Round(bill.Total * 100))
+	shareCents := totalCents / int64(parts)
+	remainder := totalCents % int64(parts)
+	if shareCents == 0 {
+		return nil, errors.New("total is too small to split")
+	}
+
+	var requests []model.PaymentRequest
+	for i, payerID := range bill.PayerIDs {
+		cents := shareCents
+		if int64(i) < remainder {
+			cents++
+		}
+
+		request, err := u.Save(model.PaymentRequest{
+			RequesterID: requesterID,
+			ToAccountID: bill.ToAccountID,
+			PayerID:     payerID,
+			Amount:      float64(cents) / 100,
+			Note:        bill.Note,
+			SplitID:     splitID(requests),
+		})
+		if err != nil {
+			u.deleteAll(requests)
+			return nil, fmt.Errorf("payer %d: %w", payerID, err)
+		}
+		if request.SplitID == 0 {
+			request.SplitID = request.ID
+			if _, err := u.RequestRepo.Update(request); err != nil {
+				u.deleteAll(append(requests, request))
+				return nil, err
+			}
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func splitID(requests []model.PaymentRequest) int64 {
+	if len(requests) == 0 {
+		return 0
+	}
+	return requests[0].ID
+}
+
+func (u *PaymentRequestUsecaseImpl) deleteAll(requests []model.PaymentRequest) {
+	for _, request := range requests {
+		u.RequestRepo.Delete(request.ID)
+	}
+}
+
+// FindById implements PaymentRequestUsecase
+func (u *PaymentRequestUsecaseImpl) FindById(id int64) (model.PaymentRequest, error) {
+	return u.RequestRepo.FindById(id)
+}
+
+// FindIncoming implements PaymentRequestUsecase
+func (u *PaymentRequestUsecaseImpl) FindIncoming(payerID int64, status string) ([]model.PaymentRequest, error) {
+	requests, err := u.RequestRepo.FindByPayerId(payerID)
+	if err != nil {
+		return nil, err
+	}
+	return filterPaymentRequests(requests, status), nil
+}
+
+// FindOutgoing implements PaymentRequestUsecase
+func (u *PaymentRequestUsecaseImpl) FindOutgoing(requesterID int64, status string) ([]model.PaymentRequest, error) {
+	requests, err := u.RequestRepo.FindByRequesterId(requesterID)
+	if err != nil {
+		return nil, err
+	}
+	return filterPaymentRequests(requests, status), nil
+}
+
+func filterPaymentRequests(requests []model.PaymentRequest, status string) []model.PaymentRequest {
+	if status == "" {
+		return requests
+	}
+
+	var filtered []model.PaymentRequest
+	for _, request := range requests {
+		if request.Status == status {
+			filtered = append(filtered, request)
+		}
+	}
+	return filtered
+}
+
+// Accept implements PaymentRequestUsecase, the transfer goes through
+// TransferUsecase so limits, fees and approvals apply as usual. The transfer
+// marks the request paid, or accepted while it waits for approval.
+func (u *PaymentRequestUsecaseImpl) Accept(id int64, payerID int64, fromAccountID int64) (model.PaymentRequest, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	request, err := u.answerable(id, payerID)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	fromacc, err := u.AccRepo.FindById(fromAccountID)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if fromacc.UserID != payerID {
+		return model.PaymentRequest{}, errors.New("from_account_id does not belong to the payer")
+	}
+	if fromacc.CurrencyCode() != request.Currency {
+		return model.PaymentRequest{}, fmt.Errorf("payment request is in %s, pay it from an account in that currency", request.Currency)
+	}
+
+	// Status request diperbarui oleh transfer, juga setelah keputusan approval
+	transfer, err := u.TransferUsecase.Save(model.Transfer{
+		FromAccountID:    fromacc.ID,
+		ToAccountID:      request.ToAccountID,
+		Amount:           request.Amount,
+		InitiatedBy:      payerID,
+		PaymentRequestID: request.ID,
+	})
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	request, err = u.RequestRepo.FindById(id)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	recordAudit(u.AuditUsecase, "payment_request."+request.Status, payerID, "payment_request", request.ID,
+		fmt.Sprintf("transfer %d of %.2f", transfer.ID, transfer.Amount))
+
+	return request, nil
+}
+
+// Decline implements PaymentRequestUsecase
+func (u *PaymentRequestUsecaseImpl) Decline(id int64, payerID int64, reason string) (model.PaymentRequest, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	request, err := u.answerable(id, payerID)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	request.Status = model.PaymentRequestStatusDeclined
+	request.Reason = reason
+	request.RespondedAt = time.Now()
+	return u.RequestRepo.Update(request)
+}
+
+// Cancel implements PaymentRequestUsecase
+func (u *PaymentRequestUsecaseImpl) Cancel(id int64, requesterID int64) (model.PaymentRequest, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	request, err := u.RequestRepo.FindById(id)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if request.RequesterID != requesterID {
+		return model.PaymentRequest{}, errors.New("only the requester can cancel a payment request")
+	}
+	if request.Status != model.PaymentRequestStatusPending {
+		return model.PaymentRequest{}, fmt.Errorf("payment request is already %s", request.Status)
+	}
+
+	request.Status = model.PaymentRequestStatusCancelled
+	request.RespondedAt = time.Now()
+	return u.RequestRepo.Update(request)
+}
+
+// ExpireDue implements PaymentRequestUsecase
+func (u *PaymentRequestUsecaseImpl) ExpireDue(now time.Time) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	requests, err := u.RequestRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		if request.Status != model.PaymentRequestStatusPending || request.ExpiresAt.After(now) {
+			continue
+		}
+
+		request.Status = model.PaymentRequestStatusExpired
+		request.RespondedAt = now
+		if _, err := u.RequestRepo.Update(request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// answerable loads a pending request that payerID may accept or decline.
+func (u *PaymentRequestUsecaseImpl) answerable(id int64, payerID int64) (model.PaymentRequest, error) {
+	request, err := u.RequestRepo.FindById(id)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	if request.PayerID != payerID {
+		return model.PaymentRequest{}, errors.New("payment request is not addressed to the current user")
+	}
+	if request.Status != model.PaymentRequestStatusPending {
+		return model.PaymentRequest{}, fmt.Errorf("payment request is already %s", request.Status)
+	}
+	if !request.ExpiresAt.After(time.Now()) {
+		return model.PaymentRequest{}, errors.New("payment request has expired")
+	}
+
+	return request, nil
+}
+
+func NewPaymentRequestUsecaseImpl(RequestRepo repository.PaymentRequestRepo, AccRepo repository.AccountRepo, UserRepo repository.UserRepo, TransferUsecase TransferUsecase, AuditUsecase AuditUsecase, TTL time.Duration) PaymentRequestUsecase {
+	return &PaymentRequestUsecaseImpl{
+		RequestRepo:     RequestRepo,
+		AccRepo:         AccRepo,
+		UserRepo:        UserRepo,
+		TransferUsecase: TransferUsecase,
+		AuditUsecase:    AuditUsecase,
+		TTL:             TTL,
+	}
+}
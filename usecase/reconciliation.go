@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type ReconciliationUsecase interface {
+	Run(adjust bool, actorID int64) (model.ReconciliationReport, error)
+	RunScheduled(now time.Time) error
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/sferawann/test_mnc/eventsource"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+// reconciliationTolerance absorbs float rounding when comparing balances.
+const reconciliationTolerance = 0.005
+
+type ReconciliationUsecaseImpl struct {
+	AccRepo      repository.AccountRepo
+	HisRepo      repository.HistoryRepo
+	AuditUsecase AuditUsecase
+	Events       EventPublisher
+
+	AccountEvents AccountEventRecorder
+}
+
+// Run implements ReconciliationUsecase
+func (u *ReconciliationUsecaseImpl) Run(adjust bool, actorID int64) (model.ReconciliationReport, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	accounts, err := u.AccRepo.FindAll()
+	if err != nil {
+		return model.ReconciliationReport{}, err
+	}
+
+	historys, err := u.HisRepo.FindAll()
+	if err != nil {
+		return model.ReconciliationReport{}, err
+	}
+
+	computed := map[int64]float64{}
+	counts := map[int64]int{}
+	for _, history := range historys {
+		computed[history.AccountID] += history.Amount
+		counts[history.AccountID]++
+	}
+
+	report := model.ReconciliationReport{
+		RunAt:           time.Now(),
+		AccountsChecked: len(accounts),
+		Adjusted:        adjust,
+	}
+	for _, acc := range accounts {
+		difference := acc.Balance - computed[acc.ID]
+		if math.Abs(difference) < reconciliationTolerance {
+			continue
+		}
+
+		item := model.ReconciliationItem{
+			AccountID:       acc.ID,
+			RecordedBalance: acc.Balance,
+			ComputedBalance: computed[acc.ID],
+			Difference:      math.Round(difference*100) / 100,
+			HistoryCount:    counts[acc.ID],
+		}
+
+		if adjust {
+			adjustment, err := u.writeAdjustment(acc, item, actorID)
+			if err != nil {
+				return model.ReconciliationReport{}, err
+			}
+			item.AdjustmentID = adjustment.ID
+		}
+
+		report.Discrepancies = append(report.Discrepancies, item)
+	}
+
+	return report, nil
+}
+
+// RunScheduled implements ReconciliationUsecase
+func (u *ReconciliationUsecaseImpl) RunScheduled(now time.Time) error {
+	report, err := u.Run(false, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range report.Discrepancies {
+		log.Printf("reconciliation: account %d balance %.2f, history %.2f, difference %.2f",
+			item.AccountID, item.RecordedBalance, item.ComputedBalance, item.Difference)
+	}
+	return nil
+}
+
+// writeAdjustment posts a history row for the difference, the stored balance
+// is taken as correct and is not changed.
+func (u *ReconciliationUsecaseImpl) writeAdjustment(acc model.Account, item model.ReconciliationItem, actorID int64) (model.History, error) {
+	adjustment, err := u.HisRepo.Save(model.History{
+		AccountID:    acc.ID,
+		Account:      acc,
+		Type:         model.HistoryTypeAdjustment,
+		Description:  "Reconciliation adjustment",
+		Amount:       item.Difference,
+		BalanceAfter: acc.Balance,
+	})
+	if err != nil {
+		return model.History{}, err
+	}
+
+	if event, ok := eventsource.FromHistory(adjustment); ok {
+		if err := recordEvents(u.AccountEvents, event); err != nil {
+			u.HisRepo.Delete(adjustment.ID)
+			return model.History{}, err
+		}
+	}
+	publishBalanceChanged(u.Events, adjustment)
+
+	recordAudit(u.AuditUsecase, "reconciliation.adjusted", actorID, "account", acc.ID,
+		fmt.Sprintf("history %d adjusts %.2f, balance %.2f, history sum %.2f", adjustment.ID, item.Difference, item.RecordedBalance, item.ComputedBalance))
+
+	return adjustment, nil
+}
+
+func NewReconciliationUsecaseImpl(AccountRepo repository.AccountRepo, HisRepo repository.HistoryRepo, AuditUsecase AuditUsecase, Events EventPublisher, AccountEvents AccountEventRecorder) ReconciliationUsecase {
+	return &ReconciliationUsecaseImpl{
+		AccRepo:      AccountRepo,
+		HisRepo:      HisRepo,
+		AuditUsecase: AuditUsecase,
+		Events:       Events,
+
+		AccountEvents: AccountEvents,
+	}
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type ScheduledTransferUsecase interface {
+	Save(newSchedule model.ScheduledTransfer) (model.ScheduledTransfer, error)
+	Update(updatedSchedule model.ScheduledTransfer) (model.ScheduledTransfer, error)
+	Cancel(id int64) (model.ScheduledTransfer, error)
+	FindById(id int64) (model.ScheduledTransfer, error)
+	FindByUserId(userID int64) ([]model.ScheduledTransfer, error)
+	FindExecutions(id int64) ([]model.ScheduledTransferExecution, error)
+	RunDue(now time.Time) error
+}
+
+// ScheduleNotifier is called when a scheduled transfer could not be executed
+// and the occurrence was skipped, e.g. because of insufficient funds.
+type ScheduleNotifier interface {
+	NotifySkipped(schedule model.ScheduledTransfer, execution model.ScheduledTransferExecution)
+}
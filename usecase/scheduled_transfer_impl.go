@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type ScheduledTransferUsecaseImpl struct {
+	ScheduledTransferRepo repository.ScheduledTransferRepo
+	ExecutionRepo         repository.ScheduledTransferExecutionRepo
+	AccRepo               repository.AccountRepo
+	TransferUsecase       TransferUsecase
+	Notifier              ScheduleNotifier
+	MaxRetries            int
+	RetryDelay            time.Duration
+
+	// mu makes running a schedule and changing or cancelling it mutually
+	// exclusive, so neither overwrites the other
+	mu sync.Mutex
+}
+
+// Cancel implements ScheduledTransferUsecase
+func (u *ScheduledTransferUsecaseImpl) Cancel(id int64) (model.ScheduledTransfer, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	schedule, err := u.ScheduledTransferRepo.FindById(id)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	if schedule.Status != model.ScheduleStatusActive {
+		return model.ScheduledTransfer{}, fmt.Errorf("scheduled transfer is already %s", schedule.Status)
+	}
+
+	schedule.Status = model.ScheduleStatusCancelled
+	return u.ScheduledTransferRepo.Update(schedule)
+}
+
+// FindById implements ScheduledTransferUsecase
+func (u *ScheduledTransferUsecaseImpl) FindById(id int64) (model.ScheduledTransfer, error) {
+	return u.ScheduledTransferRepo.FindById(id)
+}
+
+// FindByUserId implements ScheduledTransferUsecase
+func (u *ScheduledTransferUsecaseImpl) FindByUserId(userID int64) ([]model.ScheduledTransfer, error) {
+	return u.ScheduledTransferRepo.FindByUserId(userID)
+}
+
+// FindExecutions implements ScheduledTransferUsecase
+func (u *ScheduledTransferUsecaseImpl) FindExecutions(id int64) ([]model.ScheduledTransferExecution, error) {
+	return u.ExecutionRepo.FindByScheduledTransferId(id)
+}
+
+// Save implements ScheduledTransferUsecase
+func (u *ScheduledTransferUsecaseImpl) Save(newSchedule model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	if newSchedule.Frequency == "" {
+		newSchedule.Frequency = model.FrequencyOnce
+	}
+	if newSchedule.StartAt.IsZero() {
+		newSchedule.StartAt = time.Now()
+	}
+
+	if err := u.validate(newSchedule); err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	if newSchedule.StartAt.Before(time.Now().Add(-time.Minute)) {
+		return model.ScheduledTransfer{}, errors.New("start_at must not be in the past")
+	}
+
+	newSchedule.RunCount = 0
+	newSchedule.NextRunAt = newSchedule.StartAt
+	newSchedule.Status = model.ScheduleStatusActive
+
+	return u.ScheduledTransferRepo.Save(newSchedule)
+}
+
+// Update implements ScheduledTransferUsecase
+func (u *ScheduledTransferUsecaseImpl) Update(updatedSchedule model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// Mendapatkan entitas ScheduledTransfer sebelumnya berdasarkan ID
+	previousSchedule, err := u.ScheduledTransferRepo.FindById(updatedSchedule.ID)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	if previousSchedule.Status != model.ScheduleStatusActive {
+		return model.ScheduledTransfer{}, fmt.Errorf("scheduled transfer is already %s", previousSchedule.Status)
+	}
+
+	// Hanya jumlah, tujuan dan batas akhir yang boleh diubah, jadwal tetap mengikuti start_at
+	schedule := previousSchedule
+	if updatedSchedule.ToAccountID != 0 {
+		schedule.ToAccountID = updatedSchedule.ToAccountID
+	}
+	if updatedSchedule.Amount != 0 {
+		schedule.Amount = updatedSchedule.Amount
+	}
+	if !updatedSchedule.EndAt.IsZero() {
+		schedule.EndAt = updatedSchedule.EndAt
+	}
+	if updatedSchedule.MaxRuns != 0 {
+		schedule.MaxRuns = updatedSchedule.MaxRuns
+	}
+
+	if err := u.validate(schedule); err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	if _, ok := nextOccurrence(schedule, schedule.RunCount); !ok {
+		schedule.Status = model.ScheduleStatusCompleted
+	}
+
+	return u.ScheduledTransferRepo.Update(schedule)
+}
+
+// RunDue implements ScheduledTransferUsecase
+func (u *ScheduledTransferUsecaseImpl) RunDue(now time.Time) error {
+	schedules, err := u.ScheduledTransferRepo.FindDue(now)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		if err := u.execute(schedule.ID, now); err != nil {
+			log.Printf("scheduled transfer %d: %v", schedule.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// execute runs a single due occurrence of a schedule. A transient error is
+// retried by a later run after RetryDelay, any other outcome is recorded and
+// moves the schedule to its next occurrence.
+func (u *ScheduledTransferUsecaseImpl) execute(id int64, now time.Time) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// Jadwal dibaca ulang, bisa saja sudah dibatalkan atau diubah sejak FindDue
+	schedule, err := u.ScheduledTransferRepo.FindById(id)
+	if err != nil {
+		return err
+	}
+	if schedule.Status != model.ScheduleStatusActive || schedule.DueAt().After(now) {
+		return nil
+	}
+
+	execution := model.ScheduledTransferExecution{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        schedule.NextRunAt,
+		Attempts:            schedule.Attempts + 1,
+	}
+
+	transfer, err := u.transfer(schedule)
+	switch {
+	case err == nil && transfer.Status == model.TransferStatusPendingApproval:
+		// Uang belum berpindah, transfer masih menunggu keputusan approver
+		execution.Status = model.ExecutionStatusPendingApproval
+		execution.TransferID = transfer.ID
+	case err == nil:
+		execution.Status = model.ExecutionStatusSuccess
+		execution.TransferID = transfer.ID
+	case skipsOccurrence(err):
+		execution.Status = model.ExecutionStatusSkipped
+		execution.Error = err.Error()
+	case isPermanent(err) || execution.Attempts > u.MaxRetries:
+		execution.Status = model.ExecutionStatusFailed
+		execution.Error = err.Error()
+	default:
+		schedule.Attempts = execution.Attempts
+		schedule.NextRetryAt = now.Add(u.RetryDelay)
+		schedule.LastError = err.Error()
+		_, err = u.ScheduledTransferRepo.Update(schedule)
+		return err
+	}
+
+	execution, err = u.ExecutionRepo.Save(execution)
+	if err != nil {
+		return err
+	}
+
+	if execution.Status == model.ExecutionStatusSkipped && u.Notifier != nil {
+		u.Notifier.NotifySkipped(schedule, execution)
+	}
+
+	schedule.RunCount++
+	schedule.Attempts = 0
+	schedule.NextRetryAt = time.Time{}
+	schedule.LastError = execution.Error
+	next, ok := nextOccurrence(schedule, schedule.RunCount)
+	if ok {
+		schedule.NextRunAt = next
+	} else {
+		schedule.Status = model.ScheduleStatusCompleted
+	}
+
+	_, err = u.ScheduledTransferRepo.Update(schedule)
+	return err
+}
+
+// transfer makes the transfer of one occurrence. Accounts that no longer
+// exist are reported as a permanent error, since no retry brings them back.
+func (u *ScheduledTransferUsecaseImpl) transfer(schedule model.ScheduledTransfer) (model.Transfer, error) {
+	accounts, err := u.AccRepo.FindAll()
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	for _, accountID := range []int64{schedule.FromAccountID, schedule.ToAccountID} {
+		if !containsAccount(accounts, accountID) {
+			return model.Transfer{}, &scheduleError{fmt.Sprintf("account %d no longer exists", accountID)}
+		}
+	}
+
+	return u.TransferUsecase.Save(model.Transfer{
+		FromAccountID: schedule.FromAccountID,
+		ToAccountID:   schedule.ToAccountID,
+		Amount:        schedule.Amount,
+		InitiatedBy:   schedule.UserID,
+	})
+}
+
+func containsAccount(accounts []model.Account, accountID int64) bool {
+	for _, acc := range accounts {
+		if acc.ID == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleError is a permanent error of a schedule itself.
+type scheduleError struct {
+	message string
+}
+
+func (e *scheduleError) Error() string {
+	return e.message
+}
+
+// skipsOccurrence reports whether err only affects this occurrence, the next
+// one may well succeed once the balance or the limits allow it.
+func skipsOccurrence(err error) bool {
+	var limitErr *LimitExceededError
+	var ruleErr *ProductRuleError
+	return errors.Is(err, ErrInsufficientBalance) || errors.As(err, &limitErr) || errors.As(err, &ruleErr)
+}
+
+// isPermanent reports whether retrying err cannot succeed, every other error
+// is taken as transient.
+func isPermanent(err error) bool {
+	var statusErr *AccountStatusError
+	var scheduleErr *scheduleError
+	return errors.As(err, &statusErr) || errors.As(err, &scheduleErr) || errors.Is(err, ErrCurrencyMismatch)
+}
+
+func (u *ScheduledTransferUsecaseImpl) validate(schedule model.ScheduledTransfer) error {
+	switch schedule.Frequency {
+	case model.FrequencyOnce, model.FrequencyDaily, model.FrequencyWeekly, model.FrequencyMonthly:
+	default:
+		return fmt.Errorf("invalid frequency: %s", schedule.Frequency)
+	}
+
+	if schedule.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if schedule.FromAccountID == schedule.ToAccountID {
+		return errors.New("from_account_id and to_account_id must be different")
+	}
+	if schedule.MaxRuns < 0 {
+		return errors.New("max_runs must not be negative")
+	}
+	if !schedule.EndAt.IsZero() && schedule.EndAt.Before(schedule.StartAt) {
+		return errors.New("end_at must be after start_at")
+	}
+
+	fromacc, err := u.AccRepo.FindById(schedule.FromAccountID)
+	if err != nil {
+		return err
+	}
+	if fromacc.UserID != schedule.UserID {
+		return errors.New("from_account_id does not belong to the current user")
+	}
+
+	_, err = u.AccRepo.FindById(schedule.ToAccountID)
+	return err
+}
+
+// nextOccurrence returns the time of the n-th (zero based) occurrence of
+// schedule, or false when the schedule has no such occurrence.
+func nextOccurrence(schedule model.ScheduledTransfer, n int) (time.Time, bool) {
+	if schedule.MaxRuns > 0 && n >= schedule.MaxRuns {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch schedule.Frequency {
+	case model.FrequencyDaily:
+		next = schedule.StartAt.AddDate(0, 0, n)
+	case model.FrequencyWeekly:
+		next = schedule.StartAt.AddDate(0, 0, 7*n)
+	case model.FrequencyMonthly:
+		next = addMonths(schedule.StartAt, n)
+	default:
+		if n > 0 {
+			return time.Time{}, false
+		}
+		next = schedule.StartAt
+	}
+
+	if !schedule.EndAt.IsZero() && next.After(schedule.EndAt) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// addMonths adds n months to t, clamping the day to the end of the target
+// month so a transfer scheduled on the 31st runs on the 30th in April.
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	firstOfTarget := time.Date(year, month+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfTarget.AddDate(0, 0, day-1)
+}
+
+type LogScheduleNotifier struct{}
+
+// NotifySkipped implements ScheduleNotifier
+func (n LogScheduleNotifier) NotifySkipped(schedule model.ScheduledTransfer, execution model.ScheduledTransferExecution) {
+	log.Printf("scheduled transfer %d for user %d skipped: %s", schedule.ID, schedule.UserID, execution.Error)
+}
+
+func NewScheduledTransferUsecaseImpl(ScheduledTransferRepo repository.ScheduledTransferRepo, ExecutionRepo repository.ScheduledTransferExecutionRepo, AccountRepo repository.AccountRepo, TransferUsecase TransferUsecase, Notifier ScheduleNotifier, MaxRetries int, RetryDelay time.Duration) ScheduledTransferUsecase {
+	return &ScheduledTransferUsecaseImpl{
+		ScheduledTransferRepo: ScheduledTransferRepo,
+		ExecutionRepo:         ExecutionRepo,
+		AccRepo:               AccountRepo,
+		TransferUsecase:       TransferUsecase,
+		Notifier:              Notifier,
+		MaxRetries:            MaxRetries,
+		RetryDelay:            RetryDelay,
+	}
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type StatementUsecase interface {
+	Generate(accountID int64, from time.Time, to time.Time) (model.Statement, error)
+	GenerateMonthly(now time.Time) error
+}
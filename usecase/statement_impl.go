@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/report"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type StatementUsecaseImpl struct {
+	AccRepo  repository.AccountRepo
+	HisRepo  repository.HistoryRepo
+	UserRepo repository.UserRepo
+	BankCode string
+	Dir      string
+}
+
+// Generate implements StatementUsecase
+func (u *StatementUsecaseImpl) Generate(accountID int64, from time.Time, to time.Time) (model.Statement, error) {
+	if !from.Before(to) {
+		return model.Statement{}, errors.New("from must be before to")
+	}
+
+	acc, err := u.AccRepo.FindById(accountID)
+	if err != nil {
+		return model.Statement{}, err
+	}
+
+	statement := model.Statement{
+		AccountID:     acc.ID,
+		AccountNumber: acc.Number,
+		AccountType:   acc.Type,
+		BankCode:      u.BankCode,
+		Currency:      acc.CurrencyCode(),
+		From:          from,
+		To:            to,
+		GeneratedAt:   time.Now(),
+	}
+
+	user, err := u.UserRepo.FindById(acc.UserID)
+	if err == nil {
+		statement.AccountHolder = user.Username
+	}
+
+	historys, err := u.HisRepo.FindByAccountId(acc.ID)
+	if err != nil {
+		return model.Statement{}, err
+	}
+
+	sort.SliceStable(historys, func(i, j int) bool {
+		if historys[i].CreatedAt.Equal(historys[j].CreatedAt) {
+			return historys[i].ID < historys[j].ID
+		}
+		return historys[i].CreatedAt.Before(historys[j].CreatedAt)
+	})
+
+	// Saldo awal dihitung mundur dari saldo sekarang, jadi tetap benar untuk
+	// history lama yang belum punya balance_after
+	statement.OpeningBalance = acc.Balance
+	for _, history := range historys {
+		if !history.CreatedAt.Before(from) {
+			statement.OpeningBalance -= history.Amount
+		}
+	}
+
+	balance := statement.OpeningBalance
+	for _, history := range historys {
+		if history.CreatedAt.Before(from) || !history.CreatedAt.Before(to) {
+			continue
+		}
+
+		balance += history.Amount
+		if history.Type == "" {
+			history.BalanceAfter = balance
+		}
+		if history.Amount < 0 {
+			statement.TotalDebit += -history.Amount
+		} else {
+			statement.TotalCredit += history.Amount
+		}
+
+		// Data akun dan user tidak perlu diulang di setiap baris
+		history.Account = model.Account{}
+		statement.Transactions = append(statement.Transactions, history)
+	}
+	statement.ClosingBalance = balance
+
+	return statement, nil
+}
+
+// GenerateMonthly implements StatementUsecase
+func (u *StatementUsecaseImpl) GenerateMonthly(now time.Time) error {
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	from := to.AddDate(0, -1, 0)
+	dir := filepath.Join(u.Dir, from.Format("2006-01"))
+
+	accounts, err := u.AccRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, acc := range accounts {
+		if err := u.writeStatements(dir, acc.ID, from, to); err != nil {
+			log.Printf("statement for account %d: %v", acc.ID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d statements for %s failed", failed, len(accounts), from.Format("2006-01"))
+	}
+	return nil
+}
+
+// writeStatements writes every format of one statement into dir, files that
+// already exist are left alone so the job can run repeatedly.
+func (u *StatementUsecaseImpl) writeStatements(dir string, accountID int64, from time.Time, to time.Time) error {
+	var statement *model.Statement
+
+	for _, format := range []string{report.FormatPDF, report.FormatCSV} {
+		path := filepath.Join(dir, fmt.Sprintf("account_%d.%s", accountID, report.FileExtension(format)))
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		if statement == nil {
+			generated, err := u.Generate(accountID, from, to)
+			if err != nil {
+				return err
+			}
+			statement = &generated
+		}
+
+		if err := writeFileAtomic(path, func(f *os.File) error {
+			return report.WriteStatement(f, *statement, format)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes through a temporary file so a crash never leaves a
+// half written file at path.
+func writeFileAtomic(path string, write func(f *os.File) error) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func NewStatementUsecaseImpl(AccountRepo repository.AccountRepo, HisRepo repository.HistoryRepo, UserRepo repository.UserRepo, BankCode string, Dir string) StatementUsecase {
+	return &StatementUsecaseImpl{
+		AccRepo:  AccountRepo,
+		HisRepo:  HisRepo,
+		UserRepo: UserRepo,
+		BankCode: BankCode,
+		Dir:      Dir,
+	}
+}
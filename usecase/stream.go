@@ -0,0 +1,9 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/eventbus"
+
+// StreamUsecase subscribes a user to the events of their own accounts.
+type StreamUsecase interface {
+	Subscribe(userID int64, lastEventID string) (*eventbus.Subscription, error)
+	Unsubscribe(subscription *eventbus.Subscription)
+}
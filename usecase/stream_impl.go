@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"sync"
+
+	"github.com/sferawann/test_mnc/eventbus"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type StreamUsecaseImpl struct {
+	Bus     *eventbus.Bus
+	AccRepo repository.AccountRepo
+}
+
+// Subscribe implements StreamUsecase
+func (u *StreamUsecaseImpl) Subscribe(userID int64, lastEventID string) (*eventbus.Subscription, error) {
+	// FindByUserId gagal untuk user yang belum punya akun, stream tetap boleh dibuka
+	accounts, err := u.AccRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	owner := &accountOwner{userID: userID, accRepo: u.AccRepo, owned: map[int64]bool{}}
+	for _, acc := range accounts {
+		owner.owned[acc.ID] = acc.UserID == userID
+	}
+	return u.Bus.Subscribe(owner.matches, lastEventID), nil
+}
+
+// Unsubscribe implements StreamUsecase
+func (u *StreamUsecaseImpl) Unsubscribe(subscription *eventbus.Subscription) {
+	u.Bus.Unsubscribe(subscription)
+}
+
+// accountOwner matches the events that touch an account of userID. Accounts
+// opened after subscribing are looked up once and then remembered.
+type accountOwner struct {
+	mu      sync.Mutex
+	userID  int64
+	accRepo repository.AccountRepo
+	owned   map[int64]bool
+}
+
+func (o *accountOwner) matches(event model.Event) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, accountID := range event.AccountIDs {
+		owned, known := o.owned[accountID]
+		if !known {
+			acc, err := o.accRepo.FindById(accountID)
+			owned = err == nil && acc.UserID == o.userID
+			o.owned[accountID] = owned
+		}
+		if owned {
+			return true
+		}
+	}
+	return false
+}
+
+func NewStreamUsecaseImpl(Bus *eventbus.Bus, AccRepo repository.AccountRepo) StreamUsecase {
+	return &StreamUsecaseImpl{
+		Bus:     Bus,
+		AccRepo: AccRepo,
+	}
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// closeAndCheck closes acc into payout and checks that the whole balance was
+// paid out without a fee.
+func closeAndCheck(t *testing.T, b *testBank, acc model.Account, payout model.Account) {
+	t.Helper()
+	balance := b.balance(acc.ID)
+	payoutBalance := b.balance(payout.ID)
+
+	closed, err := b.accounts.Close(acc.ID, payout.ID, "customer request", 0)
+	if err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if closed.CurrentStatus() != model.AccountStatusClosed || closed.Balance != 0 {
+		t.Errorf("expected a closed empty account, got %s with %.2f", closed.CurrentStatus(), closed.Balance)
+	}
+	if got := b.balance(payout.ID); got != payoutBalance+balance {
+		t.Errorf("expected the payout account to receive %.2f, it has %.2f", balance, got-payoutBalance)
+	}
+	if got := b.balance(b.feeAccount.ID); got != 0 {
+		t.Errorf("expected no fee to be charged, fee account has %.2f", got)
+	}
+}
+
+func TestCloseWithFeeRule(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	b.feeRepo.Save(model.FeeRule{Name: "Transfer fee", Type: model.FeeTypeFlat, FlatFee: 6500})
+	alice := b.openAccount("alice", 1000000)
+	payout := b.openAccount("bobby", 100000)
+
+	closeAndCheck(t, b, alice, payout)
+}
+
+func TestCloseOverTransferLimit(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{MaxPerTransaction: 25000000}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	payout := b.openAccount("bobby", 100000)
+
+	closeAndCheck(t, b, alice, payout)
+	if approvals, _ := b.apprRepo.FindAll(); len(approvals) != 0 {
+		t.Errorf("expected the payout not to wait for approval, got %d approvals", len(approvals))
+	}
+}
+
+func TestCloseFrozenAccount(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	payout := b.openAccount("bobby", 100000)
+
+	if _, err := b.accounts.Freeze(alice.ID, "fraud check", 0); err != nil {
+		t.Fatalf("freeze failed: %v", err)
+	}
+	closeAndCheck(t, b, alice, payout)
+}
+
+func TestCloseDormantAccount(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	payout := b.openAccount("bobby", 100000)
+
+	if err := b.accounts.MarkDormant(time.Now().Add(48 * time.Hour)); err != nil {
+		t.Fatalf("mark dormant failed: %v", err)
+	}
+	if acc, _ := b.accRepo.FindById(alice.ID); acc.CurrentStatus() != model.AccountStatusDormant {
+		t.Fatalf("expected the account to be dormant, it is %s", acc.CurrentStatus())
+	}
+	// Akun payout ikut dormant, tapi kredit ke akun dormant tetap boleh
+	closeAndCheck(t, b, alice, payout)
+}
+
+func TestCloseRollsBackWhenPayoutFails(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	payout := b.openAccount("bobby", 100000)
+	if _, err := b.accounts.Close(payout.ID, alice.ID, "moving banks", 0); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if _, err := b.accounts.Close(alice.ID, payout.ID, "customer request", 0); err == nil {
+		t.Fatal("expected a payout into a closed account to fail")
+	}
+	acc, _ := b.accRepo.FindById(alice.ID)
+	if acc.CurrentStatus() != model.AccountStatusActive || acc.Balance != 1100000 {
+		t.Errorf("expected the account to stay open with 1,100,000, got %s with %.2f", acc.CurrentStatus(), acc.Balance)
+	}
+}
+
+func TestClosingPayoutOnlyThroughClose(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{MaxPerTransaction: 25000000}, 0)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 30000000, Purpose: model.TransferPurposeClosingPayout}); err == nil {
+		t.Fatal("expected a closing payout outside of close to be refused")
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func newTestProducts(b *testBank) usecase.AccountProductUsecase {
+	return usecase.NewAccountProductUsecaseImpl(repository.NewAccountProductRepoImpl(filepath.Join(b.dir, "account_product.json")), b.hisRepo)
+}
+
+// expectProductRule fails the test unless err is a ProductRuleError with code.
+func expectProductRule(t *testing.T, err error, code string) {
+	t.Helper()
+	var ruleErr *usecase.ProductRuleError
+	if !errors.As(err, &ruleErr) || ruleErr.Code != code {
+		t.Errorf("expected %s, got %v", code, err)
+	}
+}
+
+func TestCheckOpeningAppliesTheProductRules(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	products := newTestProducts(b)
+
+	if err := products.CheckOpening(model.Account{Type: model.AccountTypeSavings, Currency: "IDR", Balance: 50000}); err != nil {
+		t.Errorf("expected the minimum opening balance to be enough, got %v", err)
+	}
+	expectProductRule(t, products.CheckOpening(model.Account{Type: model.AccountTypeSavings, Currency: "IDR", Balance: 49999}), usecase.ProductErrMinimumBalance)
+
+	if err := products.CheckOpening(model.Account{Type: "gold", Currency: "IDR", Balance: 100000}); err == nil {
+		t.Error("expected an unknown account type to be rejected")
+	}
+	if err := products.CheckOpening(model.Account{Type: "foreign_savings", Currency: "IDR", Balance: 100000}); err == nil {
+		t.Error("expected a currency the product does not offer to be rejected")
+	}
+	if err := products.CheckOpening(model.Account{Type: "foreign_savings", Currency: "USD", Balance: 100}); err != nil {
+		t.Errorf("expected a foreign savings account in USD to open, got %v", err)
+	}
+}
+
+func TestCheckDebitKeepsTheMinimumBalance(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	products := newTestProducts(b)
+	savings := model.Account{ID: 10, Type: model.AccountTypeSavings, Balance: 100000}
+
+	if err := products.CheckDebit(savings, 50000); err != nil {
+		t.Errorf("expected a debit down to the minimum balance, got %v", err)
+	}
+	expectProductRule(t, products.CheckDebit(savings, 60000), usecase.ProductErrMinimumBalance)
+
+	alice := b.openAccount("alice", 100000)
+	if _, err := b.accounts.Withdraw(alice.ID, 60000, model.ChannelTeller, "", 0); err == nil {
+		t.Error("expected a withdrawal below the minimum balance to be rejected")
+	}
+	if got := b.balance(alice.ID); got != 100000 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+}
+
+func TestCheckDebitAllowsTheOverdraftOfChecking(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	products := newTestProducts(b)
+	checking := model.Account{ID: 10, Type: model.AccountTypeChecking, Balance: 1000000}
+
+	if err := products.CheckDebit(checking, 6000000); err != nil {
+		t.Errorf("expected the overdraft to cover 5,000,000, got %v", err)
+	}
+	if err := products.CheckDebit(checking, 6000001); !errors.Is(err, usecase.ErrInsufficientBalance) {
+		t.Errorf("expected a debit past the overdraft to be refused, got %v", err)
+	}
+}
+
+func TestCheckDebitAppliesLockInAndWithdrawalCount(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	products := newTestProducts(b)
+
+	deposit := model.Account{ID: 10, Type: "time_deposit", Balance: 20000000, CreatedAt: time.Now()}
+	expectProductRule(t, products.CheckDebit(deposit, 1000000), usecase.ProductErrLockedIn)
+
+	deposit.CreatedAt = time.Now().AddDate(0, 0, -91)
+	if err := products.CheckDebit(deposit, 1000000); err != nil {
+		t.Fatalf("expected a debit after the lock-in, got %v", err)
+	}
+
+	// Deposito hanya boleh satu kali penarikan per bulan
+	b.hisRepo.Save(model.History{AccountID: deposit.ID, Type: model.HistoryTypeWithdrawal, Amount: -1000000})
+	expectProductRule(t, products.CheckDebit(deposit, 1000000), usecase.ProductErrWithdrawalCount)
+}
+
+func TestUpdateChecksTheRulesOfTheNewAccountType(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 100000)
+
+	_, err := b.accounts.Update(model.Account{ID: alice.ID, Type: "time_deposit"})
+	expectProductRule(t, err, usecase.ProductErrMinimumBalance)
+	if _, err := b.accounts.Update(model.Account{ID: alice.ID, Type: "gold"}); err == nil {
+		t.Error("expected an unknown account type to be rejected")
+	}
+	if acc, _ := b.accRepo.FindById(alice.ID); acc.Type != model.AccountTypeSavings {
+		t.Errorf("expected the type to stay savings, got %s", acc.Type)
+	}
+
+	updated, err := b.accounts.Update(model.Account{ID: alice.ID, Type: model.AccountTypeChecking})
+	if err != nil {
+		t.Fatalf("expected the change to checking to be allowed, got %v", err)
+	}
+	if updated.Type != model.AccountTypeChecking || updated.Balance != 100000 {
+		t.Errorf("expected a checking account with the same balance, got %+v", updated)
+	}
+}
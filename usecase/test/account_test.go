@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func TestWithdrawAboveAvailableBalance(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+
+	// Saldo 1 juta, 600 ribu ditahan, jadi hanya 400 ribu yang tersedia
+	if _, err := b.holds.Place(model.Hold{AccountID: alice.ID, Amount: 600000}); err != nil {
+		t.Fatalf("place failed: %v", err)
+	}
+
+	if _, err := b.accounts.Withdraw(alice.ID, 500000, model.ChannelTeller, "", alice.UserID); !errors.Is(err, usecase.ErrInsufficientBalance) {
+		t.Fatalf("expected insufficient balance, got %v", err)
+	}
+	if got := b.balance(alice.ID); got != 1000000 {
+		t.Errorf("expected the balance to be unchanged, got %.2f", got)
+	}
+	if historys, _ := b.hisRepo.FindByAccountId(alice.ID); len(historys) != 1 {
+		t.Errorf("expected only the opening history, got %d rows", len(historys))
+	}
+}
+
+func TestWithdrawWithinAvailableBalance(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+
+	history, err := b.accounts.Withdraw(alice.ID, 300000, model.ChannelATM, "atm-001", alice.UserID)
+	if err != nil {
+		t.Fatalf("withdraw failed: %v", err)
+	}
+	if history.Type != model.HistoryTypeWithdrawal || history.Amount != -300000 || history.BalanceAfter != 700000 {
+		t.Errorf("unexpected history %+v", history)
+	}
+	if got := b.balance(alice.ID); got != 700000 {
+		t.Errorf("expected 300,000 to be withdrawn, balance is %.2f", got)
+	}
+
+	if _, err := b.accounts.Withdraw(alice.ID, 300000, model.ChannelATM, "atm-001", alice.UserID); err == nil {
+		t.Error("expected a repeated reference to be refused")
+	}
+}
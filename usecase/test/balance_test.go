@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func TestBalanceAt(t *testing.T) {
+	dir := t.TempDir()
+	accRepo := repository.NewAccountRepoImpl(filepath.Join(dir, "account.json"))
+	hisRepo := repository.NewHistoryRepoImpl(filepath.Join(dir, "history.json"))
+	dailyBalanceRepo := repository.NewDailyBalanceRepoImpl(filepath.Join(dir, "daily_balance.json"))
+	balances := usecase.NewBalanceUsecaseImpl(accRepo, hisRepo, dailyBalanceRepo)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	threeDaysAgo := today.AddDate(0, 0, -3)
+
+	acc, _ := accRepo.Save(model.Account{UserID: 1, Number: "4850010000001", Balance: 1250})
+	acc.CreatedAt = threeDaysAgo.Add(9 * time.Hour)
+	accRepo.Update(acc)
+
+	// Saldo dibuka tiga hari lalu, lalu satu transaksi tiap hari
+	rows := []struct {
+		amount float64
+		at     time.Time
+	}{
+		{1000, threeDaysAgo.Add(9 * time.Hour)},
+		{500, threeDaysAgo.AddDate(0, 0, 1).Add(10 * time.Hour)},
+		{-200, threeDaysAgo.AddDate(0, 0, 2).Add(11 * time.Hour)},
+		{-50, today.Add(-time.Minute)},
+	}
+	for _, row := range rows {
+		history, _ := hisRepo.Save(model.History{AccountID: acc.ID, Type: model.HistoryTypeDeposit, Amount: row.amount})
+		history.CreatedAt = row.at
+		hisRepo.Update(history)
+	}
+
+	if _, err := balances.BalanceAt(acc.ID, threeDaysAgo); err == nil {
+		t.Fatal("expected an error before the account was opened")
+	}
+
+	balance, err := balances.BalanceAt(acc.ID, threeDaysAgo.AddDate(0, 0, 1).Add(12*time.Hour))
+	if err != nil || balance.Balance != 1500 || balance.Transactions != 2 || balance.SnapshotDate != "" {
+		t.Fatalf("expected 1500 from 2 rows, got %+v, %v", balance, err)
+	}
+
+	if err := balances.SnapshotDaily(now); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	yesterday := today.AddDate(0, 0, -1).Format(model.DailyBalanceDateLayout)
+	snapshots, _ := dailyBalanceRepo.FindByDate(yesterday)
+	if len(snapshots) != 1 || snapshots[0].Balance != 1250 {
+		t.Fatalf("expected yesterday's closing balance of 1250, got %+v", snapshots)
+	}
+
+	// Snapshot harian dipakai, hanya baris setelahnya yang dijumlah
+	balance, err = balances.BalanceAt(acc.ID, now)
+	if err != nil || balance.Balance != 1250 || balance.SnapshotDate != yesterday || balance.Transactions != 0 {
+		t.Fatalf("expected 1250 from yesterday's snapshot, got %+v, %v", balance, err)
+	}
+
+	eod, err := balances.EndOfDay(threeDaysAgo.AddDate(0, 0, 1))
+	if err != nil || len(eod) != 1 || eod[0].Balance != 1500 {
+		t.Fatalf("expected an end of day balance of 1500, got %+v, %v", eod, err)
+	}
+	if _, err := balances.EndOfDay(today); err == nil {
+		t.Fatal("expected today's end of day balances to be unavailable")
+	}
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+// testBank wires the money moving usecases the way main does, on
+// repositories in a temporary directory.
+type testBank struct {
+	t   *testing.T
+	dir string
+
+	userRepo repository.UserRepo
+	accRepo  repository.AccountRepo
+	hisRepo  repository.HistoryRepo
+	traRepo  repository.TransferRepo
+	apprRepo repository.TransferApprovalRepo
+	feeRepo  repository.FeeRuleRepo
+	holdRepo repository.HoldRepo
+	reqRepo  repository.PaymentRequestRepo
+	audit    usecase.AuditUsecase
+
+	feeAccount model.Account
+	approver   model.User
+
+	transfers usecase.TransferUsecase
+	accounts  usecase.AccountUsecase
+	approvals usecase.TransferApprovalUsecase
+	holds     usecase.HoldUsecase
+	requests  usecase.PaymentRequestUsecase
+}
+
+func newTestBank(t *testing.T, limit model.TransferLimit, approvalThreshold float64) *testBank {
+	dir := t.TempDir()
+	products, err := os.ReadFile(filepath.Join("..", "..", "json", "account_product.json"))
+	if err != nil {
+		t.Fatalf("failed to read account products: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "account_product.json"), products, 0644); err != nil {
+		t.Fatalf("failed to write account products: %v", err)
+	}
+
+	b := &testBank{
+		t:        t,
+		dir:      dir,
+		userRepo: repository.NewUserRepoImpl(filepath.Join(dir, "user.json")),
+		accRepo:  repository.NewAccountRepoImpl(filepath.Join(dir, "account.json")),
+		hisRepo:  repository.NewHistoryRepoImpl(filepath.Join(dir, "history.json")),
+		traRepo:  repository.NewTransferRepoImpl(filepath.Join(dir, "transfer.json")),
+		apprRepo: repository.NewTransferApprovalRepoImpl(filepath.Join(dir, "transfer_approval.json")),
+		feeRepo:  repository.NewFeeRuleRepoImpl(filepath.Join(dir, "fee_rule.json")),
+		holdRepo: repository.NewHoldRepoImpl(filepath.Join(dir, "hold.json")),
+		reqRepo:  repository.NewPaymentRequestRepoImpl(filepath.Join(dir, "payment_request.json")),
+		audit:    usecase.NewAuditUsecaseImpl(repository.NewAuditLogRepoImpl(filepath.Join(dir, "audit_log.json"))),
+	}
+
+	bank, _ := b.userRepo.Save(model.User{Username: "bank", Role: model.RoleAdmin})
+	b.feeAccount, _ = b.accRepo.Save(model.Account{UserID: bank.ID, Number: "4850019999999", Type: model.AccountTypeChecking})
+	b.approver, _ = b.userRepo.Save(model.User{Username: "approver", Role: model.RoleApprover})
+
+	hisRepo := b.hisRepo
+	productUsecase := usecase.NewAccountProductUsecaseImpl(repository.NewAccountProductRepoImpl(filepath.Join(dir, "account_product.json")), hisRepo)
+	b.transfers = usecase.NewTransferUsecaseImpl(usecase.TransferUsecaseConfig{
+		TransferRepo:      b.traRepo,
+		AccRepo:           b.accRepo,
+		UserRepo:          b.userRepo,
+		HisRepo:           hisRepo,
+		LimitUsecase:      usecase.NewTransferLimitUsecaseImpl(repository.NewTransferLimitRepoImpl(filepath.Join(dir, "transfer_limit.json")), hisRepo, b.traRepo, limit),
+		FeeUsecase:        usecase.NewFeeUsecaseImpl(b.feeRepo),
+		FeeAccountID:      b.feeAccount.ID,
+		ApprovalRepo:      b.apprRepo,
+		AuditUsecase:      b.audit,
+		ApprovalThreshold: approvalThreshold,
+		ApprovalTTL:       time.Hour,
+		ProductUsecase:    productUsecase,
+		QuoteRepo:         repository.NewFxQuoteRepoImpl(filepath.Join(dir, "fx_quote.json")),
+		HoldRepo:          b.holdRepo,
+		RequestRepo:       b.reqRepo,
+	})
+	b.accounts = usecase.NewAccountUsecaseImpl(usecase.AccountUsecaseConfig{
+		AccountRepo:     b.accRepo,
+		UserRepo:        b.userRepo,
+		HisRepo:         hisRepo,
+		Audit:           b.audit,
+		TransferUsecase: b.transfers,
+		ProductUsecase:  productUsecase,
+		DormantAfter:    24 * time.Hour,
+		BankCode:        "485",
+		BranchCode:      "001",
+	})
+	b.approvals = usecase.NewTransferApprovalUsecaseImpl(b.apprRepo, b.traRepo, b.userRepo, b.transfers, b.audit)
+	b.holds = usecase.NewHoldUsecaseImpl(b.holdRepo, b.accRepo, b.transfers, time.Hour)
+	b.requests = usecase.NewPaymentRequestUsecaseImpl(b.reqRepo, b.accRepo, b.userRepo, b.transfers, b.audit, time.Hour)
+	return b
+}
+
+// openAccount registers username and opens a savings account with balance.
+func (b *testBank) openAccount(username string, balance float64) model.Account {
+	user, err := b.userRepo.Save(model.User{Username: username, Role: model.RoleUser})
+	if err != nil {
+		b.t.Fatalf("failed to register %s: %v", username, err)
+	}
+	acc, err := b.accounts.Save(model.Account{UserID: user.ID, Balance: balance})
+	if err != nil {
+		b.t.Fatalf("failed to open an account for %s: %v", username, err)
+	}
+	return acc
+}
+
+// balance returns the stored balance of the account.
+func (b *testBank) balance(accountID int64) float64 {
+	acc, err := b.accRepo.FindById(accountID)
+	if err != nil {
+		b.t.Fatalf("failed to find account %d: %v", accountID, err)
+	}
+	return acc.Balance
+}
+
+// held returns the stored held balance of the account.
+func (b *testBank) held(accountID int64) float64 {
+	acc, err := b.accRepo.FindById(accountID)
+	if err != nil {
+		b.t.Fatalf("failed to find account %d: %v", accountID, err)
+	}
+	return acc.HeldBalance
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func TestLargeTransfersToANewBeneficiaryWaitForTheCoolingOff(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 10000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	beneficiaryRepo := repository.NewBeneficiaryRepoImpl(filepath.Join(b.dir, "beneficiary.json"))
+	beneficiaries := usecase.NewBeneficiaryUsecaseImpl(beneficiaryRepo, b.accRepo, 24*time.Hour, 5000000)
+
+	beneficiary, err := beneficiaries.Save(model.Beneficiary{UserID: alice.UserID, Nickname: "Bobby", AccountNumber: bobby.Number})
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	if err := beneficiaries.CheckTransfer(beneficiary, 5000000); err != nil {
+		t.Errorf("expected transfers up to the cooling-off amount to be allowed, got %v", err)
+	}
+
+	var coolingOff *usecase.CoolingOffError
+	err = beneficiaries.CheckTransfer(beneficiary, 5000001)
+	if !errors.As(err, &coolingOff) {
+		t.Fatalf("expected a cooling-off error, got %v", err)
+	}
+	if want := beneficiary.CreatedAt.Add(24 * time.Hour); !coolingOff.Until.Equal(want) {
+		t.Errorf("expected large transfers from %v, got %v", want, coolingOff.Until)
+	}
+
+	// Setelah masa tunggu lewat transfer besar diperbolehkan
+	beneficiary.CreatedAt = time.Now().Add(-25 * time.Hour)
+	if err := beneficiaries.CheckTransfer(beneficiary, 5000001); err != nil {
+		t.Errorf("expected the cooling-off to be over, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func TestFeeQuoteAppliesTheMatchingTier(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	b.feeRepo.Save(model.FeeRule{
+		Name: "Tiered",
+		Type: model.FeeTypeTiered,
+		Tiers: []model.FeeTier{
+			{UpTo: 1000000, Fee: 2500},
+			{UpTo: 10000000, Fee: 5000},
+			{Fee: 6500},
+		},
+	})
+	fees := usecase.NewFeeUsecaseImpl(b.feeRepo)
+
+	from := model.Account{ID: 1, UserID: 1, Type: model.AccountTypeSavings}
+	to := model.Account{ID: 2, UserID: 2, Type: model.AccountTypeSavings}
+	for amount, want := range map[float64]float64{500000: 2500, 1000000: 2500, 1000001: 5000, 25000000: 6500} {
+		quote, err := fees.Quote(from, to, amount)
+		if err != nil {
+			t.Fatalf("quote failed: %v", err)
+		}
+		if quote.Fee != want || quote.Total != amount+want {
+			t.Errorf("amount %.2f: expected a fee of %.2f, got %.2f with total %.2f", amount, want, quote.Fee, quote.Total)
+		}
+	}
+}
+
+func TestFeeQuoteIsFreeBetweenAccountsOfTheSameOwner(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	rule, _ := b.feeRepo.Save(model.FeeRule{Name: "Flat", Type: model.FeeTypeFlat, FlatFee: 6500, FreeForSameOwner: true})
+	fees := usecase.NewFeeUsecaseImpl(b.feeRepo)
+
+	from := model.Account{ID: 1, UserID: 1, Type: model.AccountTypeSavings}
+	own := model.Account{ID: 2, UserID: 1, Type: model.AccountTypeChecking}
+	other := model.Account{ID: 3, UserID: 2, Type: model.AccountTypeSavings}
+
+	quote, err := fees.Quote(from, own, 100000)
+	if err != nil {
+		t.Fatalf("quote failed: %v", err)
+	}
+	if quote.Fee != 0 || quote.FeeRuleID != rule.ID {
+		t.Errorf("expected rule %d to waive the fee, got %+v", rule.ID, quote)
+	}
+
+	quote, err = fees.Quote(from, other, 100000)
+	if err != nil {
+		t.Fatalf("quote failed: %v", err)
+	}
+	if quote.Fee != 6500 {
+		t.Errorf("expected a fee of 6,500 to another owner, got %.2f", quote.Fee)
+	}
+}
+
+func TestTransferFeeIsPostedAndRefundedOnReverse(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	b.feeRepo.Save(model.FeeRule{Name: "Flat", Type: model.FeeTypeFlat, FlatFee: 6500})
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	transfer, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000, InitiatedBy: alice.UserID})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	if transfer.Fee != 6500 {
+		t.Errorf("expected a fee of 6,500, got %.2f", transfer.Fee)
+	}
+	if got := b.balance(alice.ID); got != 893500 {
+		t.Errorf("expected the amount and the fee to be debited, balance is %.2f", got)
+	}
+	if got := b.balance(b.feeAccount.ID); got != 6500 {
+		t.Errorf("expected the fee account to be credited, balance is %.2f", got)
+	}
+
+	if _, err := b.transfers.Reverse(transfer.ID); err != nil {
+		t.Fatalf("reverse failed: %v", err)
+	}
+	if got := b.balance(alice.ID); got != 1000000 {
+		t.Errorf("expected the amount and the fee to be refunded, balance is %.2f", got)
+	}
+	if got := b.balance(bobby.ID); got != 100000 {
+		t.Errorf("expected the credit to be taken back, balance is %.2f", got)
+	}
+	if got := b.balance(b.feeAccount.ID); got != 0 {
+		t.Errorf("expected the fee to be taken back from the fee account, balance is %.2f", got)
+	}
+}
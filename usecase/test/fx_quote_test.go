@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/fx"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+// newTestQuotes quotes on the quote repo the transfers of b read.
+func newTestQuotes(b *testBank) (usecase.FxQuoteUsecase, repository.FxQuoteRepo) {
+	quoteRepo := repository.NewFxQuoteRepoImpl(filepath.Join(b.dir, "fx_quote.json"))
+	provider := fx.NewStaticProvider(filepath.Join("..", "..", "json", "fx_rate.json"))
+	return usecase.NewFxQuoteUsecaseImpl(quoteRepo, b.accRepo, provider, 0.5, time.Minute), quoteRepo
+}
+
+// openForeignAccount opens a foreign savings account in currency for username.
+func (b *testBank) openForeignAccount(username string, currency string) model.Account {
+	user, _ := b.userRepo.Save(model.User{Username: username, Role: model.RoleUser})
+	acc, err := b.accounts.Save(model.Account{UserID: user.ID, Type: "foreign_savings", Currency: currency, Balance: 100})
+	if err != nil {
+		b.t.Fatalf("failed to open a %s account for %s: %v", currency, username, err)
+	}
+	return acc
+}
+
+func TestFxQuoteCanOnlyBeUsedOnce(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 10000000)
+	bobby := b.openForeignAccount("bobby", "USD")
+	quotes, _ := newTestQuotes(b)
+
+	quote, err := quotes.Quote(alice.UserID, alice.ID, "USD", 1000000)
+	if err != nil {
+		t.Fatalf("quote failed: %v", err)
+	}
+
+	transfer, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 1000000, QuoteID: quote.ID})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	if transfer.TargetAmount != quote.TargetAmount || b.balance(bobby.ID) != 100+quote.TargetAmount {
+		t.Errorf("expected %.2f USD to be credited, got %.2f", quote.TargetAmount, transfer.TargetAmount)
+	}
+
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 1000000, QuoteID: quote.ID}); err == nil {
+		t.Error("expected a used quote to be rejected")
+	}
+	if got := b.balance(alice.ID); got != 9000000 {
+		t.Errorf("expected only the first conversion to be debited, balance is %.2f", got)
+	}
+}
+
+func TestExpiredFxQuoteIsRejected(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 10000000)
+	bobby := b.openForeignAccount("bobby", "USD")
+	quotes, quoteRepo := newTestQuotes(b)
+
+	quote, err := quotes.Quote(alice.UserID, alice.ID, "USD", 1000000)
+	if err != nil {
+		t.Fatalf("quote failed: %v", err)
+	}
+	quote.ExpiresAt = time.Now().Add(-time.Second)
+	quoteRepo.Update(quote)
+
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 1000000, QuoteID: quote.ID}); err == nil {
+		t.Error("expected an expired quote to be rejected")
+	}
+	if got := b.balance(bobby.ID); got != 100 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+}
+
+func TestFxQuoteMustMatchTheTransfer(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 10000000)
+	carol := b.openAccount("carol", 10000000)
+	bobby := b.openForeignAccount("bobby", "USD")
+	dave := b.openForeignAccount("dave", "SGD")
+	quotes, _ := newTestQuotes(b)
+
+	quote, err := quotes.Quote(alice.UserID, alice.ID, "USD", 1000000)
+	if err != nil {
+		t.Fatalf("quote failed: %v", err)
+	}
+
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: carol.ID, ToAccountID: bobby.ID, Amount: 1000000, QuoteID: quote.ID}); err == nil {
+		t.Error("expected a quote of another account to be rejected")
+	}
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: dave.ID, Amount: 1000000, QuoteID: quote.ID}); err == nil {
+		t.Error("expected a quote for another currency pair to be rejected")
+	}
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 2000000, QuoteID: quote.ID}); err == nil {
+		t.Error("expected a quote for another amount to be rejected")
+	}
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 1000000}); err == nil {
+		t.Error("expected a conversion without a quote to be rejected")
+	}
+
+	if got := b.balance(alice.ID); got != 10000000 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+	if stored, _ := quotes.FindById(quote.ID); stored.TransferID != 0 {
+		t.Errorf("expected the quote to stay unused, got transfer %d", stored.TransferID)
+	}
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+func TestHoldIsCapturedOnce(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	hold, err := b.holds.Place(model.Hold{AccountID: alice.ID, Amount: 600000})
+	if err != nil {
+		t.Fatalf("place failed: %v", err)
+	}
+
+	captured, err := b.holds.Capture(hold.ID, 500000, bobby.ID)
+	if err != nil || captured.Status != model.HoldStatusCaptured || captured.TransferID == 0 {
+		t.Fatalf("expected the hold to be captured, got %+v, %v", captured, err)
+	}
+	if _, err := b.holds.Capture(hold.ID, 500000, bobby.ID); err == nil {
+		t.Fatal("expected a captured hold not to be captured again")
+	}
+	if _, err := b.holds.Release(hold.ID); err == nil {
+		t.Fatal("expected a captured hold not to be released")
+	}
+
+	if got := b.balance(alice.ID); got != 500000 {
+		t.Errorf("expected 500,000 to be debited once, balance is %.2f", got)
+	}
+	if got := b.held(alice.ID); got != 0 {
+		t.Errorf("expected the rest of the hold to be released, held is %.2f", got)
+	}
+}
+
+func TestHoldStaysActiveWhileCaptureWaitsForApproval(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	hold, _ := b.holds.Place(model.Hold{AccountID: alice.ID, Amount: 12000000})
+	pending, err := b.holds.Capture(hold.ID, 0, bobby.ID)
+	if err != nil {
+		t.Fatalf("capture failed: %v", err)
+	}
+	if pending.Status != model.HoldStatusActive || pending.TransferID == 0 {
+		t.Fatalf("expected the hold to stay active for the pending transfer, got %+v", pending)
+	}
+	if got := b.held(alice.ID); got != 12000000 {
+		t.Errorf("expected the funds to stay held, held is %.2f", got)
+	}
+
+	if _, err := b.holds.Capture(hold.ID, 0, bobby.ID); err == nil {
+		t.Fatal("expected a second capture to be refused while the first waits for approval")
+	}
+	if _, err := b.holds.Release(hold.ID); err == nil {
+		t.Fatal("expected a hold with a pending capture not to be released")
+	}
+
+	approval, _ := b.apprRepo.FindByTransferId(pending.TransferID)
+	if _, err := b.approvals.Approve(approval.ID, b.approver.ID); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+
+	captured, _ := b.holdRepo.FindById(hold.ID)
+	if captured.Status != model.HoldStatusCaptured {
+		t.Errorf("expected the hold to be captured after approval, got %s", captured.Status)
+	}
+	if got := b.held(alice.ID); got != 0 {
+		t.Errorf("expected nothing to stay held, held is %.2f", got)
+	}
+	if got := b.balance(alice.ID); got != 18000000 {
+		t.Errorf("expected 12,000,000 to be debited, balance is %.2f", got)
+	}
+}
+
+func TestRejectedCaptureFreesTheHold(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	hold, _ := b.holds.Place(model.Hold{AccountID: alice.ID, Amount: 12000000})
+	pending, err := b.holds.Capture(hold.ID, 0, bobby.ID)
+	if err != nil {
+		t.Fatalf("capture failed: %v", err)
+	}
+
+	approval, _ := b.apprRepo.FindByTransferId(pending.TransferID)
+	if _, err := b.approvals.Reject(approval.ID, b.approver.ID, "not expected"); err != nil {
+		t.Fatalf("reject failed: %v", err)
+	}
+
+	freed, _ := b.holdRepo.FindById(hold.ID)
+	if freed.Status != model.HoldStatusActive || freed.TransferID != 0 {
+		t.Fatalf("expected the hold to be active and unlinked, got %+v", freed)
+	}
+	if _, err := b.holds.Release(hold.ID); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if got := b.held(alice.ID); got != 0 {
+		t.Errorf("expected the funds to be released, held is %.2f", got)
+	}
+	if got := b.balance(alice.ID); got != 30000000 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+}
+
+func TestReleaseDoesNotHideAMissingHeldBalance(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+
+	hold, _ := b.holds.Place(model.Hold{AccountID: alice.ID, Amount: 600000})
+
+	// Held balance dikurangi di luar hold, release tidak boleh menutupinya
+	acc, _ := b.accRepo.FindById(alice.ID)
+	acc.HeldBalance = 100000
+	b.accRepo.Update(acc)
+
+	if _, err := b.holds.Release(hold.ID); err == nil {
+		t.Fatal("expected releasing more than is held to fail")
+	}
+	if still, _ := b.holdRepo.FindById(hold.ID); still.Status != model.HoldStatusActive {
+		t.Errorf("expected the hold to stay active, got %s", still.Status)
+	}
+}
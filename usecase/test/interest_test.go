@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+// newTestInterest wires an interest usecase paying from a fresh expense
+// account with a 20% withholding tax.
+func newTestInterest(b *testBank) (usecase.InterestUsecase, repository.InterestAccrualRepo, model.Account) {
+	bank, _ := b.userRepo.FindByUsername("bank")
+	expense, err := b.accRepo.Save(model.Account{UserID: bank.ID, Number: "4850019999998", Type: model.AccountTypeChecking, Balance: 1000000000})
+	if err != nil {
+		b.t.Fatalf("failed to open the expense account: %v", err)
+	}
+
+	accrualRepo := repository.NewInterestAccrualRepoImpl(filepath.Join(b.dir, "interest_accrual.json"))
+	productRepo := repository.NewAccountProductRepoImpl(filepath.Join(b.dir, "account_product.json"))
+	interest := usecase.NewInterestUsecaseImpl(b.accRepo, b.hisRepo, accrualRepo, productRepo, b.audit, expense.ID, 20, nil, nil)
+	return interest, accrualRepo, expense
+}
+
+// backdate moves the opening of the account and its history to since, so
+// past days can be accrued.
+func (b *testBank) backdate(acc model.Account, since time.Time) {
+	acc, _ = b.accRepo.FindById(acc.ID)
+	acc.CreatedAt = since
+	if _, err := b.accRepo.Update(acc); err != nil {
+		b.t.Fatalf("failed to backdate account %d: %v", acc.ID, err)
+	}
+
+	historys, _ := b.hisRepo.FindByAccountId(acc.ID)
+	for _, history := range historys {
+		history.CreatedAt = since
+		if _, err := b.hisRepo.Update(history); err != nil {
+			b.t.Fatalf("failed to backdate history %d: %v", history.ID, err)
+		}
+	}
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+func TestInterestAccruesEveryDayOnceWithTheDayCountOfTheProduct(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby, _ := b.userRepo.Save(model.User{Username: "bobby", Role: model.RoleUser})
+	checking, err := b.accounts.Save(model.Account{UserID: bobby.ID, Type: model.AccountTypeChecking, Balance: 2000000})
+	if err != nil {
+		t.Fatalf("failed to open a checking account: %v", err)
+	}
+	interest, _, _ := newTestInterest(b)
+
+	from := today().AddDate(0, 0, -3)
+	b.backdate(alice, from.Add(time.Hour))
+	b.backdate(checking, from.Add(time.Hour))
+
+	accruals, err := interest.Accrue(from, today())
+	if err != nil {
+		t.Fatalf("accrue failed: %v", err)
+	}
+	if len(accruals) != 6 {
+		t.Fatalf("expected 3 days for 2 accounts, got %d accruals", len(accruals))
+	}
+
+	// Tabungan memakai ACT/365 2.5%, giro memakai ACT/360 0.5%
+	for _, accrual := range accruals {
+		want := 1000000 * 2.5 / 100 / 365
+		if accrual.AccountID == checking.ID {
+			want = 2000000 * 0.5 / 100 / 360
+		}
+		if math.Abs(accrual.Amount-want) > 1e-9 {
+			t.Errorf("account %d on %s: expected %.6f, got %.6f", accrual.AccountID, accrual.Date, want, accrual.Amount)
+		}
+	}
+
+	replayed, err := interest.Accrue(from, today())
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if len(replayed) != 0 {
+		t.Errorf("expected a replay to accrue nothing, got %d accruals", len(replayed))
+	}
+}
+
+func TestInterestPostingWithholdsTax(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	interest, accrualRepo, expense := newTestInterest(b)
+
+	month := time.Date(today().Year(), today().Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+	b.backdate(alice, month.Add(time.Hour))
+
+	accruals, err := interest.Accrue(month, month.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("accrue failed: %v", err)
+	}
+	var gross float64
+	for _, accrual := range accruals {
+		gross += accrual.Amount
+	}
+	gross = math.Round(gross*100) / 100
+	tax := math.Round(gross*20) / 100
+
+	historys, err := interest.Post(month, 0)
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	if len(historys) != 1 || historys[0].Amount != gross {
+		t.Fatalf("expected one posting of %.2f, got %+v", gross, historys)
+	}
+
+	if got := b.balance(alice.ID); math.Abs(got-(1000000+gross-tax)) > 0.001 {
+		t.Errorf("expected %.2f net of tax, balance is %.2f", gross-tax, got-1000000)
+	}
+	if got := b.balance(expense.ID); math.Abs(got-(1000000000-(gross-tax))) > 0.001 {
+		t.Errorf("expected the expense account to pay %.2f, balance is %.2f", gross-tax, got)
+	}
+
+	var taxed bool
+	aliceHistorys, _ := b.hisRepo.FindByAccountId(alice.ID)
+	for _, history := range aliceHistorys {
+		if history.Type == model.HistoryTypeTax && math.Abs(history.Amount+tax) < 0.001 {
+			taxed = true
+		}
+	}
+	if !taxed {
+		t.Errorf("expected a withholding tax history of %.2f", -tax)
+	}
+
+	for _, accrual := range mustFindAccruals(t, accrualRepo, alice.ID) {
+		if !accrual.Posted() || accrual.HistoryID != historys[0].ID {
+			t.Errorf("expected accrual %s to be posted by history %d, got %+v", accrual.Date, historys[0].ID, accrual)
+		}
+	}
+
+	again, err := interest.Post(month, 0)
+	if err != nil {
+		t.Fatalf("second post failed: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("expected a month to be posted only once, got %d postings", len(again))
+	}
+}
+
+func TestRunScheduledCatchesUpMissedDaysAndPostsClosedMonths(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	interest, accrualRepo, _ := newTestInterest(b)
+
+	// Layanan terakhir mengakru 35 hari yang lalu, lalu mati
+	since := today().AddDate(0, 0, -40)
+	b.backdate(alice, since.Add(time.Hour))
+	lastRun := today().AddDate(0, 0, -35)
+	if _, err := interest.Accrue(lastRun, lastRun.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("accrue failed: %v", err)
+	}
+
+	if err := interest.RunScheduled(time.Now()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	accruals := mustFindAccruals(t, accrualRepo, alice.ID)
+	if len(accruals) != 35 {
+		t.Fatalf("expected every day since the last run to be accrued, got %d accruals", len(accruals))
+	}
+
+	current := today().Format("2006-01")
+	postings := map[int64]bool{}
+	for _, accrual := range accruals {
+		closed := accrual.Date[:7] < current
+		if accrual.Posted() != closed {
+			t.Errorf("accrual %s: expected posted to be %v", accrual.Date, closed)
+		}
+		if accrual.Posted() {
+			postings[accrual.HistoryID] = true
+		}
+	}
+	if len(postings) == 0 {
+		t.Fatal("expected the closed months to be posted")
+	}
+
+	balance := b.balance(alice.ID)
+	historys, _ := b.hisRepo.FindAll()
+	if err := interest.RunScheduled(time.Now()); err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if got := len(mustFindAccruals(t, accrualRepo, alice.ID)); got != 35 {
+		t.Errorf("expected a second run to accrue nothing, got %d accruals", got)
+	}
+	if again, _ := b.hisRepo.FindAll(); len(again) != len(historys) || b.balance(alice.ID) != balance {
+		t.Errorf("expected a second run to post nothing, got %d historys instead of %d", len(again), len(historys))
+	}
+}
+
+func mustFindAccruals(t *testing.T, accrualRepo repository.InterestAccrualRepo, accountID int64) []model.InterestAccrual {
+	accruals, err := accrualRepo.FindByAccountId(accountID)
+	if err != nil {
+		t.Fatalf("failed to load accruals: %v", err)
+	}
+	return accruals
+}
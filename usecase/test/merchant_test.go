@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+// newTestMerchant registers the account of owner as a merchant, frozen
+// merchant accounts cannot receive payments.
+func newTestMerchant(b *testBank, owner model.Account) (usecase.MerchantUsecase, model.Merchant) {
+	merchantRepo := repository.NewMerchantRepoImpl(filepath.Join(b.dir, "merchant.json"))
+	merchants := usecase.NewMerchantUsecaseImpl(merchantRepo, b.accRepo, b.traRepo, b.transfers, b.audit, true)
+
+	merchant, err := merchants.Save(model.Merchant{UserID: owner.UserID, AccountID: owner.ID, Name: "Warung Bobby", City: "Jakarta"})
+	if err != nil {
+		b.t.Fatalf("failed to register the merchant: %v", err)
+	}
+	return merchants, merchant
+}
+
+func TestDynamicQRCanOnlyBePaidOnce(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+	merchants, merchant := newTestMerchant(b, bobby)
+
+	qr, err := merchants.GenerateQR(merchant.ID, 50000, "INV-1")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	if _, err := merchants.Pay(alice.UserID, model.QRPayment{Payload: qr.Payload, FromAccountID: alice.ID}); err != nil {
+		t.Fatalf("first payment failed: %v", err)
+	}
+	if _, err := merchants.Pay(alice.UserID, model.QRPayment{Payload: qr.Payload, FromAccountID: alice.ID}); err == nil {
+		t.Error("expected the second payment of the bill to be rejected")
+	}
+
+	if got := b.balance(bobby.ID); got != 150000 {
+		t.Errorf("expected the bill to be credited once, balance is %.2f", got)
+	}
+	if payments, _ := merchants.FindPayments(merchant.ID); len(payments) != 1 {
+		t.Errorf("expected one payment, got %d", len(payments))
+	}
+}
+
+func TestGenerateQRRejectsMerchantAccountsThatCannotReceive(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	bobby := b.openAccount("bobby", 100000)
+	merchants, merchant := newTestMerchant(b, bobby)
+
+	if _, err := b.accounts.Freeze(bobby.ID, "investigation", 0); err != nil {
+		t.Fatalf("freeze failed: %v", err)
+	}
+
+	var statusErr *usecase.AccountStatusError
+	if _, err := merchants.GenerateQR(merchant.ID, 50000, "INV-1"); !errors.As(err, &statusErr) || statusErr.Status != model.AccountStatusFrozen {
+		t.Errorf("expected a frozen merchant account to be refused, got %v", err)
+	}
+	if _, err := merchants.GenerateQR(merchant.ID, 0, ""); !errors.As(err, &statusErr) {
+		t.Errorf("expected a static QR to be refused as well, got %v", err)
+	}
+}
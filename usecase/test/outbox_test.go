@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/broker"
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func TestOutboxRelay(t *testing.T) {
+	dir := t.TempDir()
+	userRepo := repository.NewUserRepoImpl(filepath.Join(dir, "user.json"))
+	outboxRepo := repository.NewOutboxEventRepoImpl(filepath.Join(dir, "outbox_event.json"))
+	userUsecase := usecase.NewUserUsecaseImpl(userRepo, outboxRepo)
+
+	memory := broker.NewMemory(0)
+	relay := usecase.NewOutboxUsecaseImpl(outboxRepo, memory, "bank", 10, time.Minute, time.Hour)
+
+	for _, username := range []string{"alice", "bobby"} {
+		if _, err := userUsecase.Save(model.User{Username: username, Password: "secret123", Email: username + "@example.com"}); err != nil {
+			t.Fatalf("failed to register %s: %v", username, err)
+		}
+	}
+
+	// Broker sedang mati, event tetap pending dan dicoba lagi setelah backoff
+	now := time.Now()
+	memory.Fail(errors.New("broker down"))
+	if err := relay.Relay(now); err == nil {
+		t.Fatal("expected the relay to fail")
+	}
+	pending, _ := relay.FindAll(model.OutboxStatusPending)
+	if len(pending) != 2 || pending[0].Attempts != 1 || pending[0].LastError != "broker down" || pending[1].Attempts != 0 {
+		t.Fatalf("expected only the first event to be attempted, got %+v", pending)
+	}
+
+	memory.Fail(nil)
+	if err := relay.Relay(now); err != nil || len(memory.Messages()) != 0 {
+		t.Fatalf("expected the relay to wait for the backoff, got %v and %d messages", err, len(memory.Messages()))
+	}
+
+	if err := relay.Relay(now.Add(time.Minute)); err != nil {
+		t.Fatalf("relay failed: %v", err)
+	}
+	messages := memory.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(messages))
+	}
+
+	var message struct {
+		ID      string               `json:"id"`
+		Type    string               `json:"type"`
+		Payload model.UserRegistered `json:"payload"`
+	}
+	if err := json.Unmarshal(messages[0].Body, &message); err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	if messages[0].Topic != "bank.user" || messages[0].Key != "1" || message.Type != model.DomainEventUserRegistered || message.Payload.Username != "alice" {
+		t.Errorf("unexpected first message: %s %s %s", messages[0].Topic, messages[0].Key, messages[0].Body)
+	}
+	if message.ID != pending[0].EventID {
+		t.Errorf("expected the event ID to survive the retry, got %s want %s", message.ID, pending[0].EventID)
+	}
+	if strings.Contains(string(messages[0].Body), "password") {
+		t.Error("expected the password hash to stay out of the event")
+	}
+
+	pending, _ = relay.FindAll(model.OutboxStatusPending)
+	if len(pending) != 0 {
+		t.Errorf("expected no pending events, got %d", len(pending))
+	}
+}
+
+func TestOutboxRollsBackWithUser(t *testing.T) {
+	dir := t.TempDir()
+	userRepo := repository.NewUserRepoImpl(filepath.Join(dir, "user.json"))
+	// Outbox yang tidak bisa ditulis membatalkan registrasi
+	outboxRepo := repository.NewOutboxEventRepoImpl(filepath.Join(dir, "missing", "outbox_event.json"))
+	userUsecase := usecase.NewUserUsecaseImpl(userRepo, outboxRepo)
+
+	if _, err := userUsecase.Save(model.User{Username: "alice", Password: "secret123", Email: "alice@example.com"}); err == nil {
+		t.Fatal("expected the registration to fail")
+	}
+	if users, _ := userRepo.FindAll(); len(users) != 0 {
+		t.Errorf("expected the user to be removed again, got %d users", len(users))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Error("expected no outbox to be written")
+	}
+}
+
+func TestOutboxIsOptional(t *testing.T) {
+	dir := t.TempDir()
+	userRepo := repository.NewUserRepoImpl(filepath.Join(dir, "user.json"))
+	userUsecase := usecase.NewUserUsecaseImpl(userRepo, nil)
+
+	if _, err := userUsecase.Save(model.User{Username: "alice", Password: "secret123", Email: "alice@example.com"}); err != nil {
+		t.Fatalf("expected the registration to work without an outbox, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// acceptAboveThreshold lets alice accept a request from bobby that is large
+// enough to need approval.
+func acceptAboveThreshold(t *testing.T, b *testBank) (model.Account, model.PaymentRequest) {
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	request, err := b.requests.Save(model.PaymentRequest{RequesterID: bobby.UserID, ToAccountID: bobby.ID, PayerID: alice.UserID, Amount: 11000000})
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	accepted, err := b.requests.Accept(request.ID, alice.UserID, alice.ID)
+	if err != nil {
+		t.Fatalf("accept failed: %v", err)
+	}
+	if accepted.Status != model.PaymentRequestStatusAccepted || accepted.TransferID == 0 {
+		t.Fatalf("expected the request to wait for the approval of its transfer, got %+v", accepted)
+	}
+	return alice, accepted
+}
+
+func TestPaymentRequestIsPaidWhenItsTransferIsApproved(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice, accepted := acceptAboveThreshold(t, b)
+
+	approval, _ := b.apprRepo.FindByTransferId(accepted.TransferID)
+	if _, err := b.approvals.Approve(approval.ID, b.approver.ID); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+
+	paid, _ := b.reqRepo.FindById(accepted.ID)
+	if paid.Status != model.PaymentRequestStatusPaid || paid.TransferID != accepted.TransferID {
+		t.Errorf("expected the request to be paid by transfer %d, got %+v", accepted.TransferID, paid)
+	}
+	if got := b.balance(alice.ID); got != 19000000 {
+		t.Errorf("expected 11,000,000 to be debited, balance is %.2f", got)
+	}
+}
+
+func TestPaymentRequestIsAskedAgainWhenItsTransferIsRejected(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice, accepted := acceptAboveThreshold(t, b)
+
+	approval, _ := b.apprRepo.FindByTransferId(accepted.TransferID)
+	if _, err := b.approvals.Reject(approval.ID, b.approver.ID, "not expected"); err != nil {
+		t.Fatalf("reject failed: %v", err)
+	}
+
+	reopened, _ := b.reqRepo.FindById(accepted.ID)
+	if reopened.Status != model.PaymentRequestStatusPending || reopened.TransferID != 0 {
+		t.Fatalf("expected the request to be pending again, got %+v", reopened)
+	}
+
+	// Payer bisa menjawab request itu lagi
+	if _, err := b.requests.Decline(accepted.ID, alice.UserID, "paid in cash"); err != nil {
+		t.Errorf("expected the payer to be able to answer again: %v", err)
+	}
+}
+
+func TestPaymentRequestIsAskedAgainWhenItsTransferIsCancelled(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	_, accepted := acceptAboveThreshold(t, b)
+
+	if _, err := b.transfers.Reverse(accepted.TransferID); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+
+	reopened, _ := b.reqRepo.FindById(accepted.ID)
+	if reopened.Status != model.PaymentRequestStatusPending || reopened.TransferID != 0 {
+		t.Errorf("expected the request to be pending again, got %+v", reopened)
+	}
+}
+
+func TestPaymentRequestIsPaidOnce(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice, accepted := acceptAboveThreshold(t, b)
+
+	// Transfer kedua untuk request yang sama harus ditolak selama yang pertama menunggu
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: accepted.ToAccountID, Amount: accepted.Amount, PaymentRequestID: accepted.ID}); err == nil {
+		t.Fatal("expected a second transfer for the request to be refused")
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func TestReconciliationReportsBalanceMismatch(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 500000)
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 200000}); err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+
+	// Saldo diubah tanpa history, seperti edit langsung di file JSON
+	acc, _ := b.accRepo.FindById(bobby.ID)
+	acc.Balance += 50000
+	b.accRepo.Update(acc)
+
+	reconciliation := usecase.NewReconciliationUsecaseImpl(b.accRepo, b.hisRepo, b.audit, nil, nil)
+	report, err := reconciliation.Run(false, 0)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(report.Discrepancies) != 1 {
+		t.Fatalf("expected only bobby to mismatch, got %+v", report.Discrepancies)
+	}
+	item := report.Discrepancies[0]
+	if item.AccountID != bobby.ID || item.RecordedBalance != 750000 || item.ComputedBalance != 700000 || item.Difference != 50000 {
+		t.Errorf("unexpected discrepancy %+v", item)
+	}
+	if item.AdjustmentID != 0 {
+		t.Errorf("expected a report without adjustment, got history %d", item.AdjustmentID)
+	}
+}
+
+func TestReconciliationAdjustmentClearsMismatch(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+
+	acc, _ := b.accRepo.FindById(alice.ID)
+	acc.Balance -= 1000
+	b.accRepo.Update(acc)
+
+	reconciliation := usecase.NewReconciliationUsecaseImpl(b.accRepo, b.hisRepo, b.audit, nil, nil)
+	report, err := reconciliation.Run(true, b.approver.ID)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].AdjustmentID == 0 {
+		t.Fatalf("expected an adjustment for alice, got %+v", report.Discrepancies)
+	}
+
+	adjustment, _ := b.hisRepo.FindById(report.Discrepancies[0].AdjustmentID)
+	if adjustment.Type != model.HistoryTypeAdjustment || adjustment.Amount != -1000 {
+		t.Errorf("unexpected adjustment %+v", adjustment)
+	}
+	if got := b.balance(alice.ID); got != 999000 {
+		t.Errorf("expected the stored balance to be kept, got %.2f", got)
+	}
+
+	report, _ = reconciliation.Run(false, 0)
+	if len(report.Discrepancies) != 0 {
+		t.Errorf("expected no mismatch after the adjustment, got %+v", report.Discrepancies)
+	}
+}
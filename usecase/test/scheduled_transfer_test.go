@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func newTestSchedules(b *testBank) (usecase.ScheduledTransferUsecase, repository.ScheduledTransferExecutionRepo) {
+	executionRepo := repository.NewScheduledTransferExecutionRepoImpl(filepath.Join(b.dir, "scheduled_transfer_execution.json"))
+	schedules := usecase.NewScheduledTransferUsecaseImpl(repository.NewScheduledTransferRepoImpl(filepath.Join(b.dir, "scheduled_transfer.json")),
+		executionRepo, b.accRepo, b.transfers, nil, 2, time.Minute)
+	return schedules, executionRepo
+}
+
+func TestScheduledTransferRetriesLater(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+	schedules, executionRepo := newTestSchedules(b)
+
+	now := time.Now()
+	schedule, err := schedules.Save(model.ScheduledTransfer{UserID: alice.UserID, FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 25000, StartAt: now})
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	// Repo transfer tidak bisa dibaca, error sementara
+	transferFile := filepath.Join(b.dir, "transfer.json")
+	if err := os.Mkdir(transferFile, 0755); err != nil {
+		t.Fatalf("failed to break the transfer repository: %v", err)
+	}
+	start := time.Now()
+	if err := schedules.RunDue(now.Add(time.Second)); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("expected the retry not to block the run")
+	}
+	schedule, _ = schedules.FindById(schedule.ID)
+	if schedule.Attempts != 1 || schedule.NextRetryAt.IsZero() || schedule.Status != model.ScheduleStatusActive {
+		t.Fatalf("expected a retry to be scheduled, got %+v", schedule)
+	}
+	if executions, _ := executionRepo.FindByScheduledTransferId(schedule.ID); len(executions) != 0 {
+		t.Fatalf("expected no execution yet, got %+v", executions)
+	}
+
+	os.Remove(transferFile)
+	if err := schedules.RunDue(now.Add(30 * time.Second)); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got := b.balance(alice.ID); got != 1000000 {
+		t.Fatalf("expected the retry to wait for the delay, balance is %.2f", got)
+	}
+
+	if err := schedules.RunDue(now.Add(2 * time.Minute)); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	executions, _ := executionRepo.FindByScheduledTransferId(schedule.ID)
+	if len(executions) != 1 || executions[0].Status != model.ExecutionStatusSuccess || executions[0].Attempts != 2 {
+		t.Fatalf("expected one successful execution on the second attempt, got %+v", executions)
+	}
+	if schedule, _ = schedules.FindById(schedule.ID); schedule.Status != model.ScheduleStatusCompleted {
+		t.Errorf("expected the schedule to complete, it is %s", schedule.Status)
+	}
+}
+
+func TestScheduledTransferDoesNotRetryPermanentErrors(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+	carol := b.openAccount("carol", 100000)
+	schedules, executionRepo := newTestSchedules(b)
+
+	now := time.Now()
+	closedTarget, _ := schedules.Save(model.ScheduledTransfer{UserID: alice.UserID, FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 25000, StartAt: now, Frequency: model.FrequencyDaily})
+	tooLarge, _ := schedules.Save(model.ScheduledTransfer{UserID: alice.UserID, FromAccountID: alice.ID, ToAccountID: carol.ID, Amount: 5000000, StartAt: now})
+	if _, err := b.accounts.Close(bobby.ID, carol.ID, "moving banks", 0); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if err := schedules.RunDue(now.Add(time.Second)); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	executions, _ := executionRepo.FindByScheduledTransferId(closedTarget.ID)
+	if len(executions) != 1 || executions[0].Status != model.ExecutionStatusFailed || executions[0].Attempts != 1 {
+		t.Errorf("expected one failed attempt into the closed account, got %+v", executions)
+	}
+	if schedule, _ := schedules.FindById(closedTarget.ID); schedule.Attempts != 0 || !schedule.NextRetryAt.IsZero() || !schedule.NextRunAt.After(now) {
+		t.Errorf("expected the schedule to move to the next day, got %+v", schedule)
+	}
+
+	executions, _ = executionRepo.FindByScheduledTransferId(tooLarge.ID)
+	if len(executions) != 1 || executions[0].Status != model.ExecutionStatusSkipped || executions[0].Attempts != 1 {
+		t.Errorf("expected the occurrence without funds to be skipped, got %+v", executions)
+	}
+}
+
+func TestCancelledScheduleIsNotRetried(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+	schedules, executionRepo := newTestSchedules(b)
+
+	now := time.Now()
+	schedule, _ := schedules.Save(model.ScheduledTransfer{UserID: alice.UserID, FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 25000, StartAt: now})
+	transferFile := filepath.Join(b.dir, "transfer.json")
+	os.Mkdir(transferFile, 0755)
+	schedules.RunDue(now.Add(time.Second))
+	os.Remove(transferFile)
+
+	if _, err := schedules.Cancel(schedule.ID); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	schedules.RunDue(now.Add(2 * time.Minute))
+
+	if schedule, _ = schedules.FindById(schedule.ID); schedule.Status != model.ScheduleStatusCancelled {
+		t.Errorf("expected the schedule to stay cancelled, it is %s", schedule.Status)
+	}
+	if executions, _ := executionRepo.FindByScheduledTransferId(schedule.ID); len(executions) != 0 {
+		t.Errorf("expected no execution, got %+v", executions)
+	}
+}
+
+func TestScheduledTransferAboveThresholdWaitsForApproval(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+	schedules, executionRepo := newTestSchedules(b)
+
+	now := time.Now()
+	schedule, err := schedules.Save(model.ScheduledTransfer{UserID: alice.UserID, FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000, StartAt: now})
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if err := schedules.RunDue(now.Add(time.Second)); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	executions, _ := executionRepo.FindByScheduledTransferId(schedule.ID)
+	if len(executions) != 1 || executions[0].Status != model.ExecutionStatusPendingApproval || executions[0].TransferID == 0 {
+		t.Fatalf("expected one execution waiting for approval, got %+v", executions)
+	}
+	transfer, _ := b.traRepo.FindById(executions[0].TransferID)
+	if transfer.Status != model.TransferStatusPendingApproval {
+		t.Errorf("expected the transfer to wait for approval, it is %s", transfer.Status)
+	}
+	if got := b.balance(alice.ID); got != 30000000 {
+		t.Errorf("expected no money to move before approval, balance is %.2f", got)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+func TestApprovedTransferExecutesWhatWasApproved(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	pending, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000})
+	if err != nil || pending.Status != model.TransferStatusPendingApproval {
+		t.Fatalf("expected the transfer to wait for approval, got %+v, %v", pending, err)
+	}
+
+	if _, err := b.transfers.Update(model.Transfer{ID: pending.ID, Amount: 20000000}); err == nil {
+		t.Fatal("expected a pending transfer to be read only")
+	}
+
+	approval, err := b.apprRepo.FindByTransferId(pending.ID)
+	if err != nil {
+		t.Fatalf("failed to find the approval: %v", err)
+	}
+	approved, err := b.approvals.Approve(approval.ID, b.approver.ID)
+	if err != nil || approved.Status != model.ApprovalStatusApproved {
+		t.Fatalf("approve failed: %+v, %v", approved, err)
+	}
+	if got := b.balance(alice.ID); got != 19000000 {
+		t.Errorf("expected 11,000,000 to be debited, balance is %.2f", got)
+	}
+}
+
+func TestTamperedTransferIsNotExecuted(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	pending, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000})
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	// Record diubah langsung setelah diajukan, approver masih melihat 11 juta
+	tampered, _ := b.traRepo.FindById(pending.ID)
+	tampered.Amount = 20000000
+	b.traRepo.Update(tampered)
+
+	approval, _ := b.apprRepo.FindByTransferId(pending.ID)
+	if _, err := b.approvals.Approve(approval.ID, b.approver.ID); err == nil {
+		t.Fatal("expected a changed transfer to be refused")
+	}
+	if got := b.balance(alice.ID); got != 30000000 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+	if transfer, _ := b.traRepo.FindById(pending.ID); transfer.Status != model.TransferStatusFailed {
+		t.Errorf("expected the transfer to fail, got %s", transfer.Status)
+	}
+}
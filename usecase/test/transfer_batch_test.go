@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func newTestBatches(b *testBank) usecase.TransferBatchUsecase {
+	return usecase.NewTransferBatchUsecaseImpl(repository.NewTransferBatchRepoImpl(filepath.Join(b.dir, "transfer_batch.json")),
+		b.accRepo, b.hisRepo, b.transfers, usecase.NewFeeUsecaseImpl(b.feeRepo), 0)
+}
+
+// waitBatch polls until the batch has been processed in the background, or
+// is left waiting for approvals.
+func waitBatch(t *testing.T, batches usecase.TransferBatchUsecase, id int64) model.TransferBatch {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		batch, err := batches.FindById(id)
+		if err == nil && (!batch.CompletedAt.IsZero() || batch.Status == model.BatchStatusPendingApproval) {
+			return batch
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("batch %d was not processed in time", id)
+	return model.TransferBatch{}
+}
+
+func TestAllOrNothingBatchMovesNothingWhenAnItemFails(t *testing.T) {
+	// Item ketiga melewati limit jumlah transfer harian
+	b := newTestBank(t, model.TransferLimit{DailyCount: 2}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+	batches := newTestBatches(b)
+
+	batch, err := batches.Submit(model.TransferBatch{
+		UserID: alice.UserID,
+		Mode:   model.BatchModeAllOrNothing,
+		Items: []model.TransferBatchItem{
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000},
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000},
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	batch = waitBatch(t, batches, batch.ID)
+	if batch.Status != model.BatchStatusFailed || batch.SuccessCount != 0 {
+		t.Fatalf("expected the whole batch to fail, got %s with %d successes", batch.Status, batch.SuccessCount)
+	}
+	for _, item := range batch.Items {
+		if item.Status != model.BatchItemStatusFailed || item.TransferID != 0 {
+			t.Errorf("line %d: expected no transfer, got %s %d", item.Line, item.Status, item.TransferID)
+		}
+	}
+	if got := b.balance(alice.ID); got != 1000000 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+	if transfers, _ := b.traRepo.FindAll(); len(transfers) != 0 {
+		t.Errorf("expected no transfer records, got %d", len(transfers))
+	}
+}
+
+func TestAllOrNothingBatchCommitsEveryItem(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+	batches := newTestBatches(b)
+
+	batch, err := batches.Submit(model.TransferBatch{
+		UserID: alice.UserID,
+		Items: []model.TransferBatchItem{
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000},
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 200000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	batch = waitBatch(t, batches, batch.ID)
+	if batch.Status != model.BatchStatusCompleted || batch.SuccessCount != 2 {
+		t.Fatalf("expected the batch to complete, got %s with %d successes", batch.Status, batch.SuccessCount)
+	}
+	if got := b.balance(bobby.ID); got != 400000 {
+		t.Errorf("expected 300,000 to be credited, balance is %.2f", got)
+	}
+}
+
+func TestAllOrNothingBatchRejectsItemsThatNeedApproval(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+	batches := newTestBatches(b)
+
+	_, err := batches.Submit(model.TransferBatch{
+		UserID: alice.UserID,
+		Items: []model.TransferBatchItem{
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000},
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000},
+		},
+	})
+
+	var validationErr *usecase.BatchValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected the batch to be rejected, got %v", err)
+	}
+	if item := validationErr.Items[1]; item.Status != model.BatchItemStatusFailed || item.Error == "" {
+		t.Errorf("expected the second item to be refused, got %+v", item)
+	}
+	if transfers, _ := b.traRepo.FindAll(); len(transfers) != 0 {
+		t.Errorf("expected no transfer records, got %d", len(transfers))
+	}
+}
+
+func TestBestEffortBatchWaitingForApprovalIsNotCompleted(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 10000000)
+	alice := b.openAccount("alice", 30000000)
+	bobby := b.openAccount("bobby", 100000)
+	batches := newTestBatches(b)
+
+	batch, err := batches.Submit(model.TransferBatch{
+		UserID: alice.UserID,
+		Mode:   model.BatchModeBestEffort,
+		Items: []model.TransferBatchItem{
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000},
+			{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 12000000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	batch = waitBatch(t, batches, batch.ID)
+	if batch.Status != model.BatchStatusPendingApproval || !batch.CompletedAt.IsZero() {
+		t.Fatalf("expected the batch to wait for approval, got %s completed at %v", batch.Status, batch.CompletedAt)
+	}
+	if batch.PendingCount != 2 || batch.SuccessCount != 0 || batch.FailedCount != 0 {
+		t.Errorf("expected 2 pending items, got %d pending, %d successes, %d failures",
+			batch.PendingCount, batch.SuccessCount, batch.FailedCount)
+	}
+	for _, item := range batch.Items {
+		if item.Status != model.BatchItemStatusPendingApproval || item.TransferID == 0 {
+			t.Errorf("line %d: expected a transfer waiting for approval, got %s %d", item.Line, item.Status, item.TransferID)
+		}
+	}
+	if got := b.balance(bobby.ID); got != 100000 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+}
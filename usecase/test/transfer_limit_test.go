@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+// expectLimit fails the test unless err is a LimitExceededError with code.
+func expectLimit(t *testing.T, err error, code string) {
+	t.Helper()
+	var limitErr *usecase.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Code != code {
+		t.Errorf("expected %s, got %v", code, err)
+	}
+}
+
+func TestDailyLimitCapsTheOutflowOfTheDay(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{DailyAmount: 300000}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 200000}); err != nil {
+		t.Fatalf("first transfer failed: %v", err)
+	}
+	_, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 150000})
+	expectLimit(t, err, usecase.LimitCodeDailyAmount)
+
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000}); err != nil {
+		t.Errorf("expected the rest of the daily limit to be usable, got %v", err)
+	}
+}
+
+func TestMonthlyLimitCapsTheOutflowOfTheMonth(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{MonthlyAmount: 250000}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 200000}); err != nil {
+		t.Fatalf("first transfer failed: %v", err)
+	}
+	_, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 100000})
+	expectLimit(t, err, usecase.LimitCodeMonthlyAmount)
+
+	if got := b.balance(alice.ID); got != 800000 {
+		t.Errorf("expected only the first transfer to be debited, balance is %.2f", got)
+	}
+}
+
+func TestUserOverrideTakesPrecedenceOverTypeLimitAndDefault(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 1000000)
+	bobby := b.openAccount("bobby", 1000000)
+
+	limitRepo := repository.NewTransferLimitRepoImpl(filepath.Join(b.dir, "limit_override.json"))
+	limits := usecase.NewTransferLimitUsecaseImpl(limitRepo, b.hisRepo, b.traRepo, model.TransferLimit{MaxPerTransaction: 500000})
+
+	limit, err := limits.Resolve(alice)
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if limit.MaxPerTransaction != 500000 || limit.AccountType != model.AccountTypeSavings {
+		t.Errorf("expected the default limit without configured limits, got %+v", limit)
+	}
+
+	limitRepo.Save(model.TransferLimit{AccountType: model.AccountTypeSavings, MaxPerTransaction: 300000})
+	limitRepo.Save(model.TransferLimit{AccountType: model.AccountTypeSavings, UserID: alice.UserID, MaxPerTransaction: 900000})
+
+	if limit, _ := limits.Resolve(alice); limit.MaxPerTransaction != 900000 {
+		t.Errorf("expected the override of alice, got %+v", limit)
+	}
+	if limit, _ := limits.Resolve(bobby); limit.MaxPerTransaction != 300000 {
+		t.Errorf("expected the savings limit for bobby, got %+v", limit)
+	}
+	if limit, _ := limits.Resolve(model.Account{UserID: bobby.UserID, Type: model.AccountTypeChecking}); limit.MaxPerTransaction != 500000 {
+		t.Errorf("expected the default for a type without a limit, got %+v", limit)
+	}
+
+	if err := limits.Check(alice, model.Transfer{Amount: 800000}); err != nil {
+		t.Errorf("expected the override to allow 800,000, got %v", err)
+	}
+	expectLimit(t, limits.Check(bobby, model.Transfer{Amount: 800000}), usecase.LimitCodePerTransaction)
+}
+
+func TestTransfersWaitingForApprovalConsumeTheLimit(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{DailyAmount: 25000000}, 10000000)
+	alice := b.openAccount("alice", 50000000)
+	bobby := b.openAccount("bobby", 100000)
+
+	first, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000, InitiatedBy: alice.UserID})
+	if err != nil || first.Status != model.TransferStatusPendingApproval {
+		t.Fatalf("expected the first transfer to wait for approval, got %+v, %v", first, err)
+	}
+	if _, err := b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000, InitiatedBy: alice.UserID}); err != nil {
+		t.Fatalf("second transfer failed: %v", err)
+	}
+
+	// 22 juta sudah dipakai transfer yang menunggu approval
+	_, err = b.transfers.Save(model.Transfer{FromAccountID: alice.ID, ToAccountID: bobby.ID, Amount: 11000000, InitiatedBy: alice.UserID})
+	expectLimit(t, err, usecase.LimitCodeDailyAmount)
+
+	// Transfer yang disetujui tidak dihitung dua kali terhadap dirinya sendiri
+	approval, err := b.apprRepo.FindByTransferId(first.ID)
+	if err != nil {
+		t.Fatalf("approval not found: %v", err)
+	}
+	if _, err := b.approvals.Approve(approval.ID, b.approver.ID); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+	if got := b.balance(bobby.ID); got != 11100000 {
+		t.Errorf("expected the approved transfer to be credited, balance is %.2f", got)
+	}
+}
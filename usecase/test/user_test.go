@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+func TestPromoteMakesTheFirstAdmin(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 100000)
+	users := usecase.NewUserUsecaseImpl(b.userRepo, nil)
+
+	user, err := users.Promote("alice", model.RoleAdmin)
+	if err != nil {
+		t.Fatalf("promote failed: %v", err)
+	}
+	if user.Role != model.RoleAdmin {
+		t.Errorf("expected alice to be admin, got %s", user.Role)
+	}
+	stored, _ := b.userRepo.FindById(alice.UserID)
+	if stored.Role != model.RoleAdmin {
+		t.Errorf("expected the role to be stored, got %s", stored.Role)
+	}
+
+	// Restart dengan BOOTSTRAP_ADMIN_USERNAME yang sama tidak boleh gagal
+	if _, err := users.Promote("alice", model.RoleAdmin); err != nil {
+		t.Errorf("promoting an admin again failed: %v", err)
+	}
+	if _, err := users.Promote("bob", model.RoleAdmin); err == nil {
+		t.Error("expected an unknown username to be rejected")
+	}
+	if _, err := users.Promote("alice", "root"); err == nil {
+		t.Error("expected an invalid role to be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+)
+
+// failingRecorder refuses every account event, like an event store that is
+// down.
+type failingRecorder struct{}
+
+func (failingRecorder) Record(events []model.AccountEvent) error {
+	return errors.New("event store unavailable")
+}
+
+func TestVirtualAccountInboundIsRolledBackWhenEventsCannotBeRecorded(t *testing.T) {
+	b := newTestBank(t, model.TransferLimit{}, 0)
+	alice := b.openAccount("alice", 100000)
+
+	vaRepo := repository.NewVirtualAccountRepoImpl(filepath.Join(b.dir, "virtual_account.json"))
+	paymentRepo := repository.NewVirtualAccountPaymentRepoImpl(filepath.Join(b.dir, "virtual_account_payment.json"))
+	virtualAccounts := usecase.NewVirtualAccountUsecaseImpl(vaRepo, paymentRepo, b.accRepo, b.hisRepo, b.audit, nil,
+		"485", time.Hour, 3, false, nil, failingRecorder{})
+
+	virtualAccount, err := virtualAccounts.Save(model.VirtualAccount{UserID: alice.UserID, AccountID: alice.ID, Name: "Invoice 1", Amount: 50000})
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	_, err = virtualAccounts.Inbound(model.VirtualAccountInbound{Number: virtualAccount.Number, Amount: 50000, Reference: "REF-1"})
+	if err == nil {
+		t.Fatal("expected the payment to fail")
+	}
+
+	// Virtual account harus tetap bisa dibayar setelah rollback
+	stored, _ := vaRepo.FindById(virtualAccount.ID)
+	if stored.Status != model.VirtualAccountStatusActive || stored.PaidAmount != 0 || !stored.PaidAt.IsZero() {
+		t.Errorf("expected the virtual account to be restored, got %+v", stored)
+	}
+	if payments, _ := paymentRepo.FindByVirtualAccountId(virtualAccount.ID); len(payments) != 0 {
+		t.Errorf("expected no payment records, got %d", len(payments))
+	}
+	if got := b.balance(alice.ID); got != 100000 {
+		t.Errorf("expected no money to move, balance is %.2f", got)
+	}
+}
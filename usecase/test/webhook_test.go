@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/usecase"
+	"github.com/sferawann/test_mnc/webhook"
+)
+
+// newTestWebhooks subscribes url to every event and queues one delivery for
+// it. The delivery is saved directly so no background delivery races the
+// test.
+func newTestWebhooks(t *testing.T, url string, maxAttempts int) (usecase.WebhookUsecase, model.WebhookSubscription, model.WebhookDelivery) {
+	dir := t.TempDir()
+	deliveryRepo := repository.NewWebhookDeliveryRepoImpl(filepath.Join(dir, "webhook_delivery.json"))
+	webhooks := usecase.NewWebhookUsecaseImpl(repository.NewWebhookSubscriptionRepoImpl(filepath.Join(dir, "webhook.json")), deliveryRepo,
+		webhook.Sender{Client: &http.Client{Timeout: time.Second}}, maxAttempts, time.Minute, 10*time.Minute)
+
+	subscription, err := webhooks.Save(model.WebhookSubscription{URL: url, Events: []string{model.WebhookAllEvents}})
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	delivery, err := deliveryRepo.Save(model.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        "evt_1",
+		EventType:      model.EventTypes[0],
+		Payload:        []byte(`{"id":"evt_1"}`),
+		Status:         model.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("queue failed: %v", err)
+	}
+	return webhooks, subscription, delivery
+}
+
+func findDelivery(t *testing.T, webhooks usecase.WebhookUsecase, subscriptionID int64) model.WebhookDelivery {
+	deliveries, err := webhooks.FindDeliveries(subscriptionID, "")
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %d: %v", len(deliveries), err)
+	}
+	return deliveries[0]
+}
+
+func TestDeliverDueRetriesWithBackoffUntilDead(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhooks, subscription, _ := newTestWebhooks(t, server.URL, 3)
+	now := time.Now()
+
+	if err := webhooks.DeliverDue(now); err != nil {
+		t.Fatalf("deliver failed: %v", err)
+	}
+	delivery := findDelivery(t, webhooks, subscription.ID)
+	if delivery.Status != model.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusInternalServerError {
+		t.Fatalf("expected a failed first attempt, got %+v", delivery)
+	}
+	if !delivery.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the retry after 1m, got %v", delivery.NextAttemptAt.Sub(now))
+	}
+
+	// Belum waktunya dikirim ulang
+	webhooks.DeliverDue(now.Add(30 * time.Second))
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected no attempt before the backoff, got %d attempts", got)
+	}
+
+	webhooks.DeliverDue(now.Add(time.Minute))
+	delivery = findDelivery(t, webhooks, subscription.ID)
+	if delivery.Attempts != 2 || !delivery.NextAttemptAt.Equal(now.Add(3*time.Minute)) {
+		t.Errorf("expected the backoff to double to 2m, got %d attempts and %v", delivery.Attempts, delivery.NextAttemptAt.Sub(now))
+	}
+
+	webhooks.DeliverDue(now.Add(3 * time.Minute))
+	delivery = findDelivery(t, webhooks, subscription.ID)
+	if delivery.Status != model.WebhookDeliveryDead || delivery.Attempts != 3 || delivery.LastError == "" {
+		t.Fatalf("expected the delivery to be dead after 3 attempts, got %+v", delivery)
+	}
+
+	webhooks.DeliverDue(now.Add(time.Hour))
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("expected a dead delivery not to be sent again, got %d attempts", got)
+	}
+}
+
+func TestDeliverDueMarksA2xxResponseDelivered(t *testing.T) {
+	var hits int32
+	var secret string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Percobaan pertama gagal, percobaan kedua diterima
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), body, time.Now(), time.Minute); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhooks, subscription, _ := newTestWebhooks(t, server.URL, 3)
+	secret = subscription.Secret
+	now := time.Now()
+
+	webhooks.DeliverDue(now)
+	webhooks.DeliverDue(now.Add(time.Minute))
+
+	delivery := findDelivery(t, webhooks, subscription.ID)
+	if delivery.Status != model.WebhookDeliveryDelivered || delivery.ResponseStatus != http.StatusNoContent {
+		t.Fatalf("expected the signed delivery to be accepted, got %+v", delivery)
+	}
+	if delivery.Attempts != 2 || delivery.LastError != "" || !delivery.DeliveredAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the second attempt to deliver, got %+v", delivery)
+	}
+
+	webhooks.DeliverDue(now.Add(time.Hour))
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("expected a delivered delivery not to be sent again, got %d attempts", got)
+	}
+}
@@ -4,8 +4,20 @@ import "github.com/sferawann/test_mnc/model"
 
 type TransferUsecase interface {
 	Save(newTransfer model.Transfer) (model.Transfer, error)
+	Reverse(id int64) (model.Transfer, error)
+	ExecuteApproved(id int64) (model.Transfer, error)
+	FinishPending(id int64, status string) (model.Transfer, error)
 	Update(updatedTransfer model.Transfer) (model.Transfer, error)
 	Delete(id int64) (model.Transfer, error)
 	FindById(id int64) (model.Transfer, error)
 	FindAll() ([]model.Transfer, error)
+
+	// executeIn posts newTransfer as one step of the caller's ledgerTx without
+	// asking for approval, commitIn records and publishes everything written
+	// through tx. The caller holds balanceMu and rolls tx back on any error.
+	executeIn(tx *ledgerTx, newTransfer model.Transfer) (model.Transfer, error)
+	commitIn(tx *ledgerTx, extra ...model.AccountEvent) error
+
+	// requiresApproval reports whether newTransfer would wait for a checker.
+	requiresApproval(newTransfer model.Transfer) bool
 }
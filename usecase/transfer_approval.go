@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type TransferApprovalUsecase interface {
+	FindAll(status string) ([]model.TransferApproval, error)
+	FindById(id int64) (model.TransferApproval, error)
+	Approve(id int64, approverID int64) (model.TransferApproval, error)
+	Reject(id int64, approverID int64, reason string) (model.TransferApproval, error)
+	ExpireDue(now time.Time) error
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+type TransferApprovalUsecaseImpl struct {
+	ApprovalRepo    repository.TransferApprovalRepo
+	TransferRepo    repository.TransferRepo
+	UserRepo        repository.UserRepo
+	TransferUsecase TransferUsecase
+	AuditUsecase    AuditUsecase
+
+	// mu makes deciding on an approval and expiring it mutually exclusive
+	mu sync.Mutex
+}
+
+// FindAll implements TransferApprovalUsecase
+func (u *TransferApprovalUsecaseImpl) FindAll(status string) ([]model.TransferApproval, error) {
+	approvals, err := u.ApprovalRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		return approvals, nil
+	}
+
+	var filtered []model.TransferApproval
+	for _, approval := range approvals {
+		if approval.Status == status {
+			filtered = append(filtered, approval)
+		}
+	}
+
+	return filtered, nil
+}
+
+// FindById implements TransferApprovalUsecase
+func (u *TransferApprovalUsecaseImpl) FindById(id int64) (model.TransferApproval, error) {
+	return u.ApprovalRepo.FindById(id)
+}
+
+// Approve implements TransferApprovalUsecase
+func (u *TransferApprovalUsecaseImpl) Approve(id int64, approverID int64) (model.TransferApproval, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	approval, err := u.decidable(id, approverID)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	approval.DecidedBy = approverID
+	approval.DecidedAt = time.Now()
+
+	transfer, err := u.TransferUsecase.ExecuteApproved(approval.TransferID)
+	if err != nil {
+		// Approval tetap dicatat, tapi transfer gagal dieksekusi
+		approval.Status = model.ApprovalStatusFailed
+		approval.Reason = err.Error()
+		u.setTransferStatus(approval.TransferID, model.TransferStatusFailed)
+		recordAudit(u.AuditUsecase, "transfer.approval_failed", approverID, "transfer_approval", approval.ID, err.Error())
+		if _, updateErr := u.ApprovalRepo.Update(approval); updateErr != nil {
+			return model.TransferApproval{}, updateErr
+		}
+		return model.TransferApproval{}, err
+	}
+
+	approval.Status = model.ApprovalStatusApproved
+	approval.Transfer = transfer
+	approval, err = u.ApprovalRepo.Update(approval)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	recordAudit(u.AuditUsecase, "transfer.approved", approverID, "transfer_approval", approval.ID,
+		fmt.Sprintf("transfer %d executed", transfer.ID))
+
+	return approval, nil
+}
+
+// Reject implements TransferApprovalUsecase
+func (u *TransferApprovalUsecaseImpl) Reject(id int64, approverID int64, reason string) (model.TransferApproval, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	approval, err := u.decidable(id, approverID)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	approval.Status = model.ApprovalStatusRejected
+	approval.DecidedBy = approverID
+	approval.DecidedAt = time.Now()
+	approval.Reason = reason
+
+	u.setTransferStatus(approval.TransferID, model.TransferStatusRejected)
+	approval, err = u.ApprovalRepo.Update(approval)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	recordAudit(u.AuditUsecase, "transfer.rejected", approverID, "transfer_approval", approval.ID, reason)
+
+	return approval, nil
+}
+
+// ExpireDue implements TransferApprovalUsecase
+func (u *TransferApprovalUsecaseImpl) ExpireDue(now time.Time) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	approvals, err := u.ApprovalRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	for _, approval := range approvals {
+		if approval.Status != model.ApprovalStatusPending || approval.ExpiresAt.After(now) {
+			continue
+		}
+
+		approval.Status = model.ApprovalStatusExpired
+		approval.DecidedAt = now
+		u.setTransferStatus(approval.TransferID, model.TransferStatusExpired)
+		if _, err := u.ApprovalRepo.Update(approval); err != nil {
+			return err
+		}
+
+		recordAudit(u.AuditUsecase, "transfer.approval_expired", 0, "transfer_approval", approval.ID, "")
+	}
+
+	return nil
+}
+
+// decidable loads a pending approval and checks that approverID may decide
+// on it, nobody can approve a transfer they requested or that debits their
+// own account.
+func (u *TransferApprovalUsecaseImpl) decidable(id int64, approverID int64) (model.TransferApproval, error) {
+	approval, err := u.ApprovalRepo.FindById(id)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+
+	if approval.Status != model.ApprovalStatusPending {
+		return model.TransferApproval{}, fmt.Errorf("transfer approval is already %s", approval.Status)
+	}
+	if !approval.ExpiresAt.After(time.Now()) {
+		return model.TransferApproval{}, errors.New("transfer approval has expired")
+	}
+
+	approver, err := u.UserRepo.FindById(approverID)
+	if err != nil {
+		return model.TransferApproval{}, err
+	}
+	if approver.Role != model.RoleApprover && approver.Role != model.RoleAdmin {
+		return model.TransferApproval{}, errors.New("user is not allowed to approve transfers")
+	}
+	if approverID == approval.RequestedBy || approverID == approval.Transfer.FromAccount.UserID {
+		return model.TransferApproval{}, errors.New("transfers cannot be approved by their requester")
+	}
+
+	return approval, nil
+}
+
+// setTransferStatus closes the transfer of an approval that will not execute,
+// the approval decision is stored even when this fails.
+func (u *TransferApprovalUsecaseImpl) setTransferStatus(transferID int64, status string) {
+	if _, err := u.TransferUsecase.FinishPending(transferID, status); err != nil {
+		log.Printf("finish transfer %d as %s: %v", transferID, status, err)
+	}
+}
+
+func NewTransferApprovalUsecaseImpl(ApprovalRepo repository.TransferApprovalRepo, TransferRepo repository.TransferRepo, UserRepo repository.UserRepo, TransferUsecase TransferUsecase, AuditUsecase AuditUsecase) TransferApprovalUsecase {
+	return &TransferApprovalUsecaseImpl{
+		ApprovalRepo:    ApprovalRepo,
+		TransferRepo:    TransferRepo,
+		UserRepo:        UserRepo,
+		TransferUsecase: TransferUsecase,
+		AuditUsecase:    AuditUsecase,
+	}
+}
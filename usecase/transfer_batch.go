@@ -0,0 +1,9 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type TransferBatchUsecase interface {
+	Submit(newBatch model.TransferBatch) (model.TransferBatch, error)
+	FindById(id int64) (model.TransferBatch, error)
+	FindByUserId(userID int64) ([]model.TransferBatch, error)
+}
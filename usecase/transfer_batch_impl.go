@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+// BatchValidationError is returned by Submit when one or more items are
+// invalid, Items carries every item with its validation error.
+type BatchValidationError struct {
+	Items []model.TransferBatchItem
+}
+
+func (e *BatchValidationError) Error() string {
+	return "batch contains invalid items"
+}
+
+type TransferBatchUsecaseImpl struct {
+	BatchRepo       repository.TransferBatchRepo
+	AccRepo         repository.AccountRepo
+	HisRepo         repository.HistoryRepo
+	TransferUsecase TransferUsecase
+	FeeUsecase      FeeUsecase
+	MaxItems        int
+
+	// mu guards BatchRepo, batches are processed in background goroutines
+	mu sync.Mutex
+}
+
+// FindById implements TransferBatchUsecase
+func (u *TransferBatchUsecaseImpl) FindById(id int64) (model.TransferBatch, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.BatchRepo.FindById(id)
+}
+
+// FindByUserId implements TransferBatchUsecase
+func (u *TransferBatchUsecaseImpl) FindByUserId(userID int64) ([]model.TransferBatch, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.BatchRepo.FindByUserId(userID)
+}
+
+// Submit implements TransferBatchUsecase
+func (u *TransferBatchUsecaseImpl) Submit(newBatch model.TransferBatch) (model.TransferBatch, error) {
+	if newBatch.Mode == "" {
+		newBatch.Mode = model.BatchModeAllOrNothing
+	}
+	if newBatch.Mode != model.BatchModeAllOrNothing && newBatch.Mode != model.BatchModeBestEffort {
+		return model.TransferBatch{}, fmt.Errorf("invalid mode: %s", newBatch.Mode)
+	}
+	if len(newBatch.Items) == 0 {
+		return model.TransferBatch{}, errors.New("batch must contain at least one item")
+	}
+	if u.MaxItems > 0 && len(newBatch.Items) > u.MaxItems {
+		return model.TransferBatch{}, fmt.Errorf("batch must not contain more than %d items", u.MaxItems)
+	}
+
+	if err := u.validate(&newBatch); err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	newBatch.Status = model.BatchStatusPending
+	newBatch.SuccessCount = 0
+	newBatch.FailedCount = 0
+	newBatch.PendingCount = 0
+	newBatch.CompletedAt = time.Time{}
+
+	u.mu.Lock()
+	batch, err := u.BatchRepo.Save(newBatch)
+	u.mu.Unlock()
+	if err != nil {
+		return model.TransferBatch{}, err
+	}
+
+	go u.process(batch)
+
+	return batch, nil
+}
+
+// validate checks every item up front so a malformed upload is rejected as a
+// whole. For all-or-nothing batches the source accounts must also cover the
+// sum of their items including fees, and no item may need approval since the
+// batch cannot wait for it.
+func (u *TransferBatchUsecaseImpl) validate(batch *model.TransferBatch) error {
+	accounts := map[int64]model.Account{}
+	findAccount := func(id int64) (model.Account, error) {
+		if acc, ok := accounts[id]; ok {
+			return acc, nil
+		}
+		acc, err := u.AccRepo.FindById(id)
+		if err == nil {
+			accounts[id] = acc
+		}
+		return acc, err
+	}
+
+	invalid := false
+	totals := map[int64]float64{}
+	batch.TotalAmount = 0
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.Line == 0 {
+			item.Line = i + 1
+		}
+		item.Status = model.BatchItemStatusPending
+		item.TransferID = 0
+		item.Error = ""
+
+		fromacc, err := findAccount(item.FromAccountID)
+		switch {
+		case item.Amount <= 0:
+			item.Error = "amount must be greater than 0"
+		case item.FromAccountID == item.ToAccountID:
+			item.Error = "from_account_id and to_account_id must be different"
+		case err != nil:
+			item.Error = "from_account_id not found"
+		case fromacc.UserID != batch.UserID:
+			item.Error = "from_account_id does not belong to the current user"
+		}
+		if item.Error == "" {
+			toacc, err := findAccount(item.ToAccountID)
+			if err != nil {
+				item.Error = "to_account_id not found"
+			} else if quote, err := u.FeeUsecase.Quote(fromacc, toacc, item.Amount); err != nil {
+				item.Error = err.Error()
+			} else if batch.Mode == model.BatchModeAllOrNothing && u.TransferUsecase.requiresApproval(model.Transfer{
+				FromAccountID: item.FromAccountID,
+				ToAccountID:   item.ToAccountID,
+				Amount:        item.Amount,
+			}) {
+				item.Error = "amount requires approval, submit it as a best_effort batch"
+			} else {
+				totals[fromacc.ID] += quote.Total
+			}
+		}
+
+		if item.Error != "" {
+			item.Status = model.BatchItemStatusFailed
+			invalid = true
+		}
+		batch.TotalAmount += item.Amount
+	}
+
+	if batch.Mode == model.BatchModeAllOrNothing && !invalid {
+		for accountID, total := range totals {
+			if accounts[accountID].AvailableBalance() < total {
+				for i := range batch.Items {
+					if batch.Items[i].FromAccountID == accountID {
+						batch.Items[i].Status = model.BatchItemStatusFailed
+						batch.Items[i].Error = fmt.Sprintf("account %d cannot cover the batch total of %.2f", accountID, total)
+					}
+				}
+				invalid = true
+			}
+		}
+	}
+
+	if invalid {
+		return &BatchValidationError{Items: batch.Items}
+	}
+	return nil
+}
+
+func (u *TransferBatchUsecaseImpl) process(batch model.TransferBatch) {
+	batch.Status = model.BatchStatusProcessing
+	u.update(batch)
+
+	if batch.Mode == model.BatchModeAllOrNothing {
+		u.processAll(&batch)
+	} else {
+		u.processEach(&batch)
+	}
+
+	switch {
+	case batch.PendingCount > 0:
+		// Batch belum selesai selama masih ada item yang menunggu approver
+		batch.Status = model.BatchStatusPendingApproval
+		u.update(batch)
+		return
+	case batch.FailedCount == 0:
+		batch.Status = model.BatchStatusCompleted
+	case batch.SuccessCount == 0:
+		batch.Status = model.BatchStatusFailed
+	default:
+		batch.Status = model.BatchStatusPartial
+	}
+	batch.CompletedAt = time.Now()
+	u.update(batch)
+}
+
+// processAll posts every item in one ledgerTx, which is only committed when
+// all of them succeed.
+func (u *TransferBatchUsecaseImpl) processAll(batch *model.TransferBatch) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	tx := newLedgerTx(u.AccRepo, u.HisRepo)
+	for i := range batch.Items {
+		item := &batch.Items[i]
+
+		transfer, err := u.TransferUsecase.executeIn(tx, model.Transfer{
+			FromAccountID: item.FromAccountID,
+			ToAccountID:   item.ToAccountID,
+			Amount:        item.Amount,
+			InitiatedBy:   batch.UserID,
+		})
+		if err != nil {
+			tx.rollback()
+			u.abort(batch, i, err)
+			return
+		}
+
+		item.Status = model.BatchItemStatusSuccess
+		item.TransferID = transfer.ID
+	}
+
+	if err := u.TransferUsecase.commitIn(tx); err != nil {
+		tx.rollback()
+		u.abort(batch, -1, err)
+		return
+	}
+	batch.SuccessCount = len(batch.Items)
+}
+
+// abort marks every item of a rolled back batch as failed, the item at failed
+// with err and the others as not executed. A failed of -1 means the batch
+// could not be committed.
+func (u *TransferBatchUsecaseImpl) abort(batch *model.TransferBatch, failed int, err error) {
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		item.Status = model.BatchItemStatusFailed
+		item.TransferID = 0
+		item.Error = "not executed, batch aborted"
+		if i == failed || failed < 0 {
+			item.Error = err.Error()
+		}
+	}
+	batch.SuccessCount = 0
+	batch.FailedCount = len(batch.Items)
+}
+
+// processEach saves the items one by one, a failed item does not stop the
+// others.
+func (u *TransferBatchUsecaseImpl) processEach(batch *model.TransferBatch) {
+	for i := range batch.Items {
+		item := &batch.Items[i]
+
+		transfer, err := u.TransferUsecase.Save(model.Transfer{
+			FromAccountID: item.FromAccountID,
+			ToAccountID:   item.ToAccountID,
+			Amount:        item.Amount,
+			InitiatedBy:   batch.UserID,
+		})
+		if err != nil {
+			item.Status = model.BatchItemStatusFailed
+			item.Error = err.Error()
+			batch.FailedCount++
+		} else if transfer.Status == model.TransferStatusPendingApproval {
+			item.Status = model.BatchItemStatusPendingApproval
+			item.TransferID = transfer.ID
+			batch.PendingCount++
+		} else {
+			item.Status = model.BatchItemStatusSuccess
+			item.TransferID = transfer.ID
+			batch.SuccessCount++
+		}
+
+		u.update(*batch)
+	}
+}
+
+func (u *TransferBatchUsecaseImpl) update(batch model.TransferBatch) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, err := u.BatchRepo.Update(batch); err != nil {
+		log.Printf("batch %d: %v", batch.ID, err)
+	}
+}
+
+func NewTransferBatchUsecaseImpl(BatchRepo repository.TransferBatchRepo, AccountRepo repository.AccountRepo, HisRepo repository.HistoryRepo, TransferUsecase TransferUsecase, FeeUsecase FeeUsecase, MaxItems int) TransferBatchUsecase {
+	return &TransferBatchUsecaseImpl{
+		BatchRepo:       BatchRepo,
+		AccRepo:         AccountRepo,
+		HisRepo:         HisRepo,
+		TransferUsecase: TransferUsecase,
+		FeeUsecase:      FeeUsecase,
+		MaxItems:        MaxItems,
+	}
+}
@@ -2,17 +2,54 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/sferawann/test_mnc/model"
 	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/utils"
 )
 
-type TransferUsecaseImpl struct {
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// ErrApprovalRequired is returned when a transfer that has to be approved is
+// made as part of another operation that cannot wait for the approval.
+var ErrApprovalRequired = errors.New("transfer amount requires approval")
+
+// ErrCurrencyMismatch is returned for a transfer between accounts in
+// different currencies that does not use an FX quote.
+var ErrCurrencyMismatch = errors.New("accounts have different currencies, an fx quote is required")
+
+// TransferUsecaseConfig holds the collaborators and settings of a transfer
+// usecase. Events, OutboxRepo and AccountEvents may be left nil,
+// approvals are off while ApprovalThreshold is 0.
+type TransferUsecaseConfig struct {
 	TransferRepo repository.TransferRepo
 	AccRepo      repository.AccountRepo
 	UserRepo     repository.UserRepo
 	HisRepo      repository.HistoryRepo
+	LimitUsecase TransferLimitUsecase
+	FeeUsecase   FeeUsecase
+	FeeAccountID int64
+
+	ApprovalRepo      repository.TransferApprovalRepo
+	AuditUsecase      AuditUsecase
+	ApprovalThreshold float64
+	ApprovalTTL       time.Duration
+
+	FrozenBlocksCredits bool
+	ProductUsecase      AccountProductUsecase
+	QuoteRepo           repository.FxQuoteRepo
+	HoldRepo            repository.HoldRepo
+	RequestRepo         repository.PaymentRequestRepo
+
+	Events        EventPublisher
+	OutboxRepo    repository.OutboxEventRepo
+	AccountEvents AccountEventRecorder
+}
+
+type TransferUsecaseImpl struct {
+	TransferUsecaseConfig
 }
 
 // Delete implements TransferUsecase
@@ -32,12 +69,149 @@ func (u *TransferUsecaseImpl) FindById(id int64) (model.Transfer, error) {
 
 // Save implements TransferUsecase
 func (u *TransferUsecaseImpl) Save(newTransfer model.Transfer) (model.Transfer, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	newTransfer.ID = 0
+	newTransfer.Reversed = false
+	newTransfer.ReversalOf = 0
+	if newTransfer.Purpose == model.TransferPurposeClosingPayout {
+		return model.Transfer{}, errors.New("a closing payout is only made by closing the account")
+	}
+
+	newTransfer, err := u.prepare(newTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	if u.requiresApproval(newTransfer) {
+		return u.requestApproval(newTransfer)
+	}
+
+	return u.execute(newTransfer)
+}
+
+// executeIn implements TransferUsecase, a transfer that needs approval is
+// refused since nothing can wait for it inside tx.
+func (u *TransferUsecaseImpl) executeIn(tx *ledgerTx, newTransfer model.Transfer) (model.Transfer, error) {
+	newTransfer.ID = 0
+
+	newTransfer, err := u.prepare(newTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if u.requiresApproval(newTransfer) {
+		return model.Transfer{}, ErrApprovalRequired
+	}
+
+	return u.post(tx, newTransfer)
+}
+
+// commitIn implements TransferUsecase
+func (u *TransferUsecaseImpl) commitIn(tx *ledgerTx, extra ...model.AccountEvent) error {
+	if err := tx.record(u.AccountEvents, extra...); err != nil {
+		return err
+	}
+	tx.commit(u.Events)
+	return nil
+}
+
+// requiresApproval implements TransferUsecase, closing payouts never wait for
+// a checker.
+func (u *TransferUsecaseImpl) requiresApproval(newTransfer model.Transfer) bool {
+	if newTransfer.Purpose == model.TransferPurposeClosingPayout {
+		return false
+	}
+	return u.ApprovalThreshold > 0 && newTransfer.Amount > u.ApprovalThreshold
+}
+
+// ExecuteApproved implements TransferUsecase
+func (u *TransferUsecaseImpl) ExecuteApproved(id int64) (model.Transfer, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	pendingTransfer, err := u.TransferRepo.FindById(id)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	if pendingTransfer.Status != model.TransferStatusPendingApproval {
+		return model.Transfer{}, fmt.Errorf("transfer %d is not pending approval", id)
+	}
+
+	approval, err := u.ApprovalRepo.FindByTransferId(id)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	// Yang dieksekusi harus sama persis dengan yang dilihat approver
+	if err := matchesApproval(pendingTransfer, approval.Transfer); err != nil {
+		return model.Transfer{}, err
+	}
+
+	// Saldo dan limit diperiksa ulang karena bisa berubah selama menunggu approval
+	pendingTransfer, err = u.prepare(pendingTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if pendingTransfer.Fee != approval.Transfer.Fee {
+		return model.Transfer{}, fmt.Errorf("fee of transfer %d changed from %.2f to %.2f after it was submitted for approval", id, approval.Transfer.Fee, pendingTransfer.Fee)
+	}
+
+	return u.execute(pendingTransfer)
+}
+
+// matchesApproval returns an error when stored differs from the transfer that
+// was submitted for approval in any field that decides what money moves.
+func matchesApproval(stored model.Transfer, approved model.Transfer) error {
+	if stored.FromAccountID != approved.FromAccountID ||
+		stored.ToAccountID != approved.ToAccountID ||
+		stored.Amount != approved.Amount ||
+		stored.Fee != approved.Fee ||
+		stored.TargetAmount != approved.TargetAmount ||
+		stored.QuoteID != approved.QuoteID {
+		return fmt.Errorf("transfer %d was changed after it was submitted for approval", stored.ID)
+	}
+	return nil
+}
+
+// prepare resolves the accounts of newTransfer and runs every check that has
+// to pass before money can move.
+func (u *TransferUsecaseImpl) prepare(newTransfer model.Transfer) (model.Transfer, error) {
+	if newTransfer.ToAccountID == 0 && newTransfer.ToAccountNumber != "" {
+		// Nomor rekening divalidasi dulu supaya salah ketik tidak sampai ke lookup
+		if err := utils.ValidateAccountNumber(newTransfer.ToAccountNumber); err != nil {
+			return model.Transfer{}, err
+		}
+		toacc, err := u.AccRepo.FindByNumber(newTransfer.ToAccountNumber)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+		newTransfer.ToAccountID = toacc.ID
+	}
+
+	if newTransfer.FromAccountID == newTransfer.ToAccountID {
+		return model.Transfer{}, errors.New("from_account_id and to_account_id must be different")
+	}
 
 	fromacc, err := u.AccRepo.FindById(newTransfer.FromAccountID)
 	if err != nil {
 		return model.Transfer{}, err
 	}
 
+	if newTransfer.HoldID != 0 {
+		hold, err := u.capturableHold(newTransfer)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+		// Dana yang ditahan untuk capture ini ikut dihitung sebagai saldo tersedia
+		fromacc.HeldBalance -= hold.Amount
+	}
+	if newTransfer.PaymentRequestID != 0 {
+		if _, err := u.payableRequest(newTransfer); err != nil {
+			return model.Transfer{}, err
+		}
+	}
+
 	newTransfer.FromAccount = fromacc
 
 	toacc, err := u.AccRepo.FindById(newTransfer.ToAccountID)
@@ -55,50 +229,760 @@ func (u *TransferUsecaseImpl) Save(newTransfer model.Transfer) (model.Transfer,
 		return model.Transfer{}, err
 	}
 
+	// Akun yang dibekukan atau dormant tetap boleh dikosongkan saat ditutup
+	closing := newTransfer.Purpose == model.TransferPurposeClosingPayout
+	if closing && fromacc.CurrentStatus() == model.AccountStatusClosed {
+		return model.Transfer{}, &AccountStatusError{AccountID: fromacc.ID, Status: model.AccountStatusClosed}
+	}
+	if err := checkDebit(fromacc); err != nil && !closing {
+		return model.Transfer{}, err
+	}
+	if err := checkCredit(toacc, u.FrozenBlocksCredits); err != nil {
+		return model.Transfer{}, err
+	}
+
 	newTransfer.ToAccount = toacc
+	newTransfer.ToAccountNumber = toacc.Number
 	newTransfer.FromAccount.User = fromuser
 	newTransfer.ToAccount.User = touser
 
-	if fromacc.Balance < newTransfer.Amount {
-		return model.Transfer{}, errors.New(err.Error())
+	if newTransfer.InitiatedBy == 0 {
+		newTransfer.InitiatedBy = fromacc.UserID
+	}
+
+	newTransfer, err = u.applyRate(newTransfer, fromacc, toacc)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	// Payout penutupan memindahkan seluruh saldo, tanpa limit dan biaya
+	if closing {
+		newTransfer.Fee = 0
+		if err := u.ProductUsecase.CheckClosing(fromacc); err != nil {
+			return model.Transfer{}, err
+		}
+		if fromacc.AvailableBalance() < newTransfer.Amount {
+			return model.Transfer{}, ErrInsufficientBalance
+		}
+		return newTransfer, nil
+	}
+
+	err = u.LimitUsecase.Check(fromacc, newTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	quote, err := u.FeeUsecase.Quote(fromacc, toacc, newTransfer.Amount)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	newTransfer.Fee = quote.Fee
+
+	if err := u.ProductUsecase.CheckDebit(fromacc, quote.Total); err != nil {
+		return model.Transfer{}, err
+	}
+
+	return newTransfer, nil
+}
+
+// applyRate sets the currencies and the credited amount of newTransfer,
+// accounts in different currencies need an unexpired quote for the amount.
+func (u *TransferUsecaseImpl) applyRate(newTransfer model.Transfer, fromacc model.Account, toacc model.Account) (model.Transfer, error) {
+	newTransfer.Currency = fromacc.CurrencyCode()
+	newTransfer.TargetCurrency = toacc.CurrencyCode()
+
+	if newTransfer.Currency == newTransfer.TargetCurrency {
+		if newTransfer.QuoteID != 0 {
+			return model.Transfer{}, errors.New("an fx quote can only be used between accounts in different currencies")
+		}
+		newTransfer.TargetAmount = newTransfer.Amount
+		newTransfer.Rate = 1
+		return newTransfer, nil
+	}
+
+	if newTransfer.QuoteID == 0 {
+		return model.Transfer{}, ErrCurrencyMismatch
+	}
+
+	quote, err := u.QuoteRepo.FindById(newTransfer.QuoteID)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if quote.FromAccountID != fromacc.ID || quote.FromCurrency != newTransfer.Currency || quote.ToCurrency != newTransfer.TargetCurrency {
+		return model.Transfer{}, fmt.Errorf("fx quote %d does not match the accounts of this transfer", quote.ID)
+	}
+	if quote.SourceAmount != newTransfer.Amount {
+		return model.Transfer{}, fmt.Errorf("fx quote %d is for an amount of %.2f %s", quote.ID, quote.SourceAmount, quote.FromCurrency)
+	}
+	// Quote yang sudah dipakai transfer ini tetap berlaku selama menunggu approval
+	if quote.TransferID != 0 && quote.TransferID != newTransfer.ID {
+		return model.Transfer{}, fmt.Errorf("fx quote %d was already used", quote.ID)
+	}
+	if quote.TransferID == 0 && time.Now().After(quote.ExpiresAt) {
+		return model.Transfer{}, fmt.Errorf("fx quote %d has expired", quote.ID)
+	}
+
+	newTransfer.TargetAmount = quote.TargetAmount
+	newTransfer.Rate = quote.Rate
+	return newTransfer, nil
+}
+
+// useQuote ties the quote of newTransfer to it so it cannot be used again.
+func (u *TransferUsecaseImpl) useQuote(newTransfer model.Transfer) (func(), error) {
+	if newTransfer.QuoteID == 0 {
+		return func() {}, nil
+	}
+
+	quote, err := u.QuoteRepo.FindById(newTransfer.QuoteID)
+	if err != nil {
+		return nil, err
+	}
+	previous := quote
+
+	quote.TransferID = newTransfer.ID
+	if _, err := u.QuoteRepo.Update(quote); err != nil {
+		return nil, err
+	}
+	return func() { u.QuoteRepo.Update(previous) }, nil
+}
+
+// payableRequest loads the payment request newTransfer pays. It has to be
+// pending, or accepted with newTransfer as the transfer waiting for approval.
+func (u *TransferUsecaseImpl) payableRequest(newTransfer model.Transfer) (model.PaymentRequest, error) {
+	if u.RequestRepo == nil {
+		return model.PaymentRequest{}, errors.New("payment requests are not configured")
+	}
+
+	request, err := u.RequestRepo.FindById(newTransfer.PaymentRequestID)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	switch {
+	case request.TransferID == 0 && request.Status != model.PaymentRequestStatusPending,
+		request.TransferID != 0 && request.Status != model.PaymentRequestStatusAccepted:
+		return model.PaymentRequest{}, fmt.Errorf("payment request is already %s", request.Status)
+	case request.TransferID != 0 && request.TransferID != newTransfer.ID:
+		return model.PaymentRequest{}, fmt.Errorf("payment request is being paid by transfer %d", request.TransferID)
+	case request.ToAccountID != newTransfer.ToAccountID || request.Amount != newTransfer.Amount:
+		return model.PaymentRequest{}, fmt.Errorf("transfer does not match payment request %d", request.ID)
+	}
+
+	return request, nil
+}
+
+// answerRequest stores the outcome of the payment request paid by transfer,
+// the returned func restores the previous request.
+func (u *TransferUsecaseImpl) answerRequest(transfer model.Transfer, status string) (func(), error) {
+	if transfer.PaymentRequestID == 0 {
+		return func() {}, nil
+	}
+
+	request, err := u.payableRequest(transfer)
+	if err != nil {
+		return nil, err
+	}
+	previous := request
+
+	request.Status = status
+	request.FromAccountID = transfer.FromAccountID
+	request.TransferID = transfer.ID
+	request.Reason = ""
+	request.RespondedAt = time.Now()
+	if _, err := u.RequestRepo.Update(request); err != nil {
+		return nil, err
+	}
+	return func() { u.RequestRepo.Update(previous) }, nil
+}
+
+// reopenRequest asks the payer again when the transfer paying a request will
+// not execute.
+func (u *TransferUsecaseImpl) reopenRequest(pendingTransfer model.Transfer) error {
+	if pendingTransfer.PaymentRequestID == 0 || u.RequestRepo == nil {
+		return nil
+	}
+
+	request, err := u.RequestRepo.FindById(pendingTransfer.PaymentRequestID)
+	if err != nil {
+		return err
+	}
+	if request.TransferID != pendingTransfer.ID || request.Status != model.PaymentRequestStatusAccepted {
+		return nil
+	}
+
+	request.Status = model.PaymentRequestStatusPending
+	if !request.ExpiresAt.After(time.Now()) {
+		request.Status = model.PaymentRequestStatusExpired
+	}
+	request.FromAccountID = 0
+	request.TransferID = 0
+	request.Reason = fmt.Sprintf("transfer %d was %s", pendingTransfer.ID, pendingTransfer.Status)
+	request.RespondedAt = time.Now()
+	_, err = u.RequestRepo.Update(request)
+	return err
+}
+
+func (u *TransferUsecaseImpl) execute(newTransfer model.Transfer) (model.Transfer, error) {
+	// Semua perubahan saldo dan history dibatalkan jika salah satu langkah gagal
+	tx := newLedgerTx(u.AccRepo, u.HisRepo)
+	savedTransfer, err := u.post(tx, newTransfer)
+	if err != nil {
+		tx.rollback()
+		return model.Transfer{}, err
+	}
+
+	if err := u.commitIn(tx); err != nil {
+		tx.rollback()
+		return model.Transfer{}, err
 	}
 
-	fromacc.Balance -= newTransfer.Amount
-	toacc.Balance += newTransfer.Amount
+	return savedTransfer, nil
+}
 
-	_, err = u.AccRepo.Update(fromacc)
+// requestApproval stores newTransfer without moving any money and opens an
+// approval request for it.
+func (u *TransferUsecaseImpl) requestApproval(newTransfer model.Transfer) (model.Transfer, error) {
+	newTransfer.Status = model.TransferStatusPendingApproval
+	pendingTransfer, err := u.TransferRepo.Save(newTransfer)
 	if err != nil {
-		return model.Transfer{}, errors.New(err.Error())
+		return model.Transfer{}, err
 	}
 
-	_, err = u.AccRepo.Update(toacc)
+	undoOutbox, err := saveTransferCreated(u.OutboxRepo, pendingTransfer)
 	if err != nil {
-		return model.Transfer{}, errors.New(err.Error())
+		u.TransferRepo.Delete(pendingTransfer.ID)
+		return model.Transfer{}, err
 	}
 
-	fromaccHis := model.History{
-		AccountID: fromacc.ID,
-		Account:   fromacc,
-		Amount:    -newTransfer.Amount,
+	undoQuote, err := u.useQuote(pendingTransfer)
+	if err != nil {
+		undoOutbox()
+		u.TransferRepo.Delete(pendingTransfer.ID)
+		return model.Transfer{}, err
 	}
 
-	toaccHis := model.History{
-		AccountID: toacc.ID,
-		Account:   toacc,
-		Amount:    +newTransfer.Amount,
+	undoHold, err := u.linkHold(pendingTransfer)
+	if err != nil {
+		undoQuote()
+		undoOutbox()
+		u.TransferRepo.Delete(pendingTransfer.ID)
+		return model.Transfer{}, err
 	}
 
-	_, err = u.HisRepo.Save(fromaccHis)
+	undoRequest, err := u.answerRequest(pendingTransfer, model.PaymentRequestStatusAccepted)
 	if err != nil {
-		return model.Transfer{}, errors.New(err.Error())
+		undoHold()
+		undoQuote()
+		undoOutbox()
+		u.TransferRepo.Delete(pendingTransfer.ID)
+		return model.Transfer{}, err
 	}
 
-	_, err = u.HisRepo.Save(toaccHis)
+	approval, err := u.ApprovalRepo.Save(model.TransferApproval{
+		TransferID:  pendingTransfer.ID,
+		Transfer:    pendingTransfer,
+		RequestedBy: pendingTransfer.InitiatedBy,
+		Status:      model.ApprovalStatusPending,
+		ExpiresAt:   time.Now().Add(u.ApprovalTTL),
+	})
 	if err != nil {
-		return model.Transfer{}, errors.New(err.Error())
+		undoRequest()
+		undoHold()
+		undoQuote()
+		undoOutbox()
+		u.TransferRepo.Delete(pendingTransfer.ID)
+		return model.Transfer{}, err
 	}
 
-	return u.TransferRepo.Save(newTransfer)
+	recordAudit(u.AuditUsecase, "transfer.approval_requested", pendingTransfer.InitiatedBy, "transfer_approval", approval.ID,
+		fmt.Sprintf("transfer %d of %.2f from account %d to account %d", pendingTransfer.ID, pendingTransfer.Amount, pendingTransfer.FromAccountID, pendingTransfer.ToAccountID))
+
+	publishTransfer(u.Events, model.EventTransferPendingApproval, pendingTransfer)
+
+	return pendingTransfer, nil
+}
+
+// post writes the balance changes, the transfer record and the history rows
+// through tx.
+func (u *TransferUsecaseImpl) post(tx *ledgerTx, newTransfer model.Transfer) (model.Transfer, error) {
+	if newTransfer.Fee > 0 && u.FeeAccountID == 0 {
+		return model.Transfer{}, errors.New("fee revenue account is not configured")
+	}
+
+	fromacc, err := tx.adjust(newTransfer.FromAccountID, -newTransfer.Amount)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	toacc, err := tx.adjust(newTransfer.ToAccountID, newTransfer.CreditAmount())
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	// Transfer disimpan lebih dulu supaya history bisa merujuk ke ID-nya
+	savedTransfer, err := u.saveCompleted(tx, newTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	tx.completed(savedTransfer)
+
+	undoQuote, err := u.useQuote(savedTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	tx.undo(undoQuote)
+
+	if err := u.captureHold(tx, savedTransfer); err != nil {
+		return model.Transfer{}, err
+	}
+
+	undoRequest, err := u.answerRequest(savedTransfer, model.PaymentRequestStatusPaid)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	tx.undo(undoRequest)
+
+	_, err = tx.saveHistory(model.History{
+		AccountID:             fromacc.ID,
+		Account:               fromacc,
+		Type:                  model.HistoryTypeTransferOut,
+		TransferID:            savedTransfer.ID,
+		CounterpartyAccountID: toacc.ID,
+		Description:           fmt.Sprintf("Transfer to account %d", toacc.ID),
+		Channel:               newTransfer.Channel,
+		Reference:             newTransfer.Reference,
+		Amount:                -newTransfer.Amount,
+		BalanceAfter:          fromacc.Balance,
+	})
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	_, err = tx.saveHistory(model.History{
+		AccountID:             toacc.ID,
+		Account:               toacc,
+		Type:                  model.HistoryTypeTransferIn,
+		TransferID:            savedTransfer.ID,
+		CounterpartyAccountID: fromacc.ID,
+		Description:           fmt.Sprintf("Transfer from account %d", fromacc.ID),
+		Channel:               newTransfer.Channel,
+		Reference:             newTransfer.Reference,
+		Amount:                +newTransfer.CreditAmount(),
+		BalanceAfter:          toacc.Balance,
+	})
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	if newTransfer.Fee > 0 {
+		fromacc, err = tx.adjust(newTransfer.FromAccountID, -newTransfer.Fee)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+
+		feeacc, err := tx.adjust(u.FeeAccountID, newTransfer.Fee)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+
+		_, err = tx.saveHistory(model.History{
+			AccountID:             fromacc.ID,
+			Account:               fromacc,
+			Type:                  model.HistoryTypeFee,
+			TransferID:            savedTransfer.ID,
+			CounterpartyAccountID: feeacc.ID,
+			Description:           fmt.Sprintf("Fee for transfer %d", savedTransfer.ID),
+			Amount:                -newTransfer.Fee,
+			BalanceAfter:          fromacc.Balance,
+		})
+		if err != nil {
+			return model.Transfer{}, err
+		}
+
+		_, err = tx.saveHistory(model.History{
+			AccountID:             feeacc.ID,
+			Account:               feeacc,
+			Type:                  model.HistoryTypeFee,
+			TransferID:            savedTransfer.ID,
+			CounterpartyAccountID: fromacc.ID,
+			Description:           fmt.Sprintf("Fee for transfer %d", savedTransfer.ID),
+			Amount:                +newTransfer.Fee,
+			BalanceAfter:          feeacc.Balance,
+		})
+		if err != nil {
+			return model.Transfer{}, err
+		}
+	}
+
+	return savedTransfer, nil
+}
+
+// saveCompleted stores newTransfer as completed, a transfer that was waiting
+// for approval already has a record which is updated instead.
+func (u *TransferUsecaseImpl) saveCompleted(tx *ledgerTx, newTransfer model.Transfer) (model.Transfer, error) {
+	previous := newTransfer
+	newTransfer.Status = model.TransferStatusCompleted
+
+	if newTransfer.ID != 0 {
+		savedTransfer, err := u.TransferRepo.Update(newTransfer)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+		tx.undo(func() { u.TransferRepo.Update(previous) })
+		return savedTransfer, nil
+	}
+
+	savedTransfer, err := u.TransferRepo.Save(newTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	tx.undo(func() { u.TransferRepo.Delete(savedTransfer.ID) })
+
+	undoOutbox, err := saveTransferCreated(u.OutboxRepo, savedTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	tx.undo(undoOutbox)
+
+	return savedTransfer, nil
+}
+
+// capturableHold loads the hold newTransfer captures. It has to be active,
+// on the source account and not captured by another transfer already, a
+// transfer that waited for approval still owns the hold it was linked to.
+func (u *TransferUsecaseImpl) capturableHold(newTransfer model.Transfer) (model.Hold, error) {
+	if u.HoldRepo == nil {
+		return model.Hold{}, errors.New("holds are not configured")
+	}
+
+	hold, err := u.HoldRepo.FindById(newTransfer.HoldID)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	if hold.Status != model.HoldStatusActive {
+		return model.Hold{}, fmt.Errorf("hold is already %s", hold.Status)
+	}
+	if hold.AccountID != newTransfer.FromAccountID {
+		return model.Hold{}, fmt.Errorf("hold %d is not on account %d", hold.ID, newTransfer.FromAccountID)
+	}
+	if newTransfer.Amount > hold.Amount {
+		return model.Hold{}, fmt.Errorf("capture amount must be between 0 and %.2f", hold.Amount)
+	}
+	if hold.TransferID != 0 && hold.TransferID != newTransfer.ID {
+		return model.Hold{}, fmt.Errorf("hold is being captured by transfer %d", hold.TransferID)
+	}
+	if hold.TransferID == 0 && !hold.ExpiresAt.After(time.Now()) {
+		return model.Hold{}, errors.New("hold has expired")
+	}
+
+	return hold, nil
+}
+
+// linkHold ties the hold of a transfer waiting for approval to it, the funds
+// stay held until the transfer is decided.
+func (u *TransferUsecaseImpl) linkHold(pendingTransfer model.Transfer) (func(), error) {
+	if pendingTransfer.HoldID == 0 {
+		return func() {}, nil
+	}
+
+	hold, err := u.HoldRepo.FindById(pendingTransfer.HoldID)
+	if err != nil {
+		return nil, err
+	}
+	previous := hold
+
+	hold.TransferID = pendingTransfer.ID
+	hold.ToAccountID = pendingTransfer.ToAccountID
+	if _, err := u.HoldRepo.Update(hold); err != nil {
+		return nil, err
+	}
+	return func() { u.HoldRepo.Update(previous) }, nil
+}
+
+// captureHold releases the funds held for savedTransfer and marks the hold
+// captured through tx, in the same tx that debits the account.
+func (u *TransferUsecaseImpl) captureHold(tx *ledgerTx, savedTransfer model.Transfer) error {
+	if savedTransfer.HoldID == 0 {
+		return nil
+	}
+
+	hold, err := u.capturableHold(savedTransfer)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.adjustHeld(hold.AccountID, -hold.Amount); err != nil {
+		return err
+	}
+
+	previous := hold
+	hold.Status = model.HoldStatusCaptured
+	hold.CapturedAmount = savedTransfer.Amount
+	hold.ToAccountID = savedTransfer.ToAccountID
+	hold.TransferID = savedTransfer.ID
+	if _, err := u.HoldRepo.Update(hold); err != nil {
+		return err
+	}
+	tx.undo(func() { u.HoldRepo.Update(previous) })
+	return nil
+}
+
+// unlinkHold frees the hold of a pending transfer that will not execute, so
+// it can be captured again or released.
+func (u *TransferUsecaseImpl) unlinkHold(pendingTransfer model.Transfer) error {
+	if pendingTransfer.HoldID == 0 || u.HoldRepo == nil {
+		return nil
+	}
+
+	hold, err := u.HoldRepo.FindById(pendingTransfer.HoldID)
+	if err != nil {
+		return err
+	}
+	if hold.TransferID != pendingTransfer.ID {
+		return nil
+	}
+
+	hold.TransferID = 0
+	_, err = u.HoldRepo.Update(hold)
+	return err
+}
+
+// Reverse implements TransferUsecase
+func (u *TransferUsecaseImpl) Reverse(id int64) (model.Transfer, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	original, err := u.TransferRepo.FindById(id)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	if original.Status == model.TransferStatusPendingApproval {
+		return u.cancelPending(original)
+	}
+	if original.Status != "" && original.Status != model.TransferStatusCompleted {
+		return model.Transfer{}, fmt.Errorf("transfer %d is %s and cannot be reversed", id, original.Status)
+	}
+	if original.Reversed {
+		return model.Transfer{}, fmt.Errorf("transfer %d is already reversed", id)
+	}
+	if original.ReversalOf != 0 {
+		return model.Transfer{}, errors.New("a reversal cannot be reversed")
+	}
+
+	toacc, err := u.AccRepo.FindById(original.ToAccountID)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	fromacc, err := u.AccRepo.FindById(original.FromAccountID)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	// Reversal tetap boleh untuk akun yang dibekukan, hanya akun tertutup yang ditolak
+	if err := checkCredit(toacc, false); err != nil {
+		return model.Transfer{}, err
+	}
+	if err := checkCredit(fromacc, false); err != nil {
+		return model.Transfer{}, err
+	}
+	if toacc.AvailableBalance() < original.CreditAmount() {
+		return model.Transfer{}, ErrInsufficientBalance
+	}
+
+	tx := newLedgerTx(u.AccRepo, u.HisRepo)
+	reversal, err := u.postReversal(tx, original)
+	if err != nil {
+		tx.rollback()
+		return model.Transfer{}, err
+	}
+
+	if err := tx.record(u.AccountEvents); err != nil {
+		tx.rollback()
+		return model.Transfer{}, err
+	}
+	tx.commit(u.Events)
+	publishTransfer(u.Events, model.EventTransferReversed, reversal)
+
+	return reversal, nil
+}
+
+// postReversal moves the amount of original back to its source account and
+// refunds the fee, limits and fees do not apply to reversals.
+func (u *TransferUsecaseImpl) postReversal(tx *ledgerTx, original model.Transfer) (model.Transfer, error) {
+	toacc, err := tx.adjust(original.ToAccountID, -original.CreditAmount())
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	fromacc, err := tx.adjust(original.FromAccountID, original.Amount)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	reversal, err := u.TransferRepo.Save(model.Transfer{
+		FromAccountID:  original.ToAccountID,
+		FromAccount:    toacc,
+		ToAccountID:    original.FromAccountID,
+		ToAccount:      fromacc,
+		Amount:         original.CreditAmount(),
+		Currency:       original.TargetCurrency,
+		TargetAmount:   original.Amount,
+		TargetCurrency: original.Currency,
+		Rate:           original.Amount / original.CreditAmount(),
+		ReversalOf:     original.ID,
+		Status:         model.TransferStatusCompleted,
+	})
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	tx.undo(func() { u.TransferRepo.Delete(reversal.ID) })
+
+	undoOutbox, err := saveTransferCreated(u.OutboxRepo, reversal)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	tx.undo(undoOutbox)
+
+	description := fmt.Sprintf("Reversal of transfer %d", original.ID)
+
+	_, err = tx.saveHistory(model.History{
+		AccountID:             toacc.ID,
+		Account:               toacc,
+		Type:                  model.HistoryTypeReversal,
+		TransferID:            reversal.ID,
+		CounterpartyAccountID: fromacc.ID,
+		Description:           description,
+		Amount:                -original.CreditAmount(),
+		BalanceAfter:          toacc.Balance,
+	})
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	_, err = tx.saveHistory(model.History{
+		AccountID:             fromacc.ID,
+		Account:               fromacc,
+		Type:                  model.HistoryTypeReversal,
+		TransferID:            reversal.ID,
+		CounterpartyAccountID: toacc.ID,
+		Description:           description,
+		Amount:                +original.Amount,
+		BalanceAfter:          fromacc.Balance,
+	})
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	if original.Fee > 0 {
+		fromacc, err = tx.adjust(original.FromAccountID, original.Fee)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+
+		feeacc, err := tx.adjust(u.FeeAccountID, -original.Fee)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+
+		description := fmt.Sprintf("Fee refund for transfer %d", original.ID)
+
+		_, err = tx.saveHistory(model.History{
+			AccountID:             fromacc.ID,
+			Account:               fromacc,
+			Type:                  model.HistoryTypeReversal,
+			TransferID:            reversal.ID,
+			CounterpartyAccountID: feeacc.ID,
+			Description:           description,
+			Amount:                +original.Fee,
+			BalanceAfter:          fromacc.Balance,
+		})
+		if err != nil {
+			return model.Transfer{}, err
+		}
+
+		_, err = tx.saveHistory(model.History{
+			AccountID:             feeacc.ID,
+			Account:               feeacc,
+			Type:                  model.HistoryTypeReversal,
+			TransferID:            reversal.ID,
+			CounterpartyAccountID: fromacc.ID,
+			Description:           description,
+			Amount:                -original.Fee,
+			BalanceAfter:          feeacc.Balance,
+		})
+		if err != nil {
+			return model.Transfer{}, err
+		}
+	}
+
+	original.Reversed = true
+	_, err = u.TransferRepo.Update(original)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	return reversal, nil
+}
+
+// FinishPending implements TransferUsecase
+func (u *TransferUsecaseImpl) FinishPending(id int64, status string) (model.Transfer, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	pendingTransfer, err := u.TransferRepo.FindById(id)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if pendingTransfer.Status != model.TransferStatusPendingApproval {
+		return model.Transfer{}, fmt.Errorf("transfer %d is not pending approval", id)
+	}
+
+	return u.finishPending(pendingTransfer, status)
+}
+
+// finishPending closes a transfer that will not execute with status and
+// frees what was reserved for it.
+func (u *TransferUsecaseImpl) finishPending(pendingTransfer model.Transfer, status string) (model.Transfer, error) {
+	pendingTransfer.Status = status
+	finishedTransfer, err := u.TransferRepo.Update(pendingTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	if err := u.unlinkHold(finishedTransfer); err != nil {
+		return model.Transfer{}, err
+	}
+	if err := u.reopenRequest(finishedTransfer); err != nil {
+		return model.Transfer{}, err
+	}
+
+	return finishedTransfer, nil
+}
+
+// cancelPending withdraws the approval request of a transfer that has not
+// moved any money yet.
+func (u *TransferUsecaseImpl) cancelPending(pendingTransfer model.Transfer) (model.Transfer, error) {
+	approval, err := u.ApprovalRepo.FindByTransferId(pendingTransfer.ID)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	approval.Status = model.ApprovalStatusCancelled
+	approval.DecidedAt = time.Now()
+	_, err = u.ApprovalRepo.Update(approval)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	cancelledTransfer, err := u.finishPending(pendingTransfer, model.TransferStatusCancelled)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
+	recordAudit(u.AuditUsecase, "transfer.approval_cancelled", pendingTransfer.InitiatedBy, "transfer_approval", approval.ID,
+		fmt.Sprintf("transfer %d cancelled before approval", pendingTransfer.ID))
+
+	return cancelledTransfer, nil
 }
 
 // Update implements TransferUsecase
@@ -110,6 +994,12 @@ func (u *TransferUsecaseImpl) Update(updatedTransfer model.Transfer) (model.Tran
 		return model.Transfer{}, err
 	}
 
+	// Transfer yang belum selesai tidak boleh diubah, approver harus melihat yang dieksekusi
+	if previousTransfer.Status != "" && previousTransfer.Status != model.TransferStatusCompleted {
+		return model.Transfer{}, fmt.Errorf("transfer %d is %s and cannot be updated", previousTransfer.ID, previousTransfer.Status)
+	}
+	updatedTransfer.Status = previousTransfer.Status
+
 	// Mengambil nilai-nilai field dari entitas sebelumnya
 	previousFromAccID := previousTransfer.FromAccountID
 	previousToAccID := previousTransfer.ToAccountID
@@ -164,11 +1054,8 @@ func (u *TransferUsecaseImpl) Update(updatedTransfer model.Transfer) (model.Tran
 	return u.TransferRepo.Update(updatedTransfer)
 }
 
-func NewTransferUsecaseImpl(TransferRepo repository.TransferRepo, UserRepo repository.UserRepo, AccountRepo repository.AccountRepo, HisRepo repository.HistoryRepo) TransferUsecase {
+func NewTransferUsecaseImpl(config TransferUsecaseConfig) TransferUsecase {
 	return &TransferUsecaseImpl{
-		TransferRepo: TransferRepo,
-		UserRepo:     UserRepo,
-		AccRepo:      AccountRepo,
-		HisRepo:      HisRepo,
+		TransferUsecaseConfig: config,
 	}
 }
@@ -0,0 +1,10 @@
+package usecase
+
+import "github.com/sferawann/test_mnc/model"
+
+type TransferLimitUsecase interface {
+	FindAll() ([]model.TransferLimit, error)
+	Resolve(account model.Account) (model.TransferLimit, error)
+	Usage(account model.Account) (model.TransferLimitUsage, error)
+	Check(account model.Account, newTransfer model.Transfer) error
+}
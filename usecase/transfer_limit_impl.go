@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+)
+
+const (
+	LimitCodePerTransaction = "LIMIT_PER_TRANSACTION_EXCEEDED"
+	LimitCodeDailyAmount    = "LIMIT_DAILY_AMOUNT_EXCEEDED"
+	LimitCodeMonthlyAmount  = "LIMIT_MONTHLY_AMOUNT_EXCEEDED"
+	LimitCodeDailyCount     = "LIMIT_DAILY_COUNT_EXCEEDED"
+)
+
+// LimitExceededError is returned when a transfer would break one of the
+// limits configured for the source account.
+type LimitExceededError struct {
+	Code    string
+	Message string
+}
+
+func (e *LimitExceededError) Error() string {
+	return e.Message
+}
+
+type TransferLimitUsecaseImpl struct {
+	LimitRepo    repository.TransferLimitRepo
+	HisRepo      repository.HistoryRepo
+	TransferRepo repository.TransferRepo
+	DefaultLimit model.TransferLimit
+}
+
+// FindAll implements TransferLimitUsecase
+func (u *TransferLimitUsecaseImpl) FindAll() ([]model.TransferLimit, error) {
+	return u.LimitRepo.FindAll()
+}
+
+// Resolve implements TransferLimitUsecase
+func (u *TransferLimitUsecaseImpl) Resolve(account model.Account) (model.TransferLimit, error) {
+	limits, err := u.LimitRepo.FindAll()
+	if err != nil {
+		return model.TransferLimit{}, err
+	}
+
+	accountType := account.Type
+	if accountType == "" {
+		accountType = model.AccountTypeSavings
+	}
+
+	// Override user lebih diutamakan daripada limit per tipe rekening
+	var typeLimit *model.TransferLimit
+	for i, limit := range limits {
+		if limit.AccountType != "" && limit.AccountType != accountType {
+			continue
+		}
+		if limit.UserID == account.UserID {
+			return limit, nil
+		}
+		if limit.UserID == 0 && typeLimit == nil {
+			typeLimit = &limits[i]
+		}
+	}
+
+	if typeLimit != nil {
+		return *typeLimit, nil
+	}
+
+	defaultLimit := u.DefaultLimit
+	defaultLimit.AccountType = accountType
+	return defaultLimit, nil
+}
+
+// Usage implements TransferLimitUsecase
+func (u *TransferLimitUsecaseImpl) Usage(account model.Account) (model.TransferLimitUsage, error) {
+	return u.usage(account, 0)
+}
+
+// usage sums the outflow of account today and this month. Transfers waiting
+// for approval count as well, except the one with id skipTransferID.
+func (u *TransferLimitUsecaseImpl) usage(account model.Account, skipTransferID int64) (model.TransferLimitUsage, error) {
+	limit, err := u.Resolve(account)
+	if err != nil {
+		return model.TransferLimitUsage{}, err
+	}
+
+	historys, err := u.HisRepo.FindByAccountId(account.ID)
+	if err != nil {
+		return model.TransferLimitUsage{}, err
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	usage := model.TransferLimitUsage{
+		AccountID: account.ID,
+		Limit:     limit,
+	}
+	for _, history := range historys {
+		if !countsTowardLimit(history) || history.CreatedAt.Before(startOfMonth) {
+			continue
+		}
+		usage.UsedThisMonth += -history.Amount
+		if !history.CreatedAt.Before(startOfDay) {
+			usage.UsedToday += -history.Amount
+			usage.CountToday++
+		}
+	}
+
+	// Transfer yang menunggu approval sudah memakai limit walaupun uang belum berpindah
+	transfers, err := u.TransferRepo.FindAll()
+	if err != nil {
+		return model.TransferLimitUsage{}, err
+	}
+	for _, transfer := range transfers {
+		if transfer.FromAccountID != account.ID || transfer.ID == skipTransferID ||
+			transfer.Status != model.TransferStatusPendingApproval || transfer.CreatedAt.Before(startOfMonth) {
+			continue
+		}
+		usage.UsedThisMonth += transfer.Amount
+		if !transfer.CreatedAt.Before(startOfDay) {
+			usage.UsedToday += transfer.Amount
+			usage.CountToday++
+		}
+	}
+
+	usage.RemainingToday = remaining(limit.DailyAmount, usage.UsedToday)
+	usage.RemainingThisMonth = remaining(limit.MonthlyAmount, usage.UsedThisMonth)
+	usage.RemainingCountToday = -1
+	if limit.DailyCount > 0 {
+		usage.RemainingCountToday = limit.DailyCount - usage.CountToday
+		if usage.RemainingCountToday < 0 {
+			usage.RemainingCountToday = 0
+		}
+	}
+
+	return usage, nil
+}
+
+// Check implements TransferLimitUsecase, a transfer that waits for approval
+// is not counted against itself when it is checked again on approval.
+func (u *TransferLimitUsecaseImpl) Check(account model.Account, newTransfer model.Transfer) error {
+	usage, err := u.usage(account, newTransfer.ID)
+	if err != nil {
+		return err
+	}
+	amount := newTransfer.Amount
+	limit := usage.Limit
+
+	if limit.MaxPerTransaction > 0 && amount > limit.MaxPerTransaction {
+		return &LimitExceededError{
+			Code:    LimitCodePerTransaction,
+			Message: fmt.Sprintf("amount exceeds the per transaction limit of %.2f", limit.MaxPerTransaction),
+		}
+	}
+	if limit.DailyCount > 0 && usage.CountToday >= limit.DailyCount {
+		return &LimitExceededError{
+			Code:    LimitCodeDailyCount,
+			Message: fmt.Sprintf("daily limit of %d transactions reached", limit.DailyCount),
+		}
+	}
+	if limit.DailyAmount > 0 && amount > usage.RemainingToday {
+		return &LimitExceededError{
+			Code:    LimitCodeDailyAmount,
+			Message: fmt.Sprintf("amount exceeds the remaining daily limit of %.2f", usage.RemainingToday),
+		}
+	}
+	if limit.MonthlyAmount > 0 && amount > usage.RemainingThisMonth {
+		return &LimitExceededError{
+			Code:    LimitCodeMonthlyAmount,
+			Message: fmt.Sprintf("amount exceeds the remaining monthly limit of %.2f", usage.RemainingThisMonth),
+		}
+	}
+
+	return nil
+}
+
+func remaining(limit, used float64) float64 {
+	if limit <= 0 {
+		return -1
+	}
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+// countsTowardLimit reports whether history is money the account sent out.
+// Rows written before history had a type only carry the sign of the amount.
+func countsTowardLimit(history model.History) bool {
+	switch history.Type {
+	case model.HistoryTypeTransferOut, model.HistoryTypeWithdrawal:
+		return true
+	case "":
+		return history.Amount < 0
+	}
+	return false
+}
+
+func NewTransferLimitUsecaseImpl(LimitRepo repository.TransferLimitRepo, HisRepo repository.HistoryRepo, TransferRepo repository.TransferRepo, DefaultLimit model.TransferLimit) TransferLimitUsecase {
+	return &TransferLimitUsecaseImpl{
+		LimitRepo:    LimitRepo,
+		HisRepo:      HisRepo,
+		TransferRepo: TransferRepo,
+		DefaultLimit: DefaultLimit,
+	}
+}
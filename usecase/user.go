@@ -9,4 +9,6 @@ type UserUsecase interface {
 	FindById(id int64) (model.User, error)
 	FindAll() ([]model.User, error)
 	FindByUsername(username string) (model.User, error)
+	UpdateRole(id int64, role string) (model.User, error)
+	Promote(username string, role string) (model.User, error)
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/sferawann/test_mnc/model"
@@ -9,7 +10,8 @@ import (
 )
 
 type UserUsecaseImpl struct {
-	UserRepo repository.UserRepo
+	UserRepo   repository.UserRepo
+	OutboxRepo repository.OutboxEventRepo
 }
 
 // Delete implements UserUsecase
@@ -53,8 +55,26 @@ func (u *UserUsecaseImpl) Save(newUser model.User) (model.User, error) {
 		return model.User{}, err
 	}
 	newUser.Password = hashedPassword
+	newUser.Role = model.RoleUser
 
-	return u.UserRepo.Save(newUser)
+	savedUser, err := u.UserRepo.Save(newUser)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	_, err = saveOutbox(u.OutboxRepo, model.DomainEventUserRegistered, model.AggregateUser, savedUser.ID, model.UserRegistered{
+		UserID:    savedUser.ID,
+		Username:  savedUser.Username,
+		Email:     savedUser.Email,
+		Role:      savedUser.Role,
+		CreatedAt: savedUser.CreatedAt,
+	})
+	if err != nil {
+		u.UserRepo.Delete(savedUser.ID)
+		return model.User{}, err
+	}
+
+	return savedUser, nil
 }
 
 // Update implements UserUsecase
@@ -90,6 +110,9 @@ func (u *UserUsecaseImpl) Update(updatedUser model.User) (model.User, error) {
 		updatedUser.CreatedAt = previousCreatedAt
 	}
 
+	// Role hanya bisa diubah melalui UpdateRole
+	updatedUser.Role = previousUser.Role
+
 	// Hash password baru jika ada perubahan
 	if updatedUser.Password != previousPassword {
 		updatedUser.Password = hashedPassword
@@ -98,8 +121,39 @@ func (u *UserUsecaseImpl) Update(updatedUser model.User) (model.User, error) {
 	return u.UserRepo.Update(updatedUser)
 }
 
-func NewUserUsecaseImpl(UserRepo repository.UserRepo) UserUsecase {
+// UpdateRole implements UserUsecase
+func (u *UserUsecaseImpl) UpdateRole(id int64, role string) (model.User, error) {
+	switch role {
+	case model.RoleUser, model.RoleApprover, model.RoleAdmin:
+	default:
+		return model.User{}, fmt.Errorf("invalid role: %s", role)
+	}
+
+	user, err := u.UserRepo.FindById(id)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user.Role = role
+	return u.UserRepo.Update(user)
+}
+
+// Promote implements UserUsecase, it changes the role of a user by username
+// and does nothing when the user already has the role.
+func (u *UserUsecaseImpl) Promote(username string, role string) (model.User, error) {
+	user, err := u.UserRepo.FindByUsername(username)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+	return u.UpdateRole(user.ID, role)
+}
+
+func NewUserUsecaseImpl(UserRepo repository.UserRepo, OutboxRepo repository.OutboxEventRepo) UserUsecase {
 	return &UserUsecaseImpl{
-		UserRepo: UserRepo,
+		UserRepo:   UserRepo,
+		OutboxRepo: OutboxRepo,
 	}
 }
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+type VirtualAccountUsecase interface {
+	Save(newVirtualAccount model.VirtualAccount) (model.VirtualAccount, error)
+	FindById(id int64) (model.VirtualAccount, error)
+	FindByUserId(userID int64) ([]model.VirtualAccount, error)
+	FindPayments(id int64) ([]model.VirtualAccountPayment, error)
+	Close(id int64, userID int64) (model.VirtualAccount, error)
+	Inbound(inbound model.VirtualAccountInbound) (model.VirtualAccountPayment, error)
+	ExpireDue(now time.Time) error
+	DeliverCallbacks(now time.Time) error
+}
+
+// VirtualAccountNotifier tells the owner of a virtual account about a
+// payment into it.
+type VirtualAccountNotifier interface {
+	NotifyPaid(virtualAccount model.VirtualAccount, payment model.VirtualAccountPayment) error
+}
@@ -0,0 +1,474 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/utils"
+)
+
+const (
+	VirtualAccountErrNotFound       = "VA_NOT_FOUND"
+	VirtualAccountErrNotActive      = "VA_NOT_ACTIVE"
+	VirtualAccountErrExpired        = "VA_EXPIRED"
+	VirtualAccountErrAmountMismatch = "VA_AMOUNT_MISMATCH"
+)
+
+// VirtualAccountError is returned when an inbound payment can not be matched
+// to an open virtual account, the clearing network rejects the payment.
+type VirtualAccountError struct {
+	Code    string
+	Message string
+}
+
+func (e *VirtualAccountError) Error() string {
+	return e.Message
+}
+
+type VirtualAccountUsecaseImpl struct {
+	VirtualAccountRepo repository.VirtualAccountRepo
+	PaymentRepo        repository.VirtualAccountPaymentRepo
+	AccRepo            repository.AccountRepo
+	HisRepo            repository.HistoryRepo
+	AuditUsecase       AuditUsecase
+	Notifier           VirtualAccountNotifier
+	Events             EventPublisher
+	AccountEvents      AccountEventRecorder
+
+	BankCode            string
+	DefaultTTL          time.Duration
+	CallbackMaxAttempts int
+	FrozenBlocksCredits bool
+
+	// callbackMu keeps a payment from being delivered by two runs at once
+	callbackMu sync.Mutex
+}
+
+// Save implements VirtualAccountUsecase
+func (u *VirtualAccountUsecaseImpl) Save(newVirtualAccount model.VirtualAccount) (model.VirtualAccount, error) {
+	newVirtualAccount.Name = strings.TrimSpace(newVirtualAccount.Name)
+	newVirtualAccount.ExternalID = strings.TrimSpace(newVirtualAccount.ExternalID)
+	if newVirtualAccount.Name == "" || len(newVirtualAccount.Name) > 50 {
+		return model.VirtualAccount{}, errors.New("name must have 1 to 50 characters")
+	}
+	if newVirtualAccount.Amount < 0 {
+		return model.VirtualAccount{}, errors.New("amount cannot be negative")
+	}
+	if newVirtualAccount.Amount > 0 {
+		if err := validateCashAmount(newVirtualAccount.Amount); err != nil {
+			return model.VirtualAccount{}, err
+		}
+	}
+	if err := validateCallbackURL(newVirtualAccount.CallbackURL); err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	now := time.Now()
+	if newVirtualAccount.ExpiresAt.IsZero() {
+		newVirtualAccount.ExpiresAt = now.Add(u.DefaultTTL)
+	}
+	if !newVirtualAccount.ExpiresAt.After(now) {
+		return model.VirtualAccount{}, errors.New("expires_at must be in the future")
+	}
+
+	acc, err := u.AccRepo.FindById(newVirtualAccount.AccountID)
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+	if acc.UserID != newVirtualAccount.UserID {
+		return model.VirtualAccount{}, errors.New("account does not belong to the user")
+	}
+	if acc.CurrentStatus() == model.AccountStatusClosed {
+		return model.VirtualAccount{}, &AccountStatusError{AccountID: acc.ID, Status: model.AccountStatusClosed}
+	}
+	if acc.CurrencyCode() != model.DefaultCurrency {
+		return model.VirtualAccount{}, fmt.Errorf("virtual accounts are only available for %s accounts", model.DefaultCurrency)
+	}
+
+	if newVirtualAccount.ExternalID != "" {
+		virtualAccounts, err := u.VirtualAccountRepo.FindByUserId(newVirtualAccount.UserID)
+		if err != nil {
+			return model.VirtualAccount{}, err
+		}
+		for _, virtualAccount := range virtualAccounts {
+			if virtualAccount.ExternalID == newVirtualAccount.ExternalID {
+				return model.VirtualAccount{}, fmt.Errorf("external_id %s already has virtual account %s", virtualAccount.ExternalID, virtualAccount.Number)
+			}
+		}
+	}
+
+	number, err := u.newNumber()
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	newVirtualAccount.ID = 0
+	newVirtualAccount.Number = number
+	newVirtualAccount.Currency = acc.CurrencyCode()
+	newVirtualAccount.Status = model.VirtualAccountStatusActive
+	newVirtualAccount.PaidAmount = 0
+	newVirtualAccount.PaidAt = time.Time{}
+	newVirtualAccount.CreatedAt = now
+	virtualAccount, err := u.VirtualAccountRepo.Save(newVirtualAccount)
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+
+	recordAudit(u.AuditUsecase, "virtual_account.create", virtualAccount.UserID, "virtual_account", virtualAccount.ID,
+		fmt.Sprintf("%s for account %d", virtualAccount.Number, virtualAccount.AccountID))
+
+	return virtualAccount, nil
+}
+
+// FindById implements VirtualAccountUsecase
+func (u *VirtualAccountUsecaseImpl) FindById(id int64) (model.VirtualAccount, error) {
+	return u.VirtualAccountRepo.FindById(id)
+}
+
+// FindByUserId implements VirtualAccountUsecase
+func (u *VirtualAccountUsecaseImpl) FindByUserId(userID int64) ([]model.VirtualAccount, error) {
+	return u.VirtualAccountRepo.FindByUserId(userID)
+}
+
+// FindPayments implements VirtualAccountUsecase
+func (u *VirtualAccountUsecaseImpl) FindPayments(id int64) ([]model.VirtualAccountPayment, error) {
+	return u.PaymentRepo.FindByVirtualAccountId(id)
+}
+
+// Close implements VirtualAccountUsecase
+func (u *VirtualAccountUsecaseImpl) Close(id int64, userID int64) (model.VirtualAccount, error) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	virtualAccount, err := u.VirtualAccountRepo.FindById(id)
+	if err != nil {
+		return model.VirtualAccount{}, err
+	}
+	if virtualAccount.UserID != userID {
+		return model.VirtualAccount{}, errors.New("virtual account does not belong to the user")
+	}
+	if virtualAccount.Status != model.VirtualAccountStatusActive {
+		return model.VirtualAccount{}, fmt.Errorf("virtual account is already %s", virtualAccount.Status)
+	}
+
+	virtualAccount.Status = model.VirtualAccountStatusClosed
+	return u.VirtualAccountRepo.Update(virtualAccount)
+}
+
+// Inbound implements VirtualAccountUsecase. A payment whose reference was
+// already credited returns the earlier payment, so the clearing network can
+// safely retry.
+func (u *VirtualAccountUsecaseImpl) Inbound(inbound model.VirtualAccountInbound) (model.VirtualAccountPayment, error) {
+	if err := validateCashAmount(inbound.Amount); err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+	if inbound.Reference == "" {
+		return model.VirtualAccountPayment{}, errors.New("reference is required")
+	}
+
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	if payment, err := u.PaymentRepo.FindByReference(inbound.Reference); err == nil {
+		virtualAccount, err := u.VirtualAccountRepo.FindById(payment.VirtualAccountID)
+		if err != nil || virtualAccount.Number != inbound.Number || payment.Amount != inbound.Amount {
+			return model.VirtualAccountPayment{}, fmt.Errorf("reference %s was already used for another payment", inbound.Reference)
+		}
+		return payment, nil
+	}
+
+	virtualAccount, err := u.VirtualAccountRepo.FindByNumber(inbound.Number)
+	if err != nil {
+		return model.VirtualAccountPayment{}, &VirtualAccountError{
+			Code:    VirtualAccountErrNotFound,
+			Message: fmt.Sprintf("virtual account %s does not exist", inbound.Number),
+		}
+	}
+	if err := checkInbound(virtualAccount, inbound.Amount, time.Now()); err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	acc, err := u.AccRepo.FindById(virtualAccount.AccountID)
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+	if err := checkCredit(acc, u.FrozenBlocksCredits); err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	tx := newLedgerTx(u.AccRepo, u.HisRepo)
+	payment, err := u.credit(tx, virtualAccount, inbound)
+	if err != nil {
+		tx.rollback()
+		return model.VirtualAccountPayment{}, err
+	}
+	if err := tx.record(u.AccountEvents); err != nil {
+		tx.rollback()
+		return model.VirtualAccountPayment{}, err
+	}
+	tx.commit(u.Events)
+
+	recordAudit(u.AuditUsecase, "virtual_account.payment", 0, "virtual_account", virtualAccount.ID,
+		fmt.Sprintf("payment %d of %.2f, reference %s", payment.ID, payment.Amount, payment.Reference))
+
+	if payment.CallbackStatus == model.CallbackStatusPending {
+		// Callback pertama langsung dikirim, sisanya diulang oleh job
+		go func() {
+			if err := u.DeliverCallbacks(time.Now()); err != nil {
+				log.Printf("virtual account callbacks: %v", err)
+			}
+		}()
+	}
+
+	return payment, nil
+}
+
+// credit posts the inbound payment to the owning account and records it on
+// the virtual account.
+func (u *VirtualAccountUsecaseImpl) credit(tx *ledgerTx, virtualAccount model.VirtualAccount, inbound model.VirtualAccountInbound) (model.VirtualAccountPayment, error) {
+	acc, err := tx.adjust(virtualAccount.AccountID, inbound.Amount)
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	payer := inbound.PayerName
+	if payer == "" {
+		payer = "unknown payer"
+	}
+	history, err := tx.saveHistory(model.History{
+		AccountID:    acc.ID,
+		Account:      acc,
+		Type:         model.HistoryTypeDeposit,
+		Description:  fmt.Sprintf("Virtual account %s payment from %s", virtualAccount.Number, payer),
+		Channel:      model.ChannelVirtualAccount,
+		Reference:    inbound.Reference,
+		Amount:       inbound.Amount,
+		BalanceAfter: acc.Balance,
+	})
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	now := time.Now()
+	newPayment := model.VirtualAccountPayment{
+		VirtualAccountID: virtualAccount.ID,
+		AccountID:        acc.ID,
+		Amount:           inbound.Amount,
+		Reference:        inbound.Reference,
+		PayerName:        inbound.PayerName,
+		HistoryID:        history.ID,
+		CreatedAt:        now,
+	}
+	if virtualAccount.CallbackURL != "" {
+		newPayment.CallbackStatus = model.CallbackStatusPending
+	}
+	payment, err := u.PaymentRepo.Save(newPayment)
+	if err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+	tx.undo(func() {
+		if _, err := u.PaymentRepo.Delete(payment.ID); err != nil {
+			log.Printf("rollback virtual account payment %d: %v", payment.ID, err)
+		}
+	})
+
+	previous := virtualAccount
+	virtualAccount.PaidAmount = math.Round((virtualAccount.PaidAmount+inbound.Amount)*100) / 100
+	virtualAccount.PaidAt = now
+	if virtualAccount.Amount > 0 {
+		virtualAccount.Status = model.VirtualAccountStatusPaid
+	}
+	tx.undo(func() {
+		if _, err := u.VirtualAccountRepo.Update(previous); err != nil {
+			log.Printf("rollback virtual account %d: %v", previous.ID, err)
+		}
+	})
+	if _, err := u.VirtualAccountRepo.Update(virtualAccount); err != nil {
+		return model.VirtualAccountPayment{}, err
+	}
+
+	return payment, nil
+}
+
+// ExpireDue implements VirtualAccountUsecase
+func (u *VirtualAccountUsecaseImpl) ExpireDue(now time.Time) error {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+
+	virtualAccounts, err := u.VirtualAccountRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	for _, virtualAccount := range virtualAccounts {
+		if virtualAccount.Status != model.VirtualAccountStatusActive || now.Before(virtualAccount.ExpiresAt) {
+			continue
+		}
+
+		virtualAccount.Status = model.VirtualAccountStatusExpired
+		if _, err := u.VirtualAccountRepo.Update(virtualAccount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverCallbacks implements VirtualAccountUsecase, a callback that still
+// fails after CallbackMaxAttempts is marked failed.
+func (u *VirtualAccountUsecaseImpl) DeliverCallbacks(now time.Time) error {
+	if u.Notifier == nil {
+		return nil
+	}
+
+	u.callbackMu.Lock()
+	defer u.callbackMu.Unlock()
+
+	// Inbound menulis ke repo yang sama di bawah balanceMu, callback dikirim
+	// tanpa lock supaya penerima yang lambat tidak menahan pembayaran masuk
+	balanceMu.Lock()
+	payments, err := u.PaymentRepo.FindAll()
+	balanceMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		if payment.CallbackStatus != model.CallbackStatusPending {
+			continue
+		}
+
+		balanceMu.Lock()
+		virtualAccount, err := u.VirtualAccountRepo.FindById(payment.VirtualAccountID)
+		balanceMu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		payment.CallbackAttempts++
+		payment.CallbackAt = now
+		if err := u.Notifier.NotifyPaid(virtualAccount, payment); err != nil {
+			payment.CallbackError = err.Error()
+			if payment.CallbackAttempts >= u.CallbackMaxAttempts {
+				payment.CallbackStatus = model.CallbackStatusFailed
+			}
+		} else {
+			payment.CallbackError = ""
+			payment.CallbackStatus = model.CallbackStatusDelivered
+		}
+
+		balanceMu.Lock()
+		_, err = u.PaymentRepo.Update(payment)
+		balanceMu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkInbound(virtualAccount model.VirtualAccount, amount float64, now time.Time) error {
+	if virtualAccount.Status != model.VirtualAccountStatusActive {
+		return &VirtualAccountError{
+			Code:    VirtualAccountErrNotActive,
+			Message: fmt.Sprintf("virtual account %s is %s", virtualAccount.Number, virtualAccount.Status),
+		}
+	}
+	if !now.Before(virtualAccount.ExpiresAt) {
+		return &VirtualAccountError{
+			Code:    VirtualAccountErrExpired,
+			Message: fmt.Sprintf("virtual account %s expired at %s", virtualAccount.Number, virtualAccount.ExpiresAt.Format("2006-01-02 15:04")),
+		}
+	}
+	if virtualAccount.Amount > 0 && amount != virtualAccount.Amount {
+		return &VirtualAccountError{
+			Code:    VirtualAccountErrAmountMismatch,
+			Message: fmt.Sprintf("virtual account %s must be paid exactly %.2f", virtualAccount.Number, virtualAccount.Amount),
+		}
+	}
+	return nil
+}
+
+func validateCallbackURL(callbackURL string) error {
+	if callbackURL == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid callback_url: %s", callbackURL)
+	}
+	return nil
+}
+
+// newNumber generates a virtual account number that is not in use yet.
+func (u *VirtualAccountUsecaseImpl) newNumber() (string, error) {
+	for i := 0; i < accountNumberAttempts; i++ {
+		number, err := utils.GenerateVirtualAccountNumber(u.BankCode)
+		if err != nil {
+			return "", err
+		}
+		if _, err := u.VirtualAccountRepo.FindByNumber(number); err != nil {
+			return number, nil
+		}
+	}
+	return "", errors.New("could not generate a unique virtual account number")
+}
+
+// HTTPVirtualAccountNotifier posts the payment as JSON to the callback URL of
+// the virtual account, any status other than 2xx is a failed delivery.
+type HTTPVirtualAccountNotifier struct {
+	Client *http.Client
+}
+
+// NotifyPaid implements VirtualAccountNotifier
+func (n HTTPVirtualAccountNotifier) NotifyPaid(virtualAccount model.VirtualAccount, payment model.VirtualAccountPayment) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"event":           "virtual_account.paid",
+		"virtual_account": virtualAccount,
+		"payment":         payment,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.Client.Post(virtualAccount.CallbackURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback returned %s", resp.Status)
+	}
+	return nil
+}
+
+func NewVirtualAccountUsecaseImpl(VirtualAccountRepo repository.VirtualAccountRepo, PaymentRepo repository.VirtualAccountPaymentRepo, AccRepo repository.AccountRepo, HisRepo repository.HistoryRepo, AuditUsecase AuditUsecase, Notifier VirtualAccountNotifier, BankCode string, DefaultTTL time.Duration, CallbackMaxAttempts int, FrozenBlocksCredits bool, Events EventPublisher, AccountEvents AccountEventRecorder) VirtualAccountUsecase {
+	return &VirtualAccountUsecaseImpl{
+		VirtualAccountRepo:  VirtualAccountRepo,
+		PaymentRepo:         PaymentRepo,
+		AccRepo:             AccRepo,
+		HisRepo:             HisRepo,
+		AuditUsecase:        AuditUsecase,
+		Notifier:            Notifier,
+		BankCode:            BankCode,
+		DefaultTTL:          DefaultTTL,
+		CallbackMaxAttempts: CallbackMaxAttempts,
+		FrozenBlocksCredits: FrozenBlocksCredits,
+		Events:              Events,
+		AccountEvents:       AccountEvents,
+	}
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+)
+
+// WebhookUsecase manages webhook subscriptions and publishes events to them
+// through a persistent delivery queue.
+type WebhookUsecase interface {
+	EventPublisher
+	Save(newSubscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	Update(updatedSubscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	Delete(id int64) (model.WebhookSubscription, error)
+	FindById(id int64) (model.WebhookSubscription, error)
+	FindAll() ([]model.WebhookSubscription, error)
+	FindDeliveries(subscriptionID int64, status string) ([]model.WebhookDelivery, error)
+	RetryDelivery(id int64) (model.WebhookDelivery, error)
+	DeliverDue(now time.Time) error
+}
+
+// WebhookSender posts one signed delivery and returns the response status.
+type WebhookSender interface {
+	Send(url string, secret string, eventType string, deliveryID string, body []byte) (int, error)
+}
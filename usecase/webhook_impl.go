@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sferawann/test_mnc/model"
+	"github.com/sferawann/test_mnc/repository"
+	"github.com/sferawann/test_mnc/webhook"
+)
+
+const minWebhookSecretLength = 16
+
+type WebhookUsecaseImpl struct {
+	SubscriptionRepo repository.WebhookSubscriptionRepo
+	DeliveryRepo     repository.WebhookDeliveryRepo
+	Sender           WebhookSender
+
+	// A delivery is retried after RetryBase, doubling up to RetryMax, and is
+	// dead after MaxAttempts failed attempts
+	MaxAttempts int
+	RetryBase   time.Duration
+	RetryMax    time.Duration
+
+	// mu guards the repositories, deliverMu keeps DeliverDue from running
+	// twice at once
+	mu        sync.Mutex
+	deliverMu sync.Mutex
+}
+
+// Publish implements EventPublisher, the event is queued for every active
+// subscription to its type and delivery starts in the background.
+func (u *WebhookUsecaseImpl) Publish(event model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	queued, err := u.enqueue(event, payload)
+	u.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if queued > 0 {
+		go func() {
+			if err := u.DeliverDue(time.Now()); err != nil {
+				log.Printf("webhook deliveries: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+func (u *WebhookUsecaseImpl) enqueue(event model.Event, payload []byte) (int, error) {
+	subscriptions, err := u.SubscriptionRepo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		if !subscription.Active || !subscription.Subscribed(event.Type) {
+			continue
+		}
+
+		_, err := u.DeliveryRepo.Save(model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         model.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			return queued, err
+		}
+		queued++
+	}
+
+	return queued, nil
+}
+
+// Save implements WebhookUsecase, a secret is generated when none is given.
+func (u *WebhookUsecaseImpl) Save(newSubscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	if newSubscription.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return model.WebhookSubscription{}, err
+		}
+		newSubscription.Secret = secret
+	}
+	if err := validateSubscription(newSubscription); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	newSubscription.ID = 0
+	newSubscription.Active = true
+	newSubscription.CreatedAt = time.Now()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.SubscriptionRepo.Save(newSubscription)
+}
+
+// Update implements WebhookUsecase
+func (u *WebhookUsecaseImpl) Update(updatedSubscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	if err := validateSubscription(updatedSubscription); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	subscription, err := u.SubscriptionRepo.FindById(updatedSubscription.ID)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	subscription.URL = updatedSubscription.URL
+	subscription.Description = updatedSubscription.Description
+	subscription.Events = updatedSubscription.Events
+	subscription.Secret = updatedSubscription.Secret
+	subscription.Active = updatedSubscription.Active
+	return u.SubscriptionRepo.Update(subscription)
+}
+
+// Delete implements WebhookUsecase, deliveries still queued for the
+// subscription are dead-lettered.
+func (u *WebhookUsecaseImpl) Delete(id int64) (model.WebhookSubscription, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	subscription, err := u.SubscriptionRepo.Delete(id)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	deliveries, err := u.DeliveryRepo.FindBySubscriptionId(id)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	for _, delivery := range deliveries {
+		if delivery.Status != model.WebhookDeliveryPending {
+			continue
+		}
+		delivery.Status = model.WebhookDeliveryDead
+		delivery.LastError = "subscription was deleted"
+		if _, err := u.DeliveryRepo.Update(delivery); err != nil {
+			return model.WebhookSubscription{}, err
+		}
+	}
+
+	return subscription, nil
+}
+
+// FindById implements WebhookUsecase
+func (u *WebhookUsecaseImpl) FindById(id int64) (model.WebhookSubscription, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.SubscriptionRepo.FindById(id)
+}
+
+// FindAll implements WebhookUsecase
+func (u *WebhookUsecaseImpl) FindAll() ([]model.WebhookSubscription, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.SubscriptionRepo.FindAll()
+}
+
+// FindDeliveries implements WebhookUsecase, a zero subscriptionID or empty
+// status matches every delivery.
+func (u *WebhookUsecaseImpl) FindDeliveries(subscriptionID int64, status string) ([]model.WebhookDelivery, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var deliveries []model.WebhookDelivery
+	var err error
+	if subscriptionID != 0 {
+		deliveries, err = u.DeliveryRepo.FindBySubscriptionId(subscriptionID)
+	} else {
+		deliveries, err = u.DeliveryRepo.FindAll()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []model.WebhookDelivery
+	for _, delivery := range deliveries {
+		if status == "" || delivery.Status == status {
+			matching = append(matching, delivery)
+		}
+	}
+
+	return matching, nil
+}
+
+// RetryDelivery implements WebhookUsecase, it puts a dead delivery back in
+// the queue with a fresh set of attempts.
+func (u *WebhookUsecaseImpl) RetryDelivery(id int64) (model.WebhookDelivery, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delivery, err := u.DeliveryRepo.FindById(id)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	if delivery.Status != model.WebhookDeliveryDead {
+		return model.WebhookDelivery{}, fmt.Errorf("delivery %d is %s, only dead deliveries can be retried", id, delivery.Status)
+	}
+	if _, err := u.SubscriptionRepo.FindById(delivery.SubscriptionID); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	return u.DeliveryRepo.Update(delivery)
+}
+
+// DeliverDue implements WebhookUsecase. Deliveries are sent without holding
+// mu so a slow subscriber does not hold up publishing.
+func (u *WebhookUsecaseImpl) DeliverDue(now time.Time) error {
+	u.deliverMu.Lock()
+	defer u.deliverMu.Unlock()
+
+	u.mu.Lock()
+	deliveries, err := u.DeliveryRepo.FindAll()
+	u.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.Status != model.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+
+		u.mu.Lock()
+		subscription, err := u.SubscriptionRepo.FindById(delivery.SubscriptionID)
+		u.mu.Unlock()
+		if err != nil {
+			delivery.Status = model.WebhookDeliveryDead
+			delivery.LastError = err.Error()
+		} else if !subscription.Active {
+			continue
+		} else {
+			u.attempt(subscription, &delivery, now)
+		}
+
+		u.mu.Lock()
+		_, err = u.DeliveryRepo.Update(delivery)
+		u.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attempt sends delivery once and schedules the next attempt when it fails.
+func (u *WebhookUsecaseImpl) attempt(subscription model.WebhookSubscription, delivery *model.WebhookDelivery, now time.Time) {
+	delivery.Attempts++
+	status, err := u.Sender.Send(subscription.URL, subscription.Secret, delivery.EventType, strconv.FormatInt(delivery.ID, 10), delivery.Payload)
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = model.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= u.MaxAttempts {
+		delivery.Status = model.WebhookDeliveryDead
+		return
+	}
+	delivery.NextAttemptAt = now.Add(webhook.Backoff(delivery.Attempts, u.RetryBase, u.RetryMax))
+}
+
+func validateSubscription(subscription model.WebhookSubscription) error {
+	if subscription.URL == "" {
+		return errors.New("url is required")
+	}
+	if err := validateCallbackURL(subscription.URL); err != nil {
+		return fmt.Errorf("invalid url: %s", subscription.URL)
+	}
+	if len(subscription.Secret) < minWebhookSecretLength {
+		return fmt.Errorf("secret must have at least %d characters", minWebhookSecretLength)
+	}
+	if len(subscription.Events) == 0 {
+		return errors.New("events is required")
+	}
+
+	for _, event := range subscription.Events {
+		if event == model.WebhookAllEvents {
+			continue
+		}
+		known := false
+		for _, eventType := range model.EventTypes {
+			if event == eventType {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown event type: %s", event)
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func NewWebhookUsecaseImpl(SubscriptionRepo repository.WebhookSubscriptionRepo, DeliveryRepo repository.WebhookDeliveryRepo, Sender WebhookSender, MaxAttempts int, RetryBase time.Duration, RetryMax time.Duration) WebhookUsecase {
+	return &WebhookUsecaseImpl{
+		SubscriptionRepo: SubscriptionRepo,
+		DeliveryRepo:     DeliveryRepo,
+		Sender:           Sender,
+		MaxAttempts:      MaxAttempts,
+		RetryBase:        RetryBase,
+		RetryMax:         RetryMax,
+	}
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+const (
+	bankCodeLength   = 3
+	branchCodeLength = 3
+	serialLength     = 8
+
+	// AccountNumberLength is bank code + branch + serial + 2 check digits.
+	AccountNumberLength = bankCodeLength + branchCodeLength + serialLength + 2
+
+	virtualAccountPrefix       = "88"
+	virtualAccountSerialLength = 10
+
+	// VirtualAccountNumberLength is bank code + "88" + serial + 2 check
+	// digits, one digit longer than account numbers so the two never collide.
+	VirtualAccountNumberLength = bankCodeLength + len(virtualAccountPrefix) + virtualAccountSerialLength + 2
+)
+
+// GenerateAccountNumber builds an account number from bankCode, branchCode
+// and a random serial, followed by ISO 7064 MOD 97-10 check digits. The
+// serial is random so numbers can not be guessed from each other.
+func GenerateAccountNumber(bankCode string, branchCode string) (string, error) {
+	if err := validateDigits("bank code", bankCode, bankCodeLength); err != nil {
+		return "", err
+	}
+	if err := validateDigits("branch code", branchCode, branchCodeLength); err != nil {
+		return "", err
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(100000000))
+	if err != nil {
+		return "", err
+	}
+
+	base := fmt.Sprintf("%s%s%08d", bankCode, branchCode, serial.Int64())
+	return base + checkDigits(base), nil
+}
+
+// ValidateAccountNumber checks the length, characters and check digits of an
+// account number.
+func ValidateAccountNumber(number string) error {
+	if err := validateDigits("account number", number, AccountNumberLength); err != nil {
+		return err
+	}
+	if mod97(number) != 1 {
+		return errors.New("invalid account number check digits")
+	}
+	return nil
+}
+
+// GenerateVirtualAccountNumber builds a virtual account number from bankCode
+// and a random serial with the same check digits as account numbers.
+func GenerateVirtualAccountNumber(bankCode string) (string, error) {
+	if err := validateDigits("bank code", bankCode, bankCodeLength); err != nil {
+		return "", err
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(10000000000))
+	if err != nil {
+		return "", err
+	}
+
+	base := fmt.Sprintf("%s%s%010d", bankCode, virtualAccountPrefix, serial.Int64())
+	return base + checkDigits(base), nil
+}
+
+// ValidateVirtualAccountNumber checks the length, prefix and check digits of
+// a virtual account number.
+func ValidateVirtualAccountNumber(number string) error {
+	if err := validateDigits("virtual account number", number, VirtualAccountNumberLength); err != nil {
+		return err
+	}
+	if number[bankCodeLength:bankCodeLength+len(virtualAccountPrefix)] != virtualAccountPrefix {
+		return errors.New("not a virtual account number")
+	}
+	if mod97(number) != 1 {
+		return errors.New("invalid virtual account number check digits")
+	}
+	return nil
+}
+
+// checkDigits returns the two digits that make base+digits ≡ 1 (mod 97).
+func checkDigits(base string) string {
+	return fmt.Sprintf("%02d", 98-mod97(base+"00"))
+}
+
+// mod97 computes number mod 97 piecewise, the number does not fit an int64.
+func mod97(number string) int {
+	remainder := 0
+	for _, r := range number {
+		remainder = (remainder*10 + int(r-'0')) % 97
+	}
+	return remainder
+}
+
+func validateDigits(name string, value string, length int) error {
+	if len(value) != length {
+		return fmt.Errorf("%s must be %d digits", name, length)
+	}
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		return fmt.Errorf("%s must only contain digits", name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+// ParsePeriod parses an inclusive from and to date (YYYY-MM-DD) and returns
+// the range as [from, to). Empty values default to the current month up to
+// today.
+func ParsePeriod(fromParam string, toParam string) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	var err error
+	if fromParam != "" {
+		from, err = time.ParseInLocation("2006-01-02", fromParam, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be formatted as YYYY-MM-DD")
+		}
+	}
+	if toParam != "" {
+		to, err = time.ParseInLocation("2006-01-02", toParam, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be formatted as YYYY-MM-DD")
+		}
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// ParseInstant parses an RFC3339 timestamp or a date (YYYY-MM-DD). A date
+// stands for the end of that day, the start of the next one. An empty value
+// is now.
+func ParseInstant(param string) (time.Time, error) {
+	if param == "" {
+		return time.Now(), nil
+	}
+	if at, err := time.Parse(time.RFC3339, param); err == nil {
+		return at, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", param, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("at must be an RFC3339 timestamp or formatted as YYYY-MM-DD")
+	}
+	return day.AddDate(0, 0, 1), nil
+}
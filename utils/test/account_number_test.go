@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/sferawann/test_mnc/utils"
+)
+
+func TestGenerateAccountNumber(t *testing.T) {
+	number, err := utils.GenerateAccountNumber("485", "001")
+	if err != nil {
+		t.Fatalf("failed to generate account number: %v", err)
+	}
+	if len(number) != utils.AccountNumberLength || number[:6] != "485001" {
+		t.Errorf("incorrect account number: %s", number)
+	}
+	if err := utils.ValidateAccountNumber(number); err != nil {
+		t.Errorf("generated account number is invalid: %v", err)
+	}
+
+	if _, err := utils.GenerateAccountNumber("48", "001"); err == nil {
+		t.Error("expected error for short bank code")
+	}
+}
+
+func TestValidateAccountNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		valid  bool
+	}{
+		{"4850010000123433", true},
+		{"4850010000123434", false}, // check digit salah
+		{"4850010000213433", false}, // dua digit tertukar
+		{"485001000012343", false},
+		{"48500100001234a3", false},
+	}
+
+	for _, tt := range tests {
+		err := utils.ValidateAccountNumber(tt.number)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateAccountNumber(%s): got error %v, want valid %v", tt.number, err, tt.valid)
+		}
+	}
+}
+
+func TestGenerateVirtualAccountNumber(t *testing.T) {
+	number, err := utils.GenerateVirtualAccountNumber("485")
+	if err != nil {
+		t.Fatalf("failed to generate virtual account number: %v", err)
+	}
+	if len(number) != utils.VirtualAccountNumberLength || number[:5] != "48588" {
+		t.Errorf("incorrect virtual account number: %s", number)
+	}
+	if err := utils.ValidateVirtualAccountNumber(number); err != nil {
+		t.Errorf("generated virtual account number is invalid: %v", err)
+	}
+	if err := utils.ValidateAccountNumber(number); err == nil {
+		t.Errorf("virtual account number %s must not be a valid account number", number)
+	}
+}
@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Sender posts signed deliveries to subscriber URLs.
+type Sender struct {
+	Client *http.Client
+}
+
+// Send posts body to url and returns the response status, any status other
+// than 2xx is returned as an error as well.
+func (s Sender) Send(url string, secret string, eventType string, deliveryID string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now().Unix(), body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff is the wait before the next attempt after attempt failed ones,
+// doubling from base up to max.
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Sign returns the signature header of body, an HMAC-SHA256 over the
+// timestamp and the body so a captured delivery can not be replayed later.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, signature(secret, timestamp, body))
+}
+
+// Verify checks a signature header made by Sign. Signatures older than
+// tolerance are rejected, a zero tolerance accepts any age.
+func Verify(secret string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errors.New("invalid signature timestamp")
+			}
+			timestamp = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return errors.New("malformed signature header")
+	}
+
+	if tolerance > 0 && now.Sub(time.Unix(timestamp, 0)) > tolerance {
+		return errors.New("signature is too old")
+	}
+
+	expected := signature(secret, timestamp, body)
+	for _, candidate := range signatures {
+		if hmac.Equal([]byte(candidate), []byte(expected)) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match")
+}
+
+func signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sferawann/test_mnc/webhook"
+)
+
+const testSecret = "whsec_0123456789abcdef"
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"type":"transfer.completed"}`)
+	now := time.Now()
+	header := webhook.Sign(testSecret, now.Unix(), body)
+
+	if err := webhook.Verify(testSecret, header, body, now, 5*time.Minute); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := webhook.Verify("whsec_another_secret", header, body, now, 5*time.Minute); err == nil {
+		t.Error("expected signature with another secret to be rejected")
+	}
+	if err := webhook.Verify(testSecret, header, []byte(`{"type":"transfer.reversed"}`), now, 5*time.Minute); err == nil {
+		t.Error("expected signature of a changed body to be rejected")
+	}
+	if err := webhook.Verify(testSecret, header, body, now.Add(10*time.Minute), 5*time.Minute); err == nil {
+		t.Error("expected an old signature to be rejected")
+	}
+	if err := webhook.Verify(testSecret, "v1=abc", body, now, 0); err == nil {
+		t.Error("expected a header without timestamp to be rejected")
+	}
+}
+
+func TestSendSignedDelivery(t *testing.T) {
+	var received http.Header
+	var receivedBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	sender := webhook.Sender{Client: receiver.Client()}
+	body := []byte(`{"id":"evt_1","type":"account.balance_changed"}`)
+	status, err := sender.Send(receiver.URL, testSecret, "account.balance_changed", "7", body)
+	if err != nil {
+		t.Fatalf("failed to send delivery: %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("incorrect status: got %d, want %d", status, http.StatusNoContent)
+	}
+
+	if received.Get(webhook.EventHeader) != "account.balance_changed" || received.Get(webhook.DeliveryHeader) != "7" {
+		t.Errorf("incorrect delivery headers: %v", received)
+	}
+	if err := webhook.Verify(testSecret, received.Get(webhook.SignatureHeader), receivedBody, time.Now(), time.Minute); err != nil {
+		t.Errorf("receiver could not verify signature: %v", err)
+	}
+}
+
+func TestSendFailedDelivery(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	sender := webhook.Sender{Client: receiver.Client()}
+	status, err := sender.Send(receiver.URL, testSecret, "transfer.completed", "1", []byte(`{}`))
+	if err == nil {
+		t.Fatal("expected an error for a 503 response")
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("incorrect status: got %d, want %d", status, http.StatusServiceUnavailable)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := webhook.Backoff(tt.attempt, 30*time.Second, time.Hour); got != tt.want {
+			t.Errorf("Backoff(%d): got %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}